@@ -13,6 +13,9 @@ printUsage() {
 # Set the port used to communicate with the server
 export PORT="8000"
 
+# The API key is read from the environment, issue one with the crawl role through "aletheia-api apikey create"
+export API_KEY="${API_KEY:-}"
+
 # Build the client application
 go build -v -o client src/cmd/main.go
 
@@ -9,4 +9,5 @@ const (
 	UninitializedPrompt = "the context field was not initialized and will not be displayed"
 	UninitializedImage  = "the image field was not initialized and will not be displayed"
 	UninitializedVideo  = "the video field was not initialized and will not be displayed"
+	UninitializedApiKey = "the API key was not initialized, the API will reject the requests unless its authentication is disabled"
 )
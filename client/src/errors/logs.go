@@ -1,30 +1,102 @@
 package client_errors
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
 )
 
 const (
-	ErrorLevel   = "error"
+	DebugLevel   = "debug"
 	InfoLevel    = "info"
 	WarningLevel = "warning"
+	ErrorLevel   = "error"
 )
 
+// Formats the logs can be written in.
 const (
-	defaultColor = "\033[0m"
-	errorColor   = "\033[91m"
-	infoColor    = "\033[0;36m"
-	warningColor = "\033[93m"
+	TextFormat = "text"
+	JSONFormat = "json"
 )
 
-func Log(message string, level string) {
-	switch level {
-	case InfoLevel:
-		log.Println(fmt.Sprintf("%sinfo: %s %s", infoColor, message, defaultColor))
-	case WarningLevel:
-		log.Println(fmt.Sprintf("%swarning: %s %s", warningColor, message, defaultColor))
-	case ErrorLevel:
-		log.Println(fmt.Sprintf("%serror: %s %s", errorColor, message, defaultColor))
+// RequestIdKey : key of the correlation id shared with the server for each request
+const RequestIdKey = "request_id"
+
+var slogLevels = map[string]slog.Level{
+	DebugLevel:   slog.LevelDebug,
+	InfoLevel:    slog.LevelInfo,
+	WarningLevel: slog.LevelWarn,
+	ErrorLevel:   slog.LevelError,
+}
+
+var logger = newLogger(loadLogSettings())
+
+// SetupLogger :
+// Replaces the logger of the client by one writing to w with the provided level and format.
+func SetupLogger(level string, format string, w io.Writer) {
+	logger = newLogger(level, format, w)
+}
+
+// Log :
+// Writes a message at the provided level, along with the provided key-value pairs. Unknown levels are logged as info.
+func Log(message string, level string, args ...any) {
+	logger.Log(context.Background(), levelOf(level), message, args...)
+}
+
+// NewRequestId :
+// Generates a random identifier sent along a request, so its logs can be matched with the ones of the server.
+func NewRequestId() string {
+	id := make([]byte, 8)
+
+	if _, err := rand.Read(id); err != nil {
+		return "unknown"
+	}
+
+	return hex.EncodeToString(id)
+}
+
+// loadLogSettings :
+// Reads the log settings from the LOG_LEVEL and LOG_FORMAT environment variables. Without LOG_LEVEL, the level is
+// "debug" when DEBUG is true and "info" otherwise.
+func loadLogSettings() (string, string, io.Writer) {
+	level := InfoLevel
+	if debug, err := strconv.ParseBool(os.Getenv("DEBUG")); err == nil && debug {
+		level = DebugLevel
 	}
+
+	if value := strings.ToLower(os.Getenv("LOG_LEVEL")); value != "" {
+		if _, ok := slogLevels[value]; ok {
+			level = value
+		}
+	}
+
+	format := TextFormat
+	if strings.ToLower(os.Getenv("LOG_FORMAT")) == JSONFormat {
+		format = JSONFormat
+	}
+
+	return level, format, os.Stderr
+}
+
+func newLogger(level string, format string, w io.Writer) *slog.Logger {
+	options := &slog.HandlerOptions{Level: levelOf(level)}
+
+	if format == JSONFormat {
+		return slog.New(slog.NewJSONHandler(w, options))
+	}
+
+	return slog.New(slog.NewTextHandler(w, options))
+}
+
+func levelOf(level string) slog.Level {
+	if slogLevel, ok := slogLevels[level]; ok {
+		return slogLevel
+	}
+
+	return slog.LevelInfo
 }
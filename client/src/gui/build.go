@@ -11,7 +11,18 @@ import (
 	"fyne.io/fyne/v2/widget"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
+)
+
+const (
+	// pagesToVisit : article pages each news outlet contributes to a crawl started from the GUI
+	pagesToVisit = 5
+	// pollInterval : how often a running crawl job is polled
+	pollInterval = 2 * time.Second
+	// pollTimeout : how long a crawl job is polled before giving up on it
+	pollTimeout = 10 * time.Minute
 )
 
 var PostUrl string = ""
@@ -55,7 +66,8 @@ func buildFields(config models.Config) fyne.CanvasObject {
 		nil, nil, nil,
 		widget.NewButton("Send", func() {
 			Prompt = promptEntry.Text
-			sendPackage(config)
+			// The crawl is polled until it halts, which must not freeze the window
+			go sendPackage(config)
 		}),
 	)
 }
@@ -122,42 +134,96 @@ func buildCheckField(labelText string) fyne.CanvasObject {
 	)
 }
 
+// sendPackage :
+// Starts a crawl job looking for the prompt, then polls it until it halts and shows its outcome. It blocks until then,
+// so it is meant to run in its own goroutine.
 func sendPackage(config models.Config) {
-	requestBody := map[string]interface{}{
-		"query":        Prompt,
-		"pagesToVisit": 5,
-	}
+	requestId := client_errors.NewRequestId()
+	showAnswer("Starting the crawl...")
 
-	bodyJson, err := json.Marshal(requestBody)
+	var job models.CrawlJob
+	err := callApi(config, http.MethodPost, "/crawl", models.CrawlRequest{Query: Prompt, PagesToVisit: pagesToVisit},
+		requestId, http.StatusAccepted, &job)
 	if err != nil {
-		client_errors.Log("Failed to encode request body: "+err.Error(), client_errors.ErrorLevel)
-		answerBox.SetText("Error preparing request.")
-		answerBox.Show()
+		client_errors.Log("Starting the crawl failed: "+err.Error(), client_errors.ErrorLevel, client_errors.RequestIdKey, requestId)
+		showAnswer("Error: " + err.Error())
 		return
 	}
 
-	// Log the package being sent
-	client_errors.Log("Sending JSON to server:\n"+string(bodyJson), client_errors.InfoLevel)
+	deadline := time.Now().Add(pollTimeout)
+	for !job.Halted() {
+		if time.Now().After(deadline) {
+			client_errors.Log("Gave up waiting for crawl job "+job.Id, client_errors.WarningLevel, client_errors.RequestIdKey, requestId)
+			showAnswer("The crawl is taking too long, it is still running on the server as job " + job.Id + ".")
+			return
+		}
+
+		showAnswer(job.Summary())
+		time.Sleep(pollInterval)
+
+		err = callApi(config, http.MethodGet, "/crawl/"+url.PathEscape(job.Id), nil, requestId, http.StatusOK, &job)
+		if err != nil {
+			client_errors.Log("Polling the crawl failed: "+err.Error(), client_errors.ErrorLevel, client_errors.RequestIdKey, requestId)
+			showAnswer("Error: " + err.Error())
+			return
+		}
+	}
+
+	showAnswer(job.Summary())
+}
+
+// callApi :
+// Sends a request to the API, with body encoded as JSON when it is not nil, and decodes the response into out.
+// Fails if the response does not have the expected status.
+func callApi(config models.Config, method string, path string, body any, requestId string, expected int, out any) error {
+	var reader io.Reader
+	if body != nil {
+		bodyJson, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding the request: %w", err)
+		}
+
+		client_errors.Log("Sending JSON to server:\n"+string(bodyJson), client_errors.DebugLevel, client_errors.RequestIdKey, requestId)
+		reader = bytes.NewReader(bodyJson)
+	}
 
-	apiURL := "http://localhost:" + config.Port + "/crawl"
-	resp, err := http.Post(apiURL, "application/json", bytes.NewReader(bodyJson))
+	req, err := http.NewRequest(method, "http://localhost:"+config.Port+path, reader)
 	if err != nil {
-		client_errors.Log("Request failed: "+err.Error(), client_errors.ErrorLevel)
-		answerBox.SetText("Error: " + err.Error())
-		answerBox.Show()
-		return
+		return fmt.Errorf("building the request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-Id", requestId)
+	if config.ApiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+config.ApiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		client_errors.Log("Reading response failed: "+err.Error(), client_errors.ErrorLevel)
-		answerBox.SetText("Error reading response.")
-		answerBox.Show()
-		return
+		return fmt.Errorf("reading the response: %w", err)
 	}
 
-	client_errors.Log("Received response: "+string(respBody), client_errors.InfoLevel)
-	answerBox.SetText(string(respBody))
+	client_errors.Log("Received response: "+string(respBody), client_errors.DebugLevel, client_errors.RequestIdKey, requestId)
+
+	if resp.StatusCode != expected {
+		return fmt.Errorf("the server answered %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	if err = json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("decoding the response: %w", err)
+	}
+
+	return nil
+}
+
+// showAnswer :
+// Displays the text in the answer box.
+func showAnswer(text string) {
+	answerBox.SetText(text)
 	answerBox.Show()
 }
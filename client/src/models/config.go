@@ -14,6 +14,8 @@ type Config struct {
 	Image  bool   `json:"image"`
 	Video  bool   `json:"video"`
 	Prompt bool   `json:"prompt"`
+	// ApiKey : key authenticating the requests to the API, which needs the crawl role
+	ApiKey string `json:"-"`
 }
 
 const (
@@ -21,13 +23,14 @@ const (
 	Prompt = "PROMPT"
 	Image  = "IMAGE"
 	Video  = "VIDEO"
+	ApiKey = "API_KEY"
 )
 
 // NewConfig :
 // Returns an instance of a Config struct, used to configure the GUI for the client application.
 // Will fail if the "PORT" environment variable is not initialized as a valid integer.
 func NewConfig() (Config, error) {
-	config := Config{"", false, false, false}
+	config := Config{}
 
 	// Get the PORT value from the environment variables
 	err := getPort(&config)
@@ -68,6 +71,8 @@ func NewConfig() (Config, error) {
 		return Config{}, err
 	}
 
+	config.ApiKey = os.Getenv(ApiKey)
+
 	warnMissingFields(config)
 
 	return config, nil
@@ -104,4 +109,7 @@ func warnMissingFields(config Config) {
 	if !config.Video {
 		client_errors.Log(client_errors.UninitializedVideo, client_errors.WarningLevel)
 	}
+	if config.ApiKey == "" {
+		client_errors.Log(client_errors.UninitializedApiKey, client_errors.WarningLevel)
+	}
 }
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Statuses of a crawl job that has not halted yet, as reported by the API.
+const (
+	CrawlJobQueued  = "crawl job is queued"
+	CrawlJobRunning = "crawl job is running"
+)
+
+// CrawlRequest :
+// Body of the request starting a crawl job.
+type CrawlRequest struct {
+	Query        string `json:"query"`
+	PagesToVisit int    `json:"pagesToVisit"`
+}
+
+// CrawlJob :
+// Crawl job returned by the API when it is started and every time it is polled. Result is only set once it halted.
+type CrawlJob struct {
+	Id     string       `json:"id"`
+	Status string       `json:"status"`
+	Query  string       `json:"query"`
+	Result *CrawlResult `json:"result,omitempty"`
+}
+
+type CrawlResult struct {
+	DurationMs int64           `json:"durationMs"`
+	Crawlers   []CrawlerResult `json:"crawlers"`
+}
+
+type CrawlerResult struct {
+	NewsOutlet string    `json:"newsOutlet"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Articles   []Article `json:"articles"`
+}
+
+type Article struct {
+	Url          string `json:"url"`
+	CanonicalUrl string `json:"canonicalUrl"`
+	Title        string `json:"title"`
+}
+
+// Halted :
+// Reports whether the crawl job stopped, whatever its outcome.
+func (cj CrawlJob) Halted() bool {
+	return cj.Status != CrawlJobQueued && cj.Status != CrawlJobRunning
+}
+
+// Summary :
+// Returns the outcome of the crawl job as text: its status, then the articles found on each news outlet, or why none
+// were.
+func (cj CrawlJob) Summary() string {
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "%s: %s\n", cj.Query, cj.Status)
+
+	if cj.Result == nil {
+		return summary.String()
+	}
+
+	for _, crawler := range cj.Result.Crawlers {
+		fmt.Fprintf(&summary, "\n%s (%s)\n", crawler.NewsOutlet, crawler.Status)
+
+		if crawler.Error != "" {
+			fmt.Fprintf(&summary, "  %s\n", crawler.Error)
+		}
+
+		for _, article := range crawler.Articles {
+			url := article.CanonicalUrl
+			if url == "" {
+				url = article.Url
+			}
+			fmt.Fprintf(&summary, "  - %s\n    %s\n", article.Title, url)
+		}
+	}
+
+	return summary.String()
+}
@@ -3,7 +3,9 @@ package client_errors_test
 import (
 	"aletheia-client/src/errors"
 	"bytes"
-	"log"
+	"encoding/json"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -15,47 +17,69 @@ func TestLogLevels(t *testing.T) {
 		level    string
 		expected string
 	}{
+		{
+			name:     "Debug level",
+			message:  "test debug message",
+			level:    client_errors.DebugLevel,
+			expected: "DEBUG",
+		},
 		{
 			name:     "Info level",
 			message:  "test info message",
 			level:    client_errors.InfoLevel,
-			expected: "\033[0;36minfo: test info message \033[0m",
+			expected: "INFO",
 		},
 		{
 			name:     "Warning level",
 			message:  "test warning message",
 			level:    client_errors.WarningLevel,
-			expected: "\033[93mwarning: test warning message \033[0m",
+			expected: "WARN",
 		},
 		{
 			name:     "Error level",
 			message:  "test error message",
 			level:    client_errors.ErrorLevel,
-			expected: "\033[91merror: test error message \033[0m",
+			expected: "ERROR",
 		},
 	}
 
-	// Redirect log output
-	old := log.Writer()
-	defer log.SetOutput(old)
+	var buf bytes.Buffer
+	client_errors.SetupLogger(client_errors.DebugLevel, client_errors.JSONFormat, &buf)
+	defer client_errors.SetupLogger(client_errors.InfoLevel, client_errors.TextFormat, os.Stderr)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var buf bytes.Buffer
-			log.SetOutput(&buf)
+			buf.Reset()
 
-			client_errors.Log(tt.message, tt.level)
+			client_errors.Log(tt.message, tt.level, client_errors.RequestIdKey, "abc123")
 
-			// Remove timestamp from log output
-			output := buf.String()[20:] // Skip timestamp (len "2009/11/10 23:00:00 ")
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("Expected a JSON record, got %q: %v", buf.String(), err)
+			}
 
-			if output != tt.expected+"\n" {
-				t.Errorf("Expected %q, got %q", tt.expected, output)
+			if record["msg"] != tt.message || record["level"] != tt.expected || record[client_errors.RequestIdKey] != "abc123" {
+				t.Errorf("Expected msg %q at level %q with request id, got %v", tt.message, tt.expected, record)
 			}
 		})
 	}
 }
 
+// TestLogFiltersLevel verifies messages below the configured level are dropped
+func TestLogFiltersLevel(t *testing.T) {
+	var buf bytes.Buffer
+	client_errors.SetupLogger(client_errors.WarningLevel, client_errors.TextFormat, &buf)
+	defer client_errors.SetupLogger(client_errors.InfoLevel, client_errors.TextFormat, os.Stderr)
+
+	client_errors.Log("dropped", client_errors.InfoLevel)
+	client_errors.Log("kept", client_errors.ErrorLevel)
+
+	output := buf.String()
+	if strings.Contains(output, "dropped") || !strings.Contains(output, "level=ERROR msg=kept") {
+		t.Errorf("Unexpected output %q", output)
+	}
+}
+
 // TestConstants verifies constant values
 func TestConstants(t *testing.T) {
 	tests := []struct {
@@ -63,6 +87,7 @@ func TestConstants(t *testing.T) {
 		actual   string
 		expected string
 	}{
+		{"DebugLevel", client_errors.DebugLevel, "debug"},
 		{"InfoLevel", client_errors.InfoLevel, "info"},
 		{"WarningLevel", client_errors.WarningLevel, "warning"},
 		{"ErrorLevel", client_errors.ErrorLevel, "error"},
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"aletheia-client/src/models"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestCrawlJobHalted tests that only the queued and running jobs are polled again
+func TestCrawlJobHalted(t *testing.T) {
+	tests := []struct {
+		status   string
+		expected bool
+	}{
+		{models.CrawlJobQueued, false},
+		{models.CrawlJobRunning, false},
+		{"crawl job finished", true},
+		{"crawl job was cancelled", true},
+		{"crawl job failed", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if halted := (models.CrawlJob{Status: tt.status}).Halted(); halted != tt.expected {
+				t.Errorf("Expected Halted to be %v, got %v", tt.expected, halted)
+			}
+		})
+	}
+}
+
+// TestCrawlJobSummary tests that the result of a job polled from the API is rendered per news outlet
+func TestCrawlJobSummary(t *testing.T) {
+	body := `{
+		"id": "5f0c3d0e",
+		"status": "crawl job finished",
+		"query": "tax cut",
+		"result": {
+			"durationMs": 1200,
+			"crawlers": [
+				{
+					"newsOutlet": "daily herald",
+					"status": "crawler successfully crawled",
+					"articles": [
+						{"url": "https://herald.example/a?utm_source=x", "canonicalUrl": "https://herald.example/a", "title": "Tax cut approved"}
+					]
+				},
+				{"newsOutlet": "the ledger", "status": "crawler failed", "error": "unable to fetch URL", "articles": []}
+			]
+		}
+	}`
+
+	var job models.CrawlJob
+	if err := json.Unmarshal([]byte(body), &job); err != nil {
+		t.Fatalf("Failed to decode the job: %v", err)
+	}
+
+	if !job.Halted() {
+		t.Fatalf("Expected the job to be halted")
+	}
+
+	summary := job.Summary()
+	for _, expected := range []string{
+		"tax cut: crawl job finished",
+		"daily herald (crawler successfully crawled)",
+		"Tax cut approved\n    https://herald.example/a\n",
+		"the ledger (crawler failed)\n  unable to fetch URL",
+	} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("Expected the summary to contain %q, got:\n%s", expected, summary)
+		}
+	}
+}
+
+// TestCrawlJobSummaryWhileRunning tests that a job without result only shows its status
+func TestCrawlJobSummaryWhileRunning(t *testing.T) {
+	job := models.CrawlJob{Query: "tax cut", Status: models.CrawlJobRunning}
+
+	if summary := job.Summary(); summary != "tax cut: crawl job is running\n" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+}
@@ -19,6 +19,7 @@ CLEAR_DB=false
 DEBUG=false
 SETUP=false
 RESET_IMAGES=false
+MOCK_ANALYZER=false
 
 printUsage() {
   echo "Usage: run.sh [OPTIONS]"
@@ -36,6 +37,7 @@ printUsage() {
   echo -e "  --DEBUG \t\tEnable debug mode (Delve debugger)"
   echo "AI Analyzer Configuration:"
   echo -e "  --AI_PORT=\t\tPort for AI service (default: 7654)"
+  echo -e "  -M --MOCK_ANALYZER \tRuns the mock analyzer instead of the Python one, no Ollama needed"
   echo "Miscellaneous:"
   echo -e "  -h --HELP \t\tShows this help message"
 }
@@ -68,28 +70,59 @@ clearImages() {
   done
 }
 
+# Sends a JSON body to an admin endpoint of the API with the setup key, failing on any status but 2xx
+postSetup() {
+  local STATUS
+  STATUS=$(curl -s -o /tmp/aletheia-setup.out -w "%{http_code}" \
+    --header "Content-Type: application/json" \
+    --header "Authorization: Bearer $SETUP_KEY" \
+    --request POST \
+    --data "$2" \
+    "http://localhost:$SERVER_PORT/$1")
+
+  if [[ "$STATUS" != 2* ]]; then
+    echo -e "${ERROR}POST /$1 failed with status $STATUS:${NC}" >&2
+    cat /tmp/aletheia-setup.out >&2
+    echo >&2
+    return 1
+  fi
+}
+
 setupDatabase() {
   sleep 5
 
+  # Authentication is on by default, so an admin key is issued for the setup and revoked once it is done
+  local ISSUED SETUP_KEY_ID
+  if ! ISSUED=$(podman exec aletheia-api /aletheia-api apikey create setup admin); then
+    echo -e "${ERROR}Failed to issue an API key for the setup${NC}" >&2
+    exit 1
+  fi
+  SETUP_KEY=$(echo "$ISSUED" | tail -n 1)
+  SETUP_KEY_ID=$(echo "$ISSUED" | head -n 1 | sed -E 's/^API key ([0-9]+) .*/\1/')
+
   # Add Portuguese to Languages table
-  _=$(curl -s \
-    --header "Content-Type: application/json" \
-    --request POST \
-    --data '{"name":"portuguese"}' \
-    http://localhost:8000/language)
+  postSetup language '{"name":"portuguese"}' || SETUP_FAILED=true
 
   # Add G1 to NewsOutlets table
-  _=$(curl -s \
-    --header "Content-Type: application/json" \
-    --request POST \
-    --data '{
+  if [[ -z "$SETUP_FAILED" ]]; then
+    postSetup newsOutlet '{
         "name": "G1",
         "queryUrl": "https://g1.globo.com/busca/?q=QUERY_HERE&page=1&order=recent&species=not%C3%ADcias&from=now-1M",
         "htmlSelector": "li.widget > div > a",
         "language": "portuguese",
         "credibility": 100
-    }' \
-    http://localhost:8000/newsOutlet)
+    }' || SETUP_FAILED=true
+  fi
+
+  rm -f /tmp/aletheia-setup.out
+  if ! podman exec aletheia-api /aletheia-api apikey revoke "$SETUP_KEY_ID" > /dev/null; then
+    echo -e "${WARNING}Failed to revoke the setup API key $SETUP_KEY_ID, revoke it by hand${NC}" >&2
+  fi
+
+  if [[ -n "$SETUP_FAILED" ]]; then
+    echo -e "${ERROR}Failed to set up the database${NC}" >&2
+    exit 1
+  fi
 }
 
 # Cleanup any existing containers
@@ -111,6 +144,10 @@ while [[ $# -gt 0 ]]; do
     -S|--SETUP)
       SETUP=true
       ;;
+    -M|--MOCK_ANALYZER)
+      MOCK_ANALYZER=true
+      echo -e "${INFO}Using the mock AI analyzer${NC}"
+      ;;
     --SERVER_PORT=*)
       SERVER_PORT="${1#*=}"
       echo -e "${INFO}Using custom API port: $SERVER_PORT${NC}"
@@ -168,9 +205,15 @@ fi
 # Export environment variables
 export DB_HOST DB_PORT DB_USER DB_PASSWORD DB_NAME SERVER_PORT AI_PORT DEBUG
 
+# The mock analyzer is layered over the Python one, taking its place in the stack
+COMPOSE_FILES=(-f docker-compose.yml)
+if $MOCK_ANALYZER; then
+  COMPOSE_FILES+=(-f docker-compose.mock-analyzer.yml)
+fi
+
 # Start the services
 echo -e "${INFO}Starting services with podman-compose...${NC}"
-if ! podman-compose up -d; then
+if ! podman-compose "${COMPOSE_FILES[@]}" up -d; then
   echo -e "${ERROR}Failed to start services with podman-compose${NC}" >&2
   echo -e "${WARNING}Please check your podman and podman-compose installation and try again.${NC}"
   exit 1
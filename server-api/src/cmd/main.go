@@ -1,43 +1,189 @@
 package main
 
 import (
+	"aletheia-server/src/config"
 	"aletheia-server/src/controllers"
 	"aletheia-server/src/db"
 	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
 	"aletheia-server/src/repositories"
 	"aletheia-server/src/usecases"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// readHeaderTimeout : how long a client may take to send the headers of its request
+const readHeaderTimeout = 10 * time.Second
+
 func main() {
-	// Initialize the Database server
-	dbConnection, err := db.ConnectDB()
+	if err := run(); err != nil {
+		server_errors.Log(err.Error(), server_errors.ErrorLevel)
+		os.Exit(1)
+	}
+}
+
+// run :
+// Runs the API server, or the subcommand given in the arguments, until it stops. It returns instead of exiting, so
+// every deferred cleanup runs, the database connection being closed cleanly even when it fails.
+func run() error {
+	// Reading the configuration, the arguments left after the flags being the subcommand
+	cfg, args, err := config.Load(os.Args[1:])
+
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return
+		return err
+	}
+
+	// Initialize the logger before anything else logs
+	server_errors.SetupLogger(cfg.LogConfig(), os.Stderr)
+
+	// The "config" subcommand prints the configuration and exits without connecting to anything
+	if len(args) > 0 && args[0] == "config" {
+		return printConfig(cfg, args[1:])
+	}
+
+	// Stopping on SIGINT or SIGTERM, which also aborts waiting for the database
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Initialize the Database server, retrying until it answers
+	dbConnection, err := db.Connect(ctx, cfg.DBConfig())
+
+	if err != nil {
+		return err
+	}
+	defer dbConnection.Close()
+
+	// The "migrate" subcommand manages the database schema and exits without starting the API server
+	if len(args) > 0 && args[0] == "migrate" {
+		return migrate(dbConnection, cfg.Database.Driver, args[1:])
+	}
+
+	// Bringing the database schema up to date before serving any request
+	if err = migrate(dbConnection, cfg.Database.Driver, []string{"up"}); err != nil {
+		return err
+	}
+
+	// Every repository is backed by the storage driver of the configuration
+	storage := repositories.NewPostgresStorage(dbConnection)
+	if cfg.Database.Driver == db.SqliteDriver {
+		storage = repositories.NewSqliteStorage(dbConnection)
+	}
+
+	// Initializing the API keys
+	apiKeyUsecase := usecases.NewApiKeyUsecase(storage.ApiKeys)
+	apiKeyController := controllers.NewApiKeyController(apiKeyUsecase)
+
+	// The "apikey" subcommand manages the API keys and exits without starting the API server, the first admin key being
+	// issued this way
+	if len(args) > 0 && args[0] == "apikey" {
+		return manageApiKeys(ctx, apiKeyUsecase, args[1:])
 	}
 
 	// Initializing the repository layer
-	languageRepository := repositories.NewLanguageRepository(dbConnection)
-	languageUsecase := usecases.NewLanguageUsecase(languageRepository)
+	languageUsecase := usecases.NewLanguageUsecase(storage.Languages)
 	languageController := controllers.NewLanguageController(languageUsecase)
 
 	// Initializing the use case layer
 
 	// Initializing the controller layer
-	newsOutletRepository := repositories.NewNewsOutletRepository(dbConnection, *languageRepository)
-	newsOutletUsecase := usecases.NewNewsOutletUsecase(newsOutletRepository)
+	newsOutletUsecase := usecases.NewNewsOutletUsecase(storage.NewsOutlets)
 	newsOutletController := controllers.NewNewsOutletController(newsOutletUsecase)
 
+	// The crawls and fact checks fan out to every news outlet, so they also count against a daily quota
+	quotaUsecase := usecases.NewQuotaUsecase(storage.Quotas, cfg.RateLimit.DailyCrawlQuota)
+
 	// Initializing crawlers
-	crawlerUsecase := usecases.NewCrawlerUsecase()
-	crawlerController := controllers.NewCrawlerController(crawlerUsecase, newsOutletUsecase)
+	// Saving the crawl results to a local file is optional and only enabled when crawler.results_file is set
+	var resultsRepository *repositories.ResultsFileRepository
+	if cfg.Crawler.ResultsFile != "" {
+		resultsRepository = repositories.NewResultsFileRepository(cfg.Crawler.ResultsFile)
+	}
+	crawlJobRepository := repositories.NewCrawlJobRepository()
+	httpClient := repositories.NewHttpClient(cfg.HttpClientConfig())
+	fetcher := repositories.NewFetcher(httpClient, cfg.FetcherConfig())
+	analyzerRepository := repositories.NewAnalyzerRepository(cfg.AnalyzerConfig(), httpClient)
+	crawlerUsecase := usecases.NewCrawlerUsecase(crawlJobRepository, storage.CrawlRuns, resultsRepository, fetcher,
+		&analyzerRepository, cfg.Crawler.MaxPagesToVisit)
+	crawlerController := controllers.NewCrawlerController(crawlerUsecase, newsOutletUsecase, quotaUsecase)
+
+	// Initializing the crawl runs history
+	crawlRunUsecase := usecases.NewCrawlRunUsecase(storage.CrawlRuns)
+	crawlRunController := controllers.NewCrawlRunController(crawlRunUsecase)
+
+	// Initializing fact checks
+	postRepository := repositories.NewPostRepository(httpClient)
+	factCheckUsecase := usecases.NewFactCheckUsecase(crawlerUsecase, postRepository, analyzerRepository, storage.CrawlRuns)
+	factCheckController := controllers.NewFactCheckController(factCheckUsecase, newsOutletUsecase, quotaUsecase)
+
+	// Initializing the health checks, the server is only ready while the database and the AI analyzer answer
+	healthUsecase := usecases.NewHealthUsecase(cfg.Server.HealthCheckTimeout,
+		usecases.HealthCheck{Name: "database", Check: func(ctx context.Context) error {
+			return db.Ping(ctx, dbConnection)
+		}},
+		usecases.HealthCheck{Name: "analyzer", Check: analyzerRepository.Ping},
+	)
+	healthController := controllers.NewHealthController(healthUsecase)
 
 	// Initialize the API server
-	server := gin.Default()
+	if cfg.Log.Level != server_errors.DebugLevel {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	server := gin.New()
+	server.HandleMethodNotAllowed = true
+	// Clients are rate limited by address, so the X-Forwarded-For header they send is never trusted
+	_ = server.SetTrustedProxies(nil)
+	// Every request is logged with its correlation id and measured, and every error attached by the controllers is
+	// served as a models.Response carrying its code
+	server.Use(gin.Recovery(), controllers.RequestLogger(), controllers.RequestMetrics(), controllers.ErrorHandler())
+	server.NoRoute(controllers.NoRoute)
+	server.NoMethod(controllers.NoMethod)
+
+	// Every route but the health checks and the metrics requires an API key whose role allows it
+	authenticate := controllers.Authenticate(apiKeyUsecase.Authenticate)
+	authorize := func(role string) []gin.HandlerFunc {
+		if !cfg.Auth.Enabled {
+			return nil
+		}
+		return []gin.HandlerFunc{authenticate, controllers.RequireRole(role)}
+	}
+	if !cfg.Auth.Enabled {
+		server_errors.Log("authentication is disabled, every route is open to anyone reaching the server",
+			server_errors.WarningLevel)
+	}
+
+	// Every client gets its own bucket in each route group, told apart by its API key once authenticated. Before that,
+	// the requests failing to authenticate are limited by address, so guessing keys costs no more than the rate of the
+	// group
+	group := func(role string, perMinute float64, burst int) *gin.RouterGroup {
+		var handlers []gin.HandlerFunc
+		if cfg.RateLimit.Enabled && cfg.Auth.Enabled {
+			handlers = append(handlers, controllers.AuthFailureLimit(repositories.NewRateLimiter(perMinute, burst)))
+		}
+		handlers = append(handlers, authorize(role)...)
+		if cfg.RateLimit.Enabled {
+			handlers = append(handlers, controllers.RateLimit(repositories.NewRateLimiter(perMinute, burst)))
+		}
+		return server.Group("", handlers...)
+	}
+	reader := group(models.RoleRead, cfg.RateLimit.ReadPerMinute, cfg.RateLimit.ReadBurst)
+	crawler := group(models.RoleCrawl, cfg.RateLimit.CrawlPerMinute, cfg.RateLimit.CrawlBurst)
+	admin := group(models.RoleAdmin, cfg.RateLimit.AdminPerMinute, cfg.RateLimit.AdminBurst)
 
 	// Setting up HTTP paths in the API server -------------------------------------------------------------------------
 	server.GET("/ping", func(ctx *gin.Context) {
@@ -47,30 +193,209 @@ func main() {
 		})
 	})
 
+	// ----- Health
+	server.GET("healthz", healthController.Liveness)
+	server.GET("readyz", healthController.Readiness)
+
+	// ----- Metrics
+	server.GET("metrics", controllers.Metrics)
+
 	// ----- Languages
 	// ---------- Create
-	server.POST("language", languageController.AddLanguage)
+	admin.POST("language", languageController.AddLanguage)
 	// ---------- Read
-	server.GET("languages", languageController.GetLanguages)
-	server.GET("languageId/:languageId", languageController.GetLanguageById)
-	server.GET("languageName/:languageName", languageController.GetLanguageByName)
+	reader.GET("languages", languageController.GetLanguages)
+	reader.GET("languageId/:languageId", languageController.GetLanguageById)
+	reader.GET("languageName/:languageName", languageController.GetLanguageByName)
+	// ---------- Update
+	admin.PUT("language/:languageId", languageController.RenameLanguage)
+	// ---------- Delete
+	admin.DELETE("language/:languageId", languageController.DeleteLanguage)
 
 	// ----- News Outlets
 	// ---------- Create
-	server.POST("newsOutlet", newsOutletController.AddNewsOutlet)
+	admin.POST("newsOutlet", newsOutletController.AddNewsOutlet)
 	// ---------- Read
-	server.GET("newsOutlets", newsOutletController.GetNewsOutlets)
-	server.GET("newsOutletName/:newsOutletName", newsOutletController.GetNewsOutletByName)
-	server.GET("newsOutletId/:newsOutletId", newsOutletController.GetNewsOutletById)
+	reader.GET("newsOutlets", newsOutletController.GetNewsOutlets)
+	reader.GET("newsOutletName/:newsOutletName", newsOutletController.GetNewsOutletByName)
+	reader.GET("newsOutletId/:newsOutletId", newsOutletController.GetNewsOutletById)
+	// ---------- Update
+	admin.PUT("newsOutlet/:newsOutletId", newsOutletController.UpdateNewsOutlet)
+	admin.PATCH("newsOutlet/:newsOutletId", newsOutletController.PatchNewsOutlet)
+	// ---------- Delete
+	admin.DELETE("newsOutlet/:newsOutletId", newsOutletController.DeleteNewsOutlet)
 
 	// ----- Crawlers
-	server.POST("crawl", crawlerController.Crawl)
+	crawler.POST("crawl", crawlerController.Crawl)
+	reader.GET("crawl/:crawlJobId", crawlerController.GetCrawlJob)
+	crawler.DELETE("crawl/:crawlJobId", crawlerController.CancelCrawlJob)
+
+	// ----- Crawl runs
+	reader.GET("runs", crawlRunController.GetCrawlRuns)
+	reader.GET("runs/:crawlRunId", crawlRunController.GetCrawlRunById)
+
+	// ----- Fact checks
+	crawler.POST("factCheck", factCheckController.FactCheck)
+
+	// ----- API keys
+	admin.POST("apiKey", apiKeyController.IssueApiKey)
+	admin.GET("apiKeys", apiKeyController.GetApiKeys)
+	admin.DELETE("apiKey/:apiKeyId", apiKeyController.RevokeApiKey)
 	// -----------------------------------------------------------------------------------------------------------------
 
-	err = server.Run(":8000")
+	return serve(ctx, cfg.Server, server, crawlerUsecase)
+}
+
+// serve :
+// Serves the API until ctx is cancelled, then shuts down gracefully: no new request is accepted, the requests in
+// flight are waited for and so are the crawl jobs running in the background, all of it within SHUTDOWN_TIMEOUT. The
+// requests and crawl jobs still running past it are cancelled, so their crawlers halt and store what they collected.
+func serve(ctx context.Context, serverConfig config.ServerConfig, handler http.Handler, crawlerUsecase usecases.CrawlerUsecase) error {
+	// Every request context derives from base, so the requests still running past the timeout can be cancelled
+	base, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
+	httpServer := &http.Server{
+		Addr:              serverConfig.Address,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return base },
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	server_errors.Log("shutting down", server_errors.InfoLevel)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
+	defer cancel()
+
+	// Requests may start crawl jobs until the server stops accepting them, so they are drained first
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		server_errors.Log("requests still in flight were cancelled: "+err.Error(), server_errors.WarningLevel)
+		cancelRequests()
+	}
+
+	if err := crawlerUsecase.Drain(shutdownCtx); err != nil {
+		server_errors.Log("crawl jobs still running were cancelled: "+err.Error(), server_errors.WarningLevel)
+	}
+
+	server_errors.Log("server stopped", server_errors.InfoLevel)
+	return nil
+}
+
+// printConfig :
+// Runs the config subcommand: "print" writes the configuration in effect as YAML, with its secrets redacted.
+func printConfig(cfg config.Config, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return server_errors.ErrConfigInvalidCommand
+	}
+
+	return cfg.Print(os.Stdout)
+}
+
+// manageApiKeys :
+// Runs the apikey subcommand: "create <name> <role>" issues a key and prints it, "list" lists every key issued and
+// "revoke <id>" revokes a key.
+func manageApiKeys(ctx context.Context, apiKeyUsecase usecases.ApiKeyUsecase, args []string) error {
+	if len(args) == 0 {
+		return server_errors.ErrApiKeyInvalidCommand
+	}
+
+	switch {
+	case args[0] == "create" && len(args) == 3:
+		issued, err := apiKeyUsecase.IssueApiKey(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("API key %d issued to %s with the %s role, it will not be shown again:\n%s\n",
+			issued.Id, issued.Name, issued.Role, issued.Key)
+	case args[0] == "list" && len(args) == 1:
+		apiKeys, err := apiKeyUsecase.GetApiKeys(ctx)
+		if err != nil {
+			return err
+		}
+		for _, apiKey := range apiKeys {
+			status := "active"
+			if apiKey.RevokedAt != nil {
+				status = "revoked " + apiKey.RevokedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%d\t%s\t%s\t%s...\t%s\n", apiKey.Id, apiKey.Name, apiKey.Role, apiKey.Prefix, status)
+		}
+	case args[0] == "revoke" && len(args) == 2:
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return server_errors.ErrApiKeyInvalidCommand
+		}
+		if err = apiKeyUsecase.RevokeApiKey(ctx, id); err != nil {
+			return err
+		}
+		server_errors.Log(server_errors.ApiKeyRevoked, server_errors.InfoLevel)
+	default:
+		return server_errors.ErrApiKeyInvalidCommand
+	}
+
+	return nil
+}
+
+// migrate :
+// Runs the migrate subcommand: "up" applies every pending migration, "down [steps]" reverts the last steps applied
+// migrations (one by default) and "status" lists every migration along with the moment it was applied. The migrations
+// are the ones written for the provided storage driver.
+func migrate(connection *sql.DB, driver string, args []string) error {
+	migrations, err := db.Migrations(driver)
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return
+		return err
 	}
+
+	migrator := db.NewMigrator(connection, driver, migrations)
+	ctx := context.Background()
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		if _, err = migrator.Up(ctx); err != nil {
+			return err
+		}
+		server_errors.Log(server_errors.MigrationsApplied, server_errors.InfoLevel)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return server_errors.ErrMigrationInvalidCommand
+			}
+		}
+		if _, err = migrator.Down(ctx, steps); err != nil {
+			return err
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+	default:
+		return server_errors.ErrMigrationInvalidCommand
+	}
+
+	return nil
 }
@@ -0,0 +1,83 @@
+// Command mock-analyzer serves the "/getLinks" and "/analyze" endpoints of the AI analyzer without any model, as a
+// drop-in for the Python analyzer on machines unable to run Ollama. See the mockanalyzer package for its heuristics.
+package main
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/mockanalyzer"
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	// defaultPort : port of the Python analyzer, so the server finds the mock at the same URL
+	defaultPort = "7654"
+	// readHeaderTimeout : how long a client may take to send the headers of its request
+	readHeaderTimeout = 10 * time.Second
+	// shutdownTimeout : how long the requests in flight are waited for when stopping
+	shutdownTimeout = 5 * time.Second
+)
+
+func main() {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = defaultPort
+	}
+
+	flags := flag.NewFlagSet("mock-analyzer", flag.ContinueOnError)
+	flags.StringVar(&port, "port", port, "port to listen on (env PORT)")
+	logLevel := flags.String("log-level", os.Getenv("LOG_LEVEL"), "lowest level logged (env LOG_LEVEL)")
+	logFormat := flags.String("log-format", os.Getenv("LOG_FORMAT"), "format of the logs: text or json (env LOG_FORMAT)")
+
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
+	}
+
+	server_errors.SetupLogger(server_errors.LogConfig{Level: *logLevel, Format: *logFormat}, os.Stderr)
+
+	if err := run(":" + port); err != nil {
+		server_errors.Log(err.Error(), server_errors.ErrorLevel)
+		os.Exit(1)
+	}
+}
+
+// run :
+// Serves the mock analyzer on address until SIGINT or SIGTERM, returning instead of exiting so its deferred cleanups
+// run.
+func run(address string) error {
+	// Stopping on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mockanalyzer.NewHandler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	server_errors.Log("mock AI analyzer listening on "+server.Addr, server_errors.InfoLevel)
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
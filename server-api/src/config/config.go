@@ -0,0 +1,291 @@
+package config
+
+import (
+	"aletheia-server/src/db"
+	"aletheia-server/src/errors"
+	"aletheia-server/src/repositories"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config :
+// Settings of the whole server. They are read, from the lowest to the highest precedence, from the defaults, the YAML
+// configuration file, the environment variables and the command line flags. Every setting is bound to the environment
+// variable named by its env tag and to the flag named after its path in the file, e.g. -database.host.
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Analyzer  AnalyzerConfig  `yaml:"analyzer"`
+	Crawler   CrawlerConfig   `yaml:"crawler"`
+	Http      HttpConfig      `yaml:"http"`
+	Log       LogConfig       `yaml:"log"`
+	Auth      AuthConfig      `yaml:"auth"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+}
+
+type ServerConfig struct {
+	Address            string        `yaml:"address" env:"SERVER_ADDRESS" usage:"address the API listens on"`
+	ShutdownTimeout    time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" usage:"how long the requests and crawl jobs in flight are waited for when stopping"`
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout" env:"HEALTH_CHECK_TIMEOUT" usage:"how long a dependency may take to answer a readiness check"`
+}
+
+type DatabaseConfig struct {
+	Driver          string        `yaml:"driver" env:"DB_DRIVER" usage:"storage driver: postgres, or sqlite to keep everything in a single file"`
+	Path            string        `yaml:"path" env:"DB_PATH" usage:"SQLite file, used by the sqlite driver"`
+	Host            string        `yaml:"host" env:"DB_HOST" usage:"PostgreSQL host"`
+	Port            int           `yaml:"port" env:"DB_PORT" usage:"PostgreSQL port"`
+	User            string        `yaml:"user" env:"DB_USER" usage:"PostgreSQL user"`
+	Password        string        `yaml:"password" env:"DB_PASSWORD" usage:"PostgreSQL password" secret:"true"`
+	Name            string        `yaml:"name" env:"DB_NAME" usage:"PostgreSQL database name"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" usage:"connections to the database open at once"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" usage:"idle connections kept in the pool"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" usage:"how long a connection is reused"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" usage:"how long a connection may stay idle"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout" env:"DB_CONNECT_TIMEOUT" usage:"how long the database is retried at startup"`
+}
+
+type AnalyzerConfig struct {
+	Url     string        `yaml:"url" env:"AI_ANALYZER_URL" usage:"base URL of the AI analyzer"`
+	Timeout time.Duration `yaml:"timeout" env:"AI_ANALYZER_TIMEOUT" usage:"deadline of each request attempt to the AI analyzer"`
+}
+
+type CrawlerConfig struct {
+	UserAgent         string  `yaml:"user_agent" env:"CRAWLER_USER_AGENT" usage:"User-Agent sent by the crawlers and matched against robots.txt"`
+	RequestsPerSecond float64 `yaml:"requests_per_second" env:"CRAWLER_REQUESTS_PER_SECOND" usage:"requests per second allowed to each host"`
+	Burst             int     `yaml:"burst" env:"CRAWLER_BURST" usage:"requests allowed to each host at once before throttling"`
+	Workers           int     `yaml:"workers" env:"CRAWLER_WORKERS" usage:"outlet requests in flight at once across every crawler"`
+	OutletConcurrency int     `yaml:"outlet_concurrency" env:"CRAWLER_OUTLET_CONCURRENCY" usage:"article pages of a single outlet fetched at once"`
+	MaxPagesToVisit   int     `yaml:"max_pages_to_visit" env:"CRAWLER_MAX_PAGES_TO_VISIT" usage:"most article pages a crawl may ask to visit on each outlet"`
+	ResultsFile       string  `yaml:"results_file" env:"CRAWLER_RESULTS_FILE" usage:"file where every crawl result is appended as JSON, disabled when empty"`
+}
+
+type HttpConfig struct {
+	Timeout      time.Duration `yaml:"timeout" env:"HTTP_TIMEOUT" usage:"deadline of each outbound request attempt"`
+	MaxRetries   int           `yaml:"max_retries" env:"HTTP_MAX_RETRIES" usage:"attempts following a timed out, 5xx or 429 request"`
+	MaxBodyBytes int64         `yaml:"max_body_bytes" env:"HTTP_MAX_BODY_BYTES" usage:"largest response body read from outlets and the AI analyzer"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" usage:"lowest level logged: debug, info, warning or error"`
+	Format string `yaml:"format" env:"LOG_FORMAT" usage:"format of the logs: text or json"`
+}
+
+type AuthConfig struct {
+	Enabled bool `yaml:"enabled" env:"AUTH_ENABLED" usage:"requires an API key on every route but the health checks and the metrics"`
+}
+
+// RateLimitConfig :
+// Limits of each client, told apart by its API key or by its address. Each route group has its own bucket, refilling
+// at its rate per minute up to its burst, while the crawls and fact checks also count against a daily quota.
+type RateLimitConfig struct {
+	Enabled         bool    `yaml:"enabled" env:"RATE_LIMIT_ENABLED" usage:"limits the requests of each client"`
+	ReadPerMinute   float64 `yaml:"read_per_minute" env:"RATE_LIMIT_READ_PER_MINUTE" usage:"read requests each client may send per minute"`
+	ReadBurst       int     `yaml:"read_burst" env:"RATE_LIMIT_READ_BURST" usage:"read requests each client may send at once"`
+	CrawlPerMinute  float64 `yaml:"crawl_per_minute" env:"RATE_LIMIT_CRAWL_PER_MINUTE" usage:"crawl and fact check requests each client may send per minute"`
+	CrawlBurst      int     `yaml:"crawl_burst" env:"RATE_LIMIT_CRAWL_BURST" usage:"crawl and fact check requests each client may send at once"`
+	AdminPerMinute  float64 `yaml:"admin_per_minute" env:"RATE_LIMIT_ADMIN_PER_MINUTE" usage:"admin requests each client may send per minute"`
+	AdminBurst      int     `yaml:"admin_burst" env:"RATE_LIMIT_ADMIN_BURST" usage:"admin requests each client may send at once"`
+	DailyCrawlQuota int     `yaml:"daily_crawl_quota" env:"DAILY_CRAWL_QUOTA" usage:"crawls and fact checks each client may start per day, unlimited when 0"`
+}
+
+// Default :
+// Returns the settings used when nothing overrides them. There is no default password, it has to be provided when
+// using the postgres driver.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Address:            ":8000",
+			ShutdownTimeout:    30 * time.Second,
+			HealthCheckTimeout: 2 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:          db.PostgresDriver,
+			Path:            "aletheia.db",
+			Host:            "localhost",
+			Port:            5432,
+			User:            "postgres",
+			Name:            "postgres",
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			ConnectTimeout:  time.Minute,
+		},
+		Analyzer: AnalyzerConfig{
+			Url:     "http://localhost:7654",
+			Timeout: 2 * time.Minute,
+		},
+		Crawler: CrawlerConfig{
+			UserAgent:         "AletheiaCrawler/1.0",
+			RequestsPerSecond: 1,
+			Burst:             1,
+			Workers:           8,
+			OutletConcurrency: 2,
+			MaxPagesToVisit:   20,
+		},
+		Http: HttpConfig{
+			Timeout:      15 * time.Second,
+			MaxRetries:   2,
+			MaxBodyBytes: 5 << 20,
+		},
+		Log: LogConfig{
+			Level:  server_errors.InfoLevel,
+			Format: server_errors.TextFormat,
+		},
+		Auth: AuthConfig{
+			Enabled: true,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:         true,
+			ReadPerMinute:   120,
+			ReadBurst:       30,
+			CrawlPerMinute:  6,
+			CrawlBurst:      2,
+			AdminPerMinute:  60,
+			AdminBurst:      10,
+			DailyCrawlQuota: 100,
+		},
+	}
+}
+
+// Validate :
+// Checks every setting at once.
+//
+// Error: will throw ConfigInvalid listing every invalid setting.
+func (c Config) Validate() error {
+	if problems := c.problems(); len(problems) > 0 {
+		return server_errors.ErrConfigInvalid.With(strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// problems :
+// Returns a description of every invalid setting, named after its path in the file.
+func (c Config) problems() []string {
+	var problems []string
+	check := func(valid bool, name string, expected string) {
+		if !valid {
+			problems = append(problems, fmt.Sprintf("%s should be %s", name, expected))
+		}
+	}
+
+	_, _, err := net.SplitHostPort(c.Server.Address)
+	check(err == nil, "server.address", "a host:port address")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout", "positive")
+	check(c.Server.HealthCheckTimeout > 0, "server.health_check_timeout", "positive")
+
+	check(c.Database.Driver == db.PostgresDriver || c.Database.Driver == db.SqliteDriver, "database.driver", "postgres or sqlite")
+	if c.Database.Driver == db.SqliteDriver {
+		check(c.Database.Path != "", "database.path", "set")
+	} else {
+		check(c.Database.Host != "", "database.host", "set")
+		check(c.Database.Port > 0 && c.Database.Port < 65536, "database.port", "a valid port")
+		check(c.Database.User != "", "database.user", "set")
+		check(c.Database.Password != "", "database.password", "set")
+		check(c.Database.Name != "", "database.name", "set")
+	}
+	check(c.Database.MaxOpenConns > 0, "database.max_open_conns", "positive")
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns", "non negative")
+	check(c.Database.ConnMaxLifetime > 0, "database.conn_max_lifetime", "positive")
+	check(c.Database.ConnMaxIdleTime > 0, "database.conn_max_idle_time", "positive")
+	check(c.Database.ConnectTimeout > 0, "database.connect_timeout", "positive")
+
+	analyzerUrl, err := url.Parse(c.Analyzer.Url)
+	check(err == nil && (analyzerUrl.Scheme == "http" || analyzerUrl.Scheme == "https") && analyzerUrl.Host != "",
+		"analyzer.url", "an http or https URL")
+	check(c.Analyzer.Timeout > 0, "analyzer.timeout", "positive")
+
+	check(c.Crawler.UserAgent != "", "crawler.user_agent", "set")
+	check(c.Crawler.RequestsPerSecond > 0, "crawler.requests_per_second", "positive")
+	check(c.Crawler.Burst > 0, "crawler.burst", "positive")
+	check(c.Crawler.Workers > 0, "crawler.workers", "positive")
+	check(c.Crawler.OutletConcurrency > 0, "crawler.outlet_concurrency", "positive")
+	check(c.Crawler.MaxPagesToVisit > 0, "crawler.max_pages_to_visit", "positive")
+
+	check(c.Http.Timeout > 0, "http.timeout", "positive")
+	check(c.Http.MaxRetries >= 0, "http.max_retries", "non negative")
+	check(c.Http.MaxBodyBytes > 0, "http.max_body_bytes", "positive")
+
+	check(c.Log.Level == server_errors.DebugLevel || c.Log.Level == server_errors.InfoLevel ||
+		c.Log.Level == server_errors.WarningLevel || c.Log.Level == server_errors.ErrorLevel,
+		"log.level", "debug, info, warning or error")
+	check(c.Log.Format == server_errors.TextFormat || c.Log.Format == server_errors.JSONFormat,
+		"log.format", "text or json")
+
+	if c.RateLimit.Enabled {
+		check(c.RateLimit.ReadPerMinute > 0, "rate_limit.read_per_minute", "positive")
+		check(c.RateLimit.ReadBurst > 0, "rate_limit.read_burst", "positive")
+		check(c.RateLimit.CrawlPerMinute > 0, "rate_limit.crawl_per_minute", "positive")
+		check(c.RateLimit.CrawlBurst > 0, "rate_limit.crawl_burst", "positive")
+		check(c.RateLimit.AdminPerMinute > 0, "rate_limit.admin_per_minute", "positive")
+		check(c.RateLimit.AdminBurst > 0, "rate_limit.admin_burst", "positive")
+	}
+	check(c.RateLimit.DailyCrawlQuota >= 0, "rate_limit.daily_crawl_quota", "non negative")
+
+	return problems
+}
+
+// Conversions ---------------------------------------------------------------------------------------------------------
+
+// DBConfig :
+// Returns the settings of the database connection.
+func (c Config) DBConfig() db.Config {
+	return db.Config{
+		Driver:          c.Database.Driver,
+		Path:            c.Database.Path,
+		Host:            c.Database.Host,
+		Port:            c.Database.Port,
+		User:            c.Database.User,
+		Password:        c.Database.Password,
+		DBName:          c.Database.Name,
+		MaxOpenConns:    c.Database.MaxOpenConns,
+		MaxIdleConns:    c.Database.MaxIdleConns,
+		ConnMaxLifetime: c.Database.ConnMaxLifetime,
+		ConnMaxIdleTime: c.Database.ConnMaxIdleTime,
+		ConnectTimeout:  c.Database.ConnectTimeout,
+	}
+}
+
+// HttpClientConfig :
+// Returns the settings shared by every outbound HTTP call.
+func (c Config) HttpClientConfig() repositories.HttpClientConfig {
+	return repositories.HttpClientConfig{
+		Timeout:     c.Http.Timeout,
+		MaxRetries:  c.Http.MaxRetries,
+		MaxBodySize: c.Http.MaxBodyBytes,
+	}
+}
+
+// FetcherConfig :
+// Returns the settings of the fetcher shared by every crawler.
+func (c Config) FetcherConfig() repositories.FetcherConfig {
+	return repositories.FetcherConfig{
+		UserAgent:         c.Crawler.UserAgent,
+		RequestsPerSecond: c.Crawler.RequestsPerSecond,
+		Burst:             c.Crawler.Burst,
+		Workers:           c.Crawler.Workers,
+		OutletConcurrency: c.Crawler.OutletConcurrency,
+	}
+}
+
+// AnalyzerConfig :
+// Returns the settings of the AI analyzer.
+func (c Config) AnalyzerConfig() repositories.AnalyzerConfig {
+	return repositories.AnalyzerConfig{
+		Url:     c.Analyzer.Url,
+		Timeout: c.Analyzer.Timeout,
+	}
+}
+
+// LogConfig :
+// Returns the settings of the server logs.
+func (c Config) LogConfig() server_errors.LogConfig {
+	return server_errors.LogConfig{
+		Level:  c.Log.Level,
+		Format: c.Log.Format,
+	}
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"aletheia-server/src/errors"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted : value printed in place of the secrets
+const redacted = "REDACTED"
+
+// Load :
+// Reads the settings from the defaults, the YAML file given by the -config flag or the CONFIG_FILE environment
+// variable, the environment variables and the flags of args, in this order of precedence, then validates them. DEBUG
+// set to true lowers the log level to debug, unless the file, LOG_LEVEL or a flag sets it. The arguments left after the
+// flags are returned, so they can be handled as subcommands.
+//
+// Error: will throw ConfigFileError if the file cannot be read or holds unknown settings.
+//
+// Error: will throw ConfigInvalid listing every setting that cannot be parsed or is invalid, wrapping flag.ErrHelp
+// when the usage was requested.
+func Load(args []string) (Config, []string, error) {
+	config := Default()
+	settings := fields(&config)
+
+	flags := flag.NewFlagSet("aletheia-api", flag.ContinueOnError)
+	path := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML configuration file")
+
+	// Flags are only recorded while parsing, so they can be applied on top of the file and the environment
+	var overrides []func() error
+	for _, setting := range settings {
+		flags.Func(setting.name, setting.usage+" ("+setting.env+")", func(value string) error {
+			overrides = append(overrides, func() error { return setting.set(value) })
+			return nil
+		})
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return Config{}, nil, server_errors.ErrConfigInvalid.Wrap(err)
+	}
+
+	// DEBUG only replaces the default level, so it is applied before any source setting the level explicitly
+	if debug, err := strconv.ParseBool(os.Getenv("DEBUG")); err == nil && debug {
+		config.Log.Level = server_errors.DebugLevel
+	}
+
+	if *path != "" {
+		if err := loadFile(*path, &config); err != nil {
+			return Config{}, nil, err
+		}
+	}
+
+	var problems []string
+
+	for _, setting := range settings {
+		// A variable set to an empty value still applies, so it can clear a setting of the file
+		if value, ok := os.LookupEnv(setting.env); ok {
+			if err := setting.set(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", setting.env, err))
+			}
+		}
+	}
+
+	for _, override := range overrides {
+		if err := override(); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	problems = append(problems, config.problems()...)
+	if len(problems) > 0 {
+		return Config{}, nil, server_errors.ErrConfigInvalid.With(strings.Join(problems, "; "))
+	}
+
+	return config, flags.Args(), nil
+}
+
+// loadFile :
+// Decodes the YAML file at path on top of config. Settings missing from the file keep their value.
+func loadFile(path string, config *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return server_errors.ErrConfigFileError.With(path).Wrap(err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	if err = decoder.Decode(config); err != nil && err != io.EOF {
+		return server_errors.ErrConfigFileError.With(path).Wrap(err)
+	}
+
+	return nil
+}
+
+// Print :
+// Writes the settings as YAML, in the format of the configuration file, with every secret redacted.
+func (c Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// Redacted :
+// Returns a copy of the settings whose secrets are replaced, so they can be printed or logged.
+func (c Config) Redacted() Config {
+	for _, setting := range fields(&c) {
+		if setting.secret && setting.value.String() != "" {
+			setting.value.SetString(redacted)
+		}
+	}
+
+	return c
+}
+
+// Settings ------------------------------------------------------------------------------------------------------------
+
+// field :
+// A single setting of Config, along with its path in the file, its environment variable and its description.
+type field struct {
+	name   string
+	env    string
+	usage  string
+	secret bool
+	value  reflect.Value
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// fields :
+// Returns every setting of config, pointing inside it so they can be set.
+func fields(config *Config) []field {
+	var settings []field
+	collect(reflect.ValueOf(config).Elem(), "", &settings)
+
+	return settings
+}
+
+func collect(value reflect.Value, prefix string, settings *[]field) {
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		name := prefix + strings.Split(structField.Tag.Get("yaml"), ",")[0]
+
+		if structField.Type.Kind() == reflect.Struct {
+			collect(value.Field(i), name+".", settings)
+			continue
+		}
+
+		*settings = append(*settings, field{
+			name:   name,
+			env:    structField.Tag.Get("env"),
+			usage:  structField.Tag.Get("usage"),
+			secret: structField.Tag.Get("secret") == "true",
+			value:  value.Field(i),
+		})
+	}
+}
+
+// set :
+// Parses input according to the type of the setting and stores it.
+func (f field) set(input string) error {
+	switch {
+	case f.value.Type() == durationType:
+		duration, err := time.ParseDuration(input)
+		if err != nil {
+			return fmt.Errorf("%s should be a duration such as 30s: %q", f.name, input)
+		}
+		f.value.SetInt(int64(duration))
+	case f.value.Kind() == reflect.String:
+		f.value.SetString(input)
+	case f.value.Kind() == reflect.Int || f.value.Kind() == reflect.Int64:
+		number, err := strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s should be an integer: %q", f.name, input)
+		}
+		f.value.SetInt(number)
+	case f.value.Kind() == reflect.Bool:
+		enabled, err := strconv.ParseBool(input)
+		if err != nil {
+			return fmt.Errorf("%s should be true or false: %q", f.name, input)
+		}
+		f.value.SetBool(enabled)
+	case f.value.Kind() == reflect.Float64:
+		number, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return fmt.Errorf("%s should be a number: %q", f.name, input)
+		}
+		f.value.SetFloat(number)
+	default:
+		return fmt.Errorf("%s has an unsupported type %s", f.name, f.value.Type())
+	}
+
+	return nil
+}
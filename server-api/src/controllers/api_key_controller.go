@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeyController struct {
+	apiKeyUsecase usecases.ApiKeyUsecase
+}
+
+func NewApiKeyController(usecase usecases.ApiKeyUsecase) ApiKeyController {
+	return ApiKeyController{
+		apiKeyUsecase: usecase,
+	}
+}
+
+// Create --------------------------------------------------------------------------------------------------------------
+
+// IssueApiKey :
+// Issues a new API key with the name and role of the body. The key is part of the response and cannot be read back
+// afterward.
+//
+// Error: will return StatusBadRequest if the body is invalid, the name is empty or the role is unknown.
+//
+// Error: will return StatusInternalServerError if the key cannot be generated or stored.
+func (ac *ApiKeyController) IssueApiKey(ctx *gin.Context) {
+	var initializer models.ApiKeyInitializer
+
+	if !bindJSON(ctx, &initializer) {
+		return
+	}
+
+	issued, err := ac.apiKeyUsecase.IssueApiKey(ctx.Request.Context(), initializer.Name, initializer.Role)
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, issued)
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetApiKeys :
+// Returns every API key issued, revoked ones included, without the keys themselves.
+//
+// Error: will return StatusInternalServerError if the API keys cannot be read from the database.
+func (ac *ApiKeyController) GetApiKeys(ctx *gin.Context) {
+	apiKeys, err := ac.apiKeyUsecase.GetApiKeys(ctx.Request.Context())
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apiKeys)
+}
+
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// RevokeApiKey :
+// Revokes the API key with the provided id, which is rejected from then on.
+//
+// Error: will return StatusBadRequest if the id is invalid.
+//
+// Error: will return StatusNotFound if an API key with the provided id is not found.
+//
+// Error: will return StatusInternalServerError if the key cannot be revoked.
+func (ac *ApiKeyController) RevokeApiKey(ctx *gin.Context) {
+	id, ok := idParam(ctx, "apiKeyId")
+
+	if !ok {
+		return
+	}
+
+	if err := ac.apiKeyUsecase.RevokeApiKey(ctx.Request.Context(), id); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	confirm(ctx, server_errors.ApiKeyRevoked)
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiKeyHeader :
+// Header the API key can be sent in, as an alternative to an "Authorization: Bearer" header.
+const ApiKeyHeader = "X-Api-Key"
+
+// apiKeyContextKey : key under which the API key authenticating the request is stored in the gin context
+const apiKeyContextKey = "apiKey"
+
+// Authenticate :
+// Middleware reading the API key of the request, either as a bearer token or from the X-Api-Key header, and resolving
+// it with authenticate. The request is aborted when the key is missing or rejected; otherwise the key is available to
+// the following handlers through CurrentApiKey and its id is attached to the logs of the request.
+func Authenticate(authenticate func(ctx context.Context, key string) (*models.ApiKey, error)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		apiKey, err := authenticate(ctx.Request.Context(), requestApiKey(ctx))
+
+		if err != nil {
+			if server_errors.As(err).Status == http.StatusUnauthorized {
+				ctx.Header("WWW-Authenticate", `Bearer realm="aletheia"`)
+			}
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(apiKeyContextKey, apiKey)
+		ctx.Request = ctx.Request.WithContext(
+			server_errors.WithLogAttrs(ctx.Request.Context(), slog.Int(server_errors.ApiKeyIdKey, apiKey.Id)),
+		)
+
+		ctx.Next()
+	}
+}
+
+// RequireRole :
+// Middleware only letting through the requests authenticated with a key whose role allows what the provided role
+// does. It must follow Authenticate.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		apiKey := CurrentApiKey(ctx)
+
+		if apiKey == nil {
+			ctx.Error(server_errors.ErrMissingApiKey)
+			ctx.Abort()
+			return
+		}
+
+		if !models.RoleAllows(apiKey.Role, role) {
+			ctx.Error(server_errors.ErrForbiddenApiKey.With(role + " role required"))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// CurrentApiKey :
+// Returns the API key authenticating the request, or nil if it went through no Authenticate middleware.
+func CurrentApiKey(ctx *gin.Context) *models.ApiKey {
+	value, ok := ctx.Get(apiKeyContextKey)
+
+	if !ok {
+		return nil
+	}
+
+	apiKey, _ := value.(*models.ApiKey)
+	return apiKey
+}
+
+// requestApiKey :
+// Returns the key sent as a bearer token, or in the X-Api-Key header when there is none.
+func requestApiKey(ctx *gin.Context) string {
+	authorization := ctx.GetHeader("Authorization")
+
+	if scheme, token, found := strings.Cut(authorization, " "); found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	return strings.TrimSpace(ctx.GetHeader(ApiKeyHeader))
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CrawlRunController struct {
+	crawlRunUsecase usecases.CrawlRunUsecase
+}
+
+func NewCrawlRunController(usecase usecases.CrawlRunUsecase) CrawlRunController {
+	return CrawlRunController{
+		crawlRunUsecase: usecase,
+	}
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetCrawlRuns :
+// Returns a page of the stored crawl runs, newest first. The page is selected through the "limit" and "offset" query
+// parameters.
+//
+// Error: will return StatusBadRequest if limit or offset are not non negative integers.
+//
+// Error: will return StatusInternalServerError if the crawl runs cannot be read from the database.
+func (cr *CrawlRunController) GetCrawlRuns(ctx *gin.Context) {
+	limit, limitErr := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(usecases.DefaultCrawlRunsLimit)))
+	offset, offsetErr := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+
+	if limitErr != nil || offsetErr != nil || limit < 0 || offset < 0 {
+		ctx.Error(server_errors.ErrInvalidPaginationError)
+		return
+	}
+
+	runs, err := cr.crawlRunUsecase.GetCrawlRuns(ctx.Request.Context(), limit, offset)
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runs)
+}
+
+// GetCrawlRunById :
+// Returns the crawl run with the provided id along with its crawlers, their articles and its verdict, if any.
+//
+// Error: will return StatusBadRequest if the id is invalid.
+//
+// Error: will return StatusNotFound if a crawl run with the provided id is not found.
+func (cr *CrawlRunController) GetCrawlRunById(ctx *gin.Context) {
+	id, ok := idParam(ctx, "crawlRunId")
+
+	if !ok {
+		return
+	}
+
+	run, err := cr.crawlRunUsecase.GetCrawlRunById(ctx.Request.Context(), id)
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, run)
+}
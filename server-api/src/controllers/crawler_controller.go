@@ -11,46 +11,116 @@ import (
 type CrawlerController struct {
 	crawlerUseCase    usecases.CrawlerUsecase
 	newsOutletUseCase usecases.NewsOutletUseCase
+	quotaUseCase      usecases.QuotaUsecase
 }
 
-func NewCrawlerController(crawler usecases.CrawlerUsecase, newsOutletUseCase usecases.NewsOutletUseCase) CrawlerController {
+func NewCrawlerController(crawler usecases.CrawlerUsecase, newsOutletUseCase usecases.NewsOutletUseCase, quotaUseCase usecases.QuotaUsecase) CrawlerController {
 	return CrawlerController{
 		crawlerUseCase:    crawler,
 		newsOutletUseCase: newsOutletUseCase,
+		quotaUseCase:      quotaUseCase,
 	}
 }
 
+// Create --------------------------------------------------------------------------------------------------------------
+
+// Crawl :
+// Starts a crawl job over every news outlet stored in the database looking for the query received in the body. The job
+// runs in the background, so only its id and initial state are returned. Only a crawl that starts counts against the
+// daily crawl quota of the client.
+//
+// Error: will return StatusBadRequest if the body is invalid or if no crawler could be initialized.
+//
+// Error: will return StatusTooManyRequests if the client used its daily crawl quota.
+//
+// Error: will return StatusServiceUnavailable if the server is stopping and no longer starts crawl jobs.
+//
+// Error: will return StatusInternalServerError if the news outlets could not be collected from the database.
 func (cr *CrawlerController) Crawl(ctx *gin.Context) {
 	var crawlersInitializer models.CrawlerInitializer
-	err := ctx.BindJSON(&crawlersInitializer)
+
+	if !bindJSON(ctx, &crawlersInitializer) {
+		return
+	}
+
+	newsOutlets, err := cr.newsOutletUseCase.GetNewsOutlets(ctx.Request.Context())
 
 	if err != nil {
-		server_errors.Log(server_errors.InvalidParameters, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusBadRequest, models.Response{
-			Message: err.Error(),
-			Status:  http.StatusBadRequest,
-		})
+		ctx.Error(err)
+		return
+	}
+
+	if err = cr.crawlerUseCase.CheckCrawl(newsOutlets, crawlersInitializer); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	quota, ok := ConsumeCrawlQuota(ctx, cr.quotaUseCase.ConsumeCrawlQuota)
+
+	if !ok {
 		return
 	}
 
-	newsOutlets, err := cr.newsOutletUseCase.GetNewsOutlets()
+	job, err := cr.crawlerUseCase.StartCrawlJob(ctx.Request.Context(), newsOutlets, crawlersInitializer)
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		switch err.Error() {
-		case server_errors.NewsOutletParsingError:
-			ctx.JSON(http.StatusBadRequest, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusBadRequest,
-			})
-		default:
-			ctx.JSON(http.StatusInternalServerError, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-		}
-		return
-	}
-
-	cr.crawlerUseCase.Crawl(newsOutlets, crawlersInitializer.PagesToVisit, crawlersInitializer.Query)
+		// The crawl never ran, so it does not count against the quota
+		RefundCrawlQuota(ctx, quota, cr.quotaUseCase.RefundCrawlQuota)
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetCrawlJob :
+// Returns the progress of the crawl job with the provided id, including the status of each of its crawlers and, once
+// it halted, its result.
+//
+// Error: will return StatusNotFound if there is no crawl job with the provided id.
+func (cr *CrawlerController) GetCrawlJob(ctx *gin.Context) {
+	id := ctx.Param("crawlJobId")
+
+	if id == "" {
+		ctx.Error(server_errors.ErrEmptyIdError)
+		return
+	}
+
+	job, err := cr.crawlerUseCase.GetCrawlJob(id)
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// CancelCrawlJob :
+// Cancels the crawl job with the provided id. The crawlers abort their pending requests, so the job halts shortly
+// after.
+//
+// Error: will return StatusNotFound if there is no crawl job with the provided id.
+//
+// Error: will return StatusConflict if the crawl job already halted.
+func (cr *CrawlerController) CancelCrawlJob(ctx *gin.Context) {
+	id := ctx.Param("crawlJobId")
+
+	if id == "" {
+		ctx.Error(server_errors.ErrEmptyIdError)
+		return
+	}
+
+	job, err := cr.crawlerUseCase.CancelCrawlJob(id)
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, job)
 }
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler :
+// Middleware serving the last error attached to the request through ctx.Error as a models.Response, so every
+// controller reports its errors the same way. The status and code come from the typed error, while errors that are not
+// typed are served as an internal error without leaking their message.
+func ErrorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+
+		err := server_errors.As(ctx.Errors.Last().Err)
+
+		level := server_errors.WarningLevel
+		if err.Status >= http.StatusInternalServerError {
+			level = server_errors.ErrorLevel
+		}
+		server_errors.LogContext(ctx.Request.Context(), err.Message, level, "code", err.Code, "error", err.Error())
+
+		ctx.JSON(err.Status, models.Response{
+			Status:  err.Status,
+			Code:    err.Code,
+			Message: err.Message,
+			Details: err.Details,
+		})
+	}
+}
+
+// NoRoute :
+// Handler of the requests matching no route.
+func NoRoute(ctx *gin.Context) {
+	ctx.Error(server_errors.ErrRouteNotFound.With(ctx.Request.URL.Path))
+}
+
+// NoMethod :
+// Handler of the requests matching a route, but none of its methods.
+func NoMethod(ctx *gin.Context) {
+	ctx.Error(server_errors.ErrMethodNotAllowed.With(ctx.Request.Method))
+}
+
+// bindJSON :
+// Decodes the body of the request into obj. When it is invalid, an InvalidParameters error is attached to the request
+// and false is returned.
+func bindJSON(ctx *gin.Context, obj any) bool {
+	if err := ctx.ShouldBindJSON(obj); err != nil {
+		ctx.Error(server_errors.ErrInvalidParameters.With(err.Error()))
+		return false
+	}
+
+	return true
+}
+
+// confirm :
+// Writes the response confirming an action.
+func confirm(ctx *gin.Context, message string) {
+	ctx.JSON(http.StatusOK, models.Response{
+		Status:  http.StatusOK,
+		Code:    server_errors.CodeOK,
+		Message: message,
+	})
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"aletheia-server/src/models"
+	"aletheia-server/src/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FactCheckController struct {
+	factCheckUseCase  usecases.FactCheckUsecase
+	newsOutletUseCase usecases.NewsOutletUseCase
+	quotaUseCase      usecases.QuotaUsecase
+}
+
+func NewFactCheckController(factCheckUseCase usecases.FactCheckUsecase, newsOutletUseCase usecases.NewsOutletUseCase, quotaUseCase usecases.QuotaUsecase) FactCheckController {
+	return FactCheckController{
+		factCheckUseCase:  factCheckUseCase,
+		newsOutletUseCase: newsOutletUseCase,
+		quotaUseCase:      quotaUseCase,
+	}
+}
+
+// FactCheck :
+// Fact-checks the post whose url is received in the body against every news outlet stored in the database, returning
+// the verdict alongside the analysis of each article. The fact check is aborted if the client disconnects. Only a
+// valid fact check counts against the daily crawl quota of the client.
+//
+// Error: will return StatusBadRequest if the body is invalid, if the post cannot be collected or if no crawler could be
+// initialized.
+//
+// Error: will return StatusTooManyRequests if the client used its daily crawl quota.
+//
+// Error: will return StatusInternalServerError if the news outlets could not be collected from the database.
+func (fc *FactCheckController) FactCheck(ctx *gin.Context) {
+	var pkg models.PackageReceived
+
+	if !bindJSON(ctx, &pkg) {
+		return
+	}
+
+	newsOutlets, err := fc.newsOutletUseCase.GetNewsOutlets(ctx.Request.Context())
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err = fc.factCheckUseCase.CheckFactCheck(pkg, newsOutlets); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if _, ok := ConsumeCrawlQuota(ctx, fc.quotaUseCase.ConsumeCrawlQuota); !ok {
+		return
+	}
+
+	result, err := fc.factCheckUseCase.FactCheck(ctx.Request.Context(), pkg, newsOutlets)
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
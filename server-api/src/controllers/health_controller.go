@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	healthUsecase usecases.HealthUsecase
+}
+
+func NewHealthController(usecase usecases.HealthUsecase) HealthController {
+	return HealthController{
+		healthUsecase: usecase,
+	}
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// Liveness :
+// Reports that the server is running, without checking its dependencies.
+func (hc *HealthController) Liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, hc.healthUsecase.Liveness())
+}
+
+// Readiness :
+// Reports whether the server can serve requests, along with the status and latency of each of its dependencies.
+//
+// Error: will return StatusServiceUnavailable if any dependency is down.
+func (hc *HealthController) Readiness(ctx *gin.Context) {
+	health := hc.healthUsecase.Readiness(ctx.Request.Context())
+
+	status := http.StatusOK
+	if health.Status != server_errors.HealthUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	ctx.JSON(status, health)
+}
@@ -25,60 +25,30 @@ func NewLanguageController(usecase usecases.LanguageUseCase) LanguageController
 // AddLanguage :
 // Creates a new language inside the database based on the model received as parameter.
 //
-// Error: will throw LanguageTableMissing if the database is incorrectly set and the "languages" table is missing.
+// Error: will return StatusBadRequest if the body is invalid.
 //
-// Error: will throw LanguageParsingError if for some reason it is unable to parse the values it receives from the
-// database.
+// Error: will return StatusConflict if the language already exists.
 //
-// Error: will throw LanguageClosingTableError if it fails to close the database rows.
+// Error: will return StatusInternalServerError if the database is incorrectly set and the "languages" table is missing
+// or if it fails to store the language.
 func (lc *LanguageController) AddLanguage(ctx *gin.Context) {
 	var language models.Language
-	err := ctx.BindJSON(&language)
 
-	if err != nil {
-		server_errors.Log(server_errors.LanguageParsingError, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusBadRequest, models.Response{
-			Message: err.Error(),
-			Status:  http.StatusBadRequest,
-		})
+	if !bindJSON(ctx, &language) {
 		return
 	}
 
-	language, err = lc.languageUseCase.AddLanguage(language)
+	language, err := lc.languageUseCase.AddLanguage(ctx.Request.Context(), language)
 
 	if err != nil {
-		server_errors.Log(server_errors.LanguageNotAdded, server_errors.ErrorLevel)
-		switch err.Error() {
-		case server_errors.LanguageParsingError, server_errors.LanguageAlreadyExists:
-			ctx.JSON(http.StatusBadRequest, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusBadRequest,
-			})
-		default:
-			ctx.JSON(http.StatusInternalServerError, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-		}
+		ctx.Error(err)
 		return
 	}
 
-	createdLanguage, err := lc.languageUseCase.GetLanguageByName(language.Name)
+	createdLanguage, err := lc.languageUseCase.GetLanguageByName(ctx.Request.Context(), language.Name)
 
 	if err != nil {
-		server_errors.Log(server_errors.LanguageNotAdded, server_errors.ErrorLevel)
-		switch err.Error() {
-		case server_errors.LanguageNotFound:
-			ctx.JSON(http.StatusNotFound, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusNotFound,
-			})
-		default:
-			ctx.JSON(http.StatusInternalServerError, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-		}
+		ctx.Error(err)
 		return
 	}
 
@@ -91,36 +61,13 @@ func (lc *LanguageController) AddLanguage(ctx *gin.Context) {
 // Returns all the languages stored in the database. Even though it may fail, it should not crash the application at any
 // given moment.
 //
-// Error: will return StatusBadRequest if the body is invalid.
-//
-// Error: will return StatusInternalServerError if there's a problem while closing the "language" table or if it is
+// Error: will return StatusInternalServerError if there's a problem while reading the "language" table or if it is
 // missing.
 func (lc *LanguageController) GetLanguages(ctx *gin.Context) {
-	languages, err := lc.languageUseCase.GetLanguages()
+	languages, err := lc.languageUseCase.GetLanguages(ctx.Request.Context())
 
 	if err != nil {
-		switch err.Error() {
-		case server_errors.LanguageParsingError:
-			ctx.JSON(http.StatusBadRequest, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusBadRequest,
-			})
-		case server_errors.LanguageTableMissing:
-			ctx.JSON(http.StatusInternalServerError, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-		case server_errors.LanguageClosingTableError:
-			ctx.JSON(http.StatusInternalServerError, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-		default:
-			ctx.JSON(http.StatusInternalServerError, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-		}
+		ctx.Error(err)
 		return
 	}
 
@@ -131,40 +78,20 @@ func (lc *LanguageController) GetLanguages(ctx *gin.Context) {
 // Returns a "language" instance by id. Even though it may fail, it should not crash the application at any given
 // moment.
 //
-// Error: will return StatusBadRequest if the body is invalid.
+// Error: will return StatusBadRequest if the id is invalid.
 //
 // Error: will return StatusNotFound if a language with the provided id is not found.
 func (lc *LanguageController) GetLanguageById(ctx *gin.Context) {
-	id := ctx.Param("languageId")
-
-	if id == "" {
-		server_errors.Log(server_errors.EmptyIdError, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusBadRequest, models.Response{
-			Message: server_errors.EmptyIdError,
-			Status:  http.StatusBadRequest,
-		})
-		return
-	}
+	languageId, ok := idParam(ctx, "languageId")
 
-	languageId, err := strconv.Atoi(id)
-
-	if err != nil {
-		server_errors.Log(server_errors.InvalidIdError, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusBadRequest, models.Response{
-			Message: server_errors.InvalidIdError,
-			Status:  http.StatusBadRequest,
-		})
+	if !ok {
 		return
 	}
 
-	language, err := lc.languageUseCase.GetLanguageById(languageId)
+	language, err := lc.languageUseCase.GetLanguageById(ctx.Request.Context(), languageId)
 
 	if err != nil {
-		server_errors.Log(server_errors.LanguageNotFound, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusNotFound, models.Response{
-			Message: err.Error(),
-			Status:  http.StatusNotFound,
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -175,31 +102,91 @@ func (lc *LanguageController) GetLanguageById(ctx *gin.Context) {
 // Returns a "language" instance by name. Even though it may fail, it should not crash the application at any given
 // moment.
 //
-// Error: will return StatusBadRequest if the body is invalid.
+// Error: will return StatusBadRequest if the name is empty.
 //
 // Error: will return StatusNotFound if a language with the provided name is not found.
 func (lc *LanguageController) GetLanguageByName(ctx *gin.Context) {
 	name := ctx.Param("languageName")
 
 	if name == "" {
-		server_errors.Log(server_errors.EmptyNameError, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusBadRequest, models.Response{
-			Message: server_errors.EmptyNameError,
-			Status:  http.StatusBadRequest,
-		})
+		ctx.Error(server_errors.ErrEmptyNameError)
 		return
 	}
 
-	language, err := lc.languageUseCase.GetLanguageByName(name)
+	language, err := lc.languageUseCase.GetLanguageByName(ctx.Request.Context(), name)
 
 	if err != nil {
-		server_errors.Log(server_errors.LanguageNotFound, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusNotFound, models.Response{
-			Message: err.Error(),
-			Status:  http.StatusNotFound,
-		})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, language)
 }
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// RenameLanguage :
+// Changes the name of the language with the provided id to the one received in the body.
+//
+// Error: will return StatusBadRequest if the body is invalid or the name is empty.
+//
+// Error: will return StatusNotFound if a language with the provided id is not found.
+//
+// Error: will return StatusConflict if another language already uses the provided name.
+func (lc *LanguageController) RenameLanguage(ctx *gin.Context) {
+	languageId, ok := idParam(ctx, "languageId")
+
+	if !ok {
+		return
+	}
+
+	var language models.Language
+
+	if !bindJSON(ctx, &language) {
+		return
+	}
+
+	renamedLanguage, err := lc.languageUseCase.RenameLanguage(ctx.Request.Context(), languageId, language)
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, renamedLanguage)
+}
+
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// DeleteLanguage :
+// Removes the language with the provided id. The news outlets using it are only removed along with it when the
+// "cascade" query parameter is "true".
+//
+// Error: will return StatusBadRequest if the "cascade" query parameter is not a boolean.
+//
+// Error: will return StatusNotFound if a language with the provided id is not found.
+//
+// Error: will return StatusConflict if news outlets still use the language and cascade is not set.
+func (lc *LanguageController) DeleteLanguage(ctx *gin.Context) {
+	languageId, ok := idParam(ctx, "languageId")
+
+	if !ok {
+		return
+	}
+
+	cascade, err := strconv.ParseBool(ctx.DefaultQuery("cascade", "false"))
+
+	if err != nil {
+		ctx.Error(server_errors.ErrInvalidCascadeError.With(ctx.Query("cascade")))
+		return
+	}
+
+	err = lc.languageUseCase.DeleteLanguage(ctx.Request.Context(), languageId, cascade)
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	confirm(ctx, server_errors.LanguageDeleted)
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"aletheia-server/src/errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIdHeader :
+// Header carrying the correlation id of a request, both ways.
+const RequestIdHeader = "X-Request-Id"
+
+// validRequestId : request ids sent by the clients are only kept when they are short and free of control characters
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestLogger :
+// Middleware attaching a correlation id to every request, either the one sent in the X-Request-Id header or a new one,
+// and logging the request once it was served. The id is sent back in the same header and carried by every log written
+// with the context of the request, down to the crawlers it starts.
+func RequestLogger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		id := ctx.GetHeader(RequestIdHeader)
+		if !validRequestId.MatchString(id) {
+			id = server_errors.NewCorrelationId()
+		}
+
+		ctx.Header(RequestIdHeader, id)
+		ctx.Request = ctx.Request.WithContext(
+			server_errors.WithLogAttrs(ctx.Request.Context(), slog.String(server_errors.RequestIdKey, id)),
+		)
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := server_errors.InfoLevel
+		if status >= http.StatusInternalServerError {
+			level = server_errors.ErrorLevel
+		} else if status >= http.StatusBadRequest {
+			level = server_errors.WarningLevel
+		}
+
+		server_errors.LogContext(ctx.Request.Context(), "request served", level,
+			"method", ctx.Request.Method,
+			"path", ctx.Request.URL.Path,
+			"status", status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"client_ip", ctx.ClientIP(),
+		)
+	}
+}
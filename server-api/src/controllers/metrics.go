@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"aletheia-server/src/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute : route label of the requests matching no route, so unknown paths share a single series
+const unmatchedRoute = "unmatched"
+
+// RequestMetrics :
+// Middleware recording the count and the duration of every request served, by method, route and status.
+func RequestMetrics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		metrics.ObserveHttpRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
+
+// Metrics :
+// Serves the metrics of the server in the Prometheus text format.
+func Metrics(ctx *gin.Context) {
+	metrics.Handler().ServeHTTP(ctx.Writer, ctx.Request)
+}
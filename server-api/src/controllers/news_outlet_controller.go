@@ -6,7 +6,6 @@ import (
 	"aletheia-server/src/usecases"
 	"github.com/gin-gonic/gin"
 	"net/http"
-	"strconv"
 )
 
 type NewsOutletController struct {
@@ -24,61 +23,31 @@ func NewNewsOutletController(usecase usecases.NewsOutletUseCase) NewsOutletContr
 // AddNewsOutlet :
 // Creates a new news outlet inside the database based on the model received as parameter.
 //
-// Error: will throw LanguageTableMissing if the database is incorrectly set and the "languages" table is missing.
+// Error: will return StatusBadRequest if the body is invalid, if the query url lacks the QUERY_HERE placeholder or if
+// the language is not maintained inside the database.
 //
-// Error: will throw LanguageParsingError if for some reason it is unable to parse the values it receives from the
-// database.
+// Error: will return StatusConflict if another news outlet already uses the provided name.
 //
-// Error: will throw LanguageClosingTableError if it fails to close the database rows.
+// Error: will return StatusInternalServerError if the database fails to store the news outlet.
 func (no *NewsOutletController) AddNewsOutlet(ctx *gin.Context) {
 	var newsOutlet models.NewsOutlet
-	err := ctx.BindJSON(&newsOutlet)
 
-	if err != nil {
-		server_errors.Log(server_errors.NewsOutletParsingError, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusBadRequest, models.Response{
-			Message: err.Error(),
-			Status:  http.StatusBadRequest,
-		})
+	if !bindJSON(ctx, &newsOutlet) {
 		return
 	}
 
-	newsOutlet, err = no.newsOutletUsecase.AddNewsOutlet(newsOutlet)
+	newsOutlet, err := no.newsOutletUsecase.AddNewsOutlet(ctx.Request.Context(), newsOutlet)
 
 	if err != nil {
-		server_errors.Log(server_errors.NewsOutletNotAdded, server_errors.ErrorLevel)
-		switch err.Error() {
-		case server_errors.LanguageParsingError, server_errors.LanguageNotFound:
-			ctx.JSON(http.StatusBadRequest, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusBadRequest,
-			})
-		default:
-			ctx.JSON(http.StatusInternalServerError, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-		}
+		ctx.Error(err)
 		return
 	}
 
 	// Confirm it was correctly added
-	createdNewsOutlet, err := no.newsOutletUsecase.GetNewsOutletByName(newsOutlet.Name)
+	createdNewsOutlet, err := no.newsOutletUsecase.GetNewsOutletByName(ctx.Request.Context(), newsOutlet.Name)
 
 	if err != nil {
-		server_errors.Log(server_errors.NewsOutletNotAdded, server_errors.ErrorLevel)
-		switch err.Error() {
-		case server_errors.NewsOutletNotFound:
-			ctx.JSON(http.StatusNotFound, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusNotFound,
-			})
-		default:
-			ctx.JSON(http.StatusInternalServerError, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-		}
+		ctx.Error(err)
 		return
 	}
 
@@ -91,27 +60,13 @@ func (no *NewsOutletController) AddNewsOutlet(ctx *gin.Context) {
 // Returns all the news outlets stored in the database. Even though it may fail, it should not crash the application at
 // any given moment.
 //
-// Error: will return StatusBadRequest if the body is invalid.
-//
-// Error: will return StatusInternalServerError if there's a problem while closing the "news_outlet" table or if it is
+// Error: will return StatusInternalServerError if there's a problem while reading the "news_outlet" table or if it is
 // missing.
 func (no *NewsOutletController) GetNewsOutlets(ctx *gin.Context) {
-	newsOutlets, err := no.newsOutletUsecase.GetNewsOutlets()
+	newsOutlets, err := no.newsOutletUsecase.GetNewsOutlets(ctx.Request.Context())
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		switch err.Error() {
-		case server_errors.NewsOutletParsingError:
-			ctx.JSON(http.StatusBadRequest, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusBadRequest,
-			})
-		default:
-			ctx.JSON(http.StatusInternalServerError, models.Response{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-		}
+		ctx.Error(err)
 		return
 	}
 
@@ -122,29 +77,21 @@ func (no *NewsOutletController) GetNewsOutlets(ctx *gin.Context) {
 // Returns a "NewsOutlet" instance by name. Even though it may fail, it should not crash the application at any given
 // moment.
 //
-// Error: will return StatusBadRequest if the body is invalid.
+// Error: will return StatusBadRequest if the name is empty.
 //
 // Error: will return StatusNotFound if a news outlet with the provided name is not found.
 func (no *NewsOutletController) GetNewsOutletByName(ctx *gin.Context) {
 	name := ctx.Param("newsOutletName")
 
 	if name == "" {
-		server_errors.Log(server_errors.EmptyNameError, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusBadRequest, models.Response{
-			Message: server_errors.EmptyNameError,
-			Status:  http.StatusBadRequest,
-		})
+		ctx.Error(server_errors.ErrEmptyNameError)
 		return
 	}
 
-	newsOutlet, err := no.newsOutletUsecase.GetNewsOutletByName(name)
+	newsOutlet, err := no.newsOutletUsecase.GetNewsOutletByName(ctx.Request.Context(), name)
 
 	if err != nil {
-		server_errors.Log(server_errors.NewsOutletNotFound, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusNotFound, models.Response{
-			Message: err.Error(),
-			Status:  http.StatusNotFound,
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -155,42 +102,113 @@ func (no *NewsOutletController) GetNewsOutletByName(ctx *gin.Context) {
 // Returns a "NewsOutlet" instance by id. Even though it may fail, it should not crash the application at any given
 // moment.
 //
-// Error: will return StatusBadRequest if the body is invalid.
+// Error: will return StatusBadRequest if the id is invalid.
 //
-// Error: will return StatusNotFound if a news outlet with the provided name is not found.
+// Error: will return StatusNotFound if a news outlet with the provided id is not found.
 func (no *NewsOutletController) GetNewsOutletById(ctx *gin.Context) {
-	inputId := ctx.Param("newsOutletName")
+	id, ok := idParam(ctx, "newsOutletId")
 
-	if inputId == "" {
-		server_errors.Log(server_errors.EmptyNameError, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusBadRequest, models.Response{
-			Message: server_errors.EmptyNameError,
-			Status:  http.StatusBadRequest,
-		})
+	if !ok {
 		return
 	}
 
-	id, err := strconv.Atoi(inputId)
+	newsOutlet, err := no.newsOutletUsecase.GetNewsOutletById(ctx.Request.Context(), id)
 
 	if err != nil {
-		server_errors.Log(server_errors.InvalidIdError, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusBadRequest, models.Response{
-			Message: err.Error(),
-			Status:  http.StatusBadRequest,
-		})
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newsOutlet)
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// UpdateNewsOutlet :
+// Replaces every field of the news outlet with the provided id by the model received in the body.
+//
+// Error: will return StatusBadRequest if the body is invalid, if the query url lacks the QUERY_HERE placeholder or if
+// the language is not maintained inside the database.
+//
+// Error: will return StatusNotFound if a news outlet with the provided id is not found.
+//
+// Error: will return StatusConflict if another news outlet already uses the provided name.
+func (no *NewsOutletController) UpdateNewsOutlet(ctx *gin.Context) {
+	id, ok := idParam(ctx, "newsOutletId")
+
+	if !ok {
 		return
 	}
 
-	newsOutlet, err := no.newsOutletUsecase.GetNewsOutletById(id)
+	var newsOutlet models.NewsOutlet
+
+	if !bindJSON(ctx, &newsOutlet) {
+		return
+	}
+
+	updatedNewsOutlet, err := no.newsOutletUsecase.UpdateNewsOutlet(ctx.Request.Context(), id, newsOutlet)
 
 	if err != nil {
-		server_errors.Log(server_errors.NewsOutletNotFound, server_errors.ErrorLevel)
-		ctx.JSON(http.StatusNotFound, models.Response{
-			Message: err.Error(),
-			Status:  http.StatusNotFound,
-		})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, newsOutlet)
+	ctx.JSON(http.StatusOK, updatedNewsOutlet)
+}
+
+// PatchNewsOutlet :
+// Replaces only the fields present in the body of the news outlet with the provided id.
+//
+// Error: will return StatusBadRequest if the body is invalid or empty, if the query url lacks the QUERY_HERE
+// placeholder or if the language is not maintained inside the database.
+//
+// Error: will return StatusNotFound if a news outlet with the provided id is not found.
+//
+// Error: will return StatusConflict if another news outlet already uses the provided name.
+func (no *NewsOutletController) PatchNewsOutlet(ctx *gin.Context) {
+	id, ok := idParam(ctx, "newsOutletId")
+
+	if !ok {
+		return
+	}
+
+	var patch models.NewsOutletPatch
+
+	if !bindJSON(ctx, &patch) {
+		return
+	}
+
+	updatedNewsOutlet, err := no.newsOutletUsecase.PatchNewsOutlet(ctx.Request.Context(), id, patch)
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedNewsOutlet)
+}
+
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// DeleteNewsOutlet :
+// Removes the news outlet with the provided id from the database.
+//
+// Error: will return StatusNotFound if a news outlet with the provided id is not found.
+//
+// Error: will return StatusInternalServerError if the database fails to delete the row.
+func (no *NewsOutletController) DeleteNewsOutlet(ctx *gin.Context) {
+	id, ok := idParam(ctx, "newsOutletId")
+
+	if !ok {
+		return
+	}
+
+	err := no.newsOutletUsecase.DeleteNewsOutlet(ctx.Request.Context(), id)
+
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	confirm(ctx, server_errors.NewsOutletDeleted)
 }
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"aletheia-server/src/errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idParam :
+// Reads the integer id stored in the provided path parameter. When it is missing or invalid, an EmptyIdError or
+// InvalidIdError is attached to the request and false is returned.
+func idParam(ctx *gin.Context, param string) (int, bool) {
+	inputId := ctx.Param(param)
+
+	if inputId == "" {
+		ctx.Error(server_errors.ErrEmptyIdError)
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(inputId)
+
+	if err != nil {
+		ctx.Error(server_errors.ErrInvalidIdError.With(inputId))
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers describing the limits of the requests.
+const (
+	RetryAfterHeader          = "Retry-After"
+	CrawlQuotaRemainingHeader = "X-Crawl-Quota-Remaining"
+)
+
+// RateLimit :
+// Middleware refusing the requests of a client whose bucket in limiter is empty, with a 429 and a Retry-After header.
+// Clients are told apart by their API key, or by their address when the request went through no Authenticate
+// middleware.
+func RateLimit(limiter *repositories.RateLimiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ok, retryAfter := limiter.Allow(clientOf(ctx)); !ok {
+			tooManyRequests(ctx, server_errors.ErrRateLimited, retryAfter)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// AuthFailureLimit :
+// Middleware preceding Authenticate and limiting by address the requests it rejects. Each request answered with a 401
+// takes a token from the bucket of its address in limiter; once it is empty, the requests from that address are
+// refused with a 429 and a Retry-After header before their key is hashed and looked up. The requests whose key is
+// accepted take nothing, so clients sharing an address are still limited by key only.
+func AuthFailureLimit(limiter *repositories.RateLimiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		client := "ip:" + ctx.ClientIP()
+
+		if ok, retryAfter := limiter.Peek(client); !ok {
+			tooManyRequests(ctx, server_errors.ErrRateLimited, retryAfter)
+			return
+		}
+
+		ctx.Next()
+
+		if last := ctx.Errors.Last(); last != nil && server_errors.As(last.Err).Status == http.StatusUnauthorized {
+			limiter.Allow(client)
+		}
+	}
+}
+
+// ConsumeCrawlQuota :
+// Counts the request against the daily crawl quota of its client through consume, and reports whether it may go on
+// along with the quota. Controllers call it once the request is known to be valid, so a request refused for its body
+// costs nothing. Once the quota is exhausted, the request is refused with a 429 and a Retry-After header pointing at
+// the reset of the quota.
+func ConsumeCrawlQuota(ctx *gin.Context, consume func(ctx context.Context, client string) (models.Quota, error)) (models.Quota, bool) {
+	quota, err := consume(ctx.Request.Context(), clientOf(ctx))
+
+	if errors.Is(err, server_errors.ErrCrawlQuotaExceeded) {
+		ctx.Header(CrawlQuotaRemainingHeader, "0")
+		tooManyRequests(ctx, server_errors.ErrCrawlQuotaExceeded.With("resets at "+quota.ResetsAt.Format(time.RFC3339)),
+			time.Until(quota.ResetsAt))
+		return quota, false
+	}
+
+	if err != nil {
+		ctx.Error(err)
+		ctx.Abort()
+		return quota, false
+	}
+
+	if quota.Limit > 0 {
+		ctx.Header(CrawlQuotaRemainingHeader, strconv.Itoa(quota.Remaining))
+	}
+
+	return quota, true
+}
+
+// RefundCrawlQuota :
+// Gives back, through refund, the crawl counted by ConsumeCrawlQuota when the request failed before the crawl could
+// start, and updates the remaining quota told to the client. A failed refund is only logged, so the client is still
+// served the error that stopped the crawl.
+func RefundCrawlQuota(ctx *gin.Context, quota models.Quota, refund func(ctx context.Context, client string, quota models.Quota) (models.Quota, error)) {
+	quota, err := refund(ctx.Request.Context(), clientOf(ctx), quota)
+
+	if err != nil {
+		server_errors.LogContext(ctx.Request.Context(), "crawl quota could not be refunded", server_errors.WarningLevel,
+			"error", err.Error())
+		return
+	}
+
+	if quota.Limit > 0 {
+		ctx.Header(CrawlQuotaRemainingHeader, strconv.Itoa(quota.Remaining))
+	}
+}
+
+// clientOf :
+// Returns the identifier the limits of the request are tracked under: "key:<id>" for the requests authenticated with
+// an API key, "ip:<address>" otherwise.
+func clientOf(ctx *gin.Context) string {
+	if apiKey := CurrentApiKey(ctx); apiKey != nil {
+		return "key:" + strconv.Itoa(apiKey.Id)
+	}
+
+	return "ip:" + ctx.ClientIP()
+}
+
+// tooManyRequests :
+// Aborts the request with the provided error, telling the client how many seconds to wait before retrying.
+func tooManyRequests(ctx *gin.Context, err error, retryAfter time.Duration) {
+	ctx.Header(RetryAfterHeader, strconv.Itoa(max(1, int(math.Ceil(retryAfter.Seconds())))))
+	ctx.Error(err)
+	ctx.Abort()
+}
@@ -2,27 +2,103 @@ package db
 
 import (
 	"aletheia-server/src/errors"
+	"context"
 	"database/sql"
 	"fmt"
+	"math/rand"
+	"time"
+
+	_ "github.com/lib/pq" // Registers the "postgres" driver
+)
+
+const (
+	connectBaseBackoff = 500 * time.Millisecond
+	connectMaxBackoff  = 10 * time.Second
 )
 
-func ConnectDB() (*sql.DB, error) {
-	config := LoadConfig()
+// Connect :
+// Opens a connection pool to the database with the pool settings of config, then pings it until it answers. Failed
+// pings are retried with a jittered exponential backoff until config.ConnectTimeout elapses or ctx is cancelled, so a
+// database that starts slower than the server does not crash it. When config.Driver is SqliteDriver, the SQLite file
+// is opened instead.
+//
+// Error: will throw DatabaseUnreachable along with the number of attempts if the database never answered.
+func Connect(ctx context.Context, config Config) (*sql.DB, error) {
+	if config.Driver == SqliteDriver {
+		return connectSqlite(ctx, config)
+	}
+
 	psqlInfo := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		config.Host, config.Port, config.User, config.Password, config.DBName)
-	db, err := sql.Open("postgres", psqlInfo)
+	db, err := sql.Open(PostgresDriver, psqlInfo)
 
 	if err != nil {
-		panic(err)
+		return nil, server_errors.ErrDatabaseUnreachable.Wrap(err)
 	}
 
-	err = db.Ping()
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
 
-	if err != nil {
-		panic(err)
+	if err = waitFor(ctx, db, config.ConnectTimeout); err != nil {
+		_ = db.Close()
+		return nil, err
 	}
 
 	server_errors.Log(fmt.Sprintf("Connected to "+config.DBName), server_errors.InfoLevel)
 	return db, nil
 }
+
+// waitFor :
+// Pings the database until it answers, giving up once timeout elapses or ctx is cancelled.
+func waitFor(ctx context.Context, db *sql.DB, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+
+		if err == nil {
+			return nil
+		}
+
+		backoff := connectBackoff(attempt)
+		server_errors.LogContext(ctx, server_errors.DatabaseUnreachable, server_errors.WarningLevel,
+			"attempt", attempt, "retry_in", backoff.String(), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return server_errors.ErrDatabaseUnreachable.With(fmt.Sprintf("after %d attempts", attempt)).Wrap(err)
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// connectBackoff :
+// Returns how long to wait before the attempt following the provided one, doubling from connectBaseBackoff up to
+// connectMaxBackoff, with up to half of it randomized so restarted servers do not hammer the database together.
+func connectBackoff(attempt int) time.Duration {
+	backoff := connectMaxBackoff
+	if attempt < 16 {
+		backoff = min(connectBaseBackoff<<(attempt-1), connectMaxBackoff)
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// Ping :
+// Checks that the database can still be reached through the provided connection.
+//
+// Error: will throw DatabaseUnreachable if the database does not answer before ctx is done.
+func Ping(ctx context.Context, connection *sql.DB) error {
+	if err := connection.PingContext(ctx); err != nil {
+		return server_errors.ErrDatabaseUnreachable.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,371 @@
+package db
+
+import (
+	"aletheia-server/src/errors"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationFiles :
+// Numbered migrations shipped inside the binary. Every version has an "up" file applying it and a "down" file
+// reverting it, e.g. 0002_crawl_runs.up.sql and 0002_crawl_runs.down.sql. The Postgres migrations are at the root and
+// the SQLite ones, which keep the same versions, are in the "sqlite" directory.
+//
+//go:embed migrations/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// migrationsLockId :
+// Key of the Postgres advisory lock held while migrating, so two servers starting together never apply the same
+// migration twice.
+const migrationsLockId = 7_202_504
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration :
+// A single versioned change of the database schema.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus :
+// A migration along with the moment it was applied, which is nil while it is pending.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrations :
+// Returns the migrations of the provided driver embedded in the binary, sorted by version.
+//
+// Error: will throw MigrationLoadError if the embedded files are not valid migrations.
+func Migrations(driver string) ([]Migration, error) {
+	dir := "migrations"
+	if driver == SqliteDriver {
+		dir = "migrations/sqlite"
+	}
+
+	files, err := fs.Sub(migrationFiles, dir)
+
+	if err != nil {
+		return nil, server_errors.ErrMigrationLoadError.Wrap(err)
+	}
+
+	return LoadMigrations(files)
+}
+
+// LoadMigrations :
+// Reads the migrations stored at the root of fsys and returns them sorted by version.
+//
+// Error: will throw MigrationInvalidName if a file name does not follow the 0001_name.up.sql layout.
+//
+// Error: will throw MigrationDuplicateVersion if two migrations share a version.
+//
+// Error: will throw MigrationMissingDirection if a migration lacks its up or down file.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+
+	if err != nil {
+		return nil, server_errors.ErrMigrationLoadError.Wrap(err)
+	}
+
+	byVersion := make(map[int]*Migration)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, server_errors.ErrMigrationInvalidName.With(entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		name, direction := match[2], match[3]
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+
+		if err != nil {
+			return nil, server_errors.ErrMigrationLoadError.Wrap(err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != name {
+			return nil, server_errors.ErrMigrationDuplicateVersion.With(fmt.Sprintf("%04d", version))
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, server_errors.ErrMigrationMissingDirection.With(fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator :
+// Applies and reverts migrations, keeping track of the applied ones in the "schema_migrations" table.
+type Migrator struct {
+	connection *sql.DB
+	driver     string
+	migrations []Migration
+}
+
+func NewMigrator(connection *sql.DB, driver string, migrations []Migration) *Migrator {
+	return &Migrator{
+		connection: connection,
+		driver:     driver,
+		migrations: migrations,
+	}
+}
+
+// Up :
+// Applies every pending migration in order, each inside its own transaction, and returns the ones applied.
+//
+// Error: will throw MigrationFailed if a migration cannot be applied. The migrations applied before it are kept.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied := make([]Migration, 0)
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		appliedAt, err := appliedMigrations(ctx, conn)
+
+		if err != nil {
+			return err
+		}
+
+		for version := range appliedAt {
+			if m.find(version) == nil {
+				server_errors.Log(fmt.Sprintf("%s: %04d", server_errors.MigrationUnknownVersion, version), server_errors.WarningLevel)
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := appliedAt[migration.Version]; ok {
+				continue
+			}
+
+			err = runInTransaction(ctx, conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+
+			if err != nil {
+				return server_errors.ErrMigrationFailed.With(fmt.Sprintf("%04d_%s", migration.Version, migration.Name)).Wrap(err)
+			}
+
+			server_errors.Log(fmt.Sprintf("Applied migration %04d_%s", migration.Version, migration.Name), server_errors.InfoLevel)
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down :
+// Reverts the last steps applied migrations, newest first, and returns the ones reverted.
+//
+// Error: will throw MigrationUnknownVersion if an applied migration to revert is not shipped in this binary.
+//
+// Error: will throw MigrationRollbackFailed if a migration cannot be reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	reverted := make([]Migration, 0)
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		appliedAt, err := appliedMigrations(ctx, conn)
+
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int, 0, len(appliedAt))
+		for version := range appliedAt {
+			versions = append(versions, version)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for i := 0; i < steps && i < len(versions); i++ {
+			migration := m.find(versions[i])
+
+			if migration == nil {
+				return server_errors.ErrMigrationUnknownVersion.With(fmt.Sprintf("%04d", versions[i]))
+			}
+
+			err = runInTransaction(ctx, conn, migration.Down,
+				"DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+
+			if err != nil {
+				return server_errors.ErrMigrationRollbackFailed.With(fmt.Sprintf("%04d_%s", migration.Version, migration.Name)).Wrap(err)
+			}
+
+			server_errors.Log(fmt.Sprintf("Reverted migration %04d_%s", migration.Version, migration.Name), server_errors.InfoLevel)
+			reverted = append(reverted, *migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status :
+// Returns every known migration along with the moment it was applied, if it was.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		appliedAt, err := appliedMigrations(ctx, conn)
+
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if at, ok := appliedAt[migration.Version]; ok {
+				status.AppliedAt = &at
+			}
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// find :
+// Returns the migration with the provided version, or nil if this binary does not ship it.
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+
+	return nil
+}
+
+// withLock :
+// Runs fn on a dedicated connection holding the migrations advisory lock, creating the "schema_migrations" table
+// first if needed. SQLite has no advisory locks, but the file is only ever written by one connection at a time.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.connection.Conn(ctx)
+
+	if err != nil {
+		return server_errors.ErrMigrationLockError.Wrap(err)
+	}
+	defer conn.Close()
+
+	if m.driver == SqliteDriver {
+		_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    version    INTEGER PRIMARY KEY,
+    name       TEXT      NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`)
+
+		if err != nil {
+			return server_errors.ErrMigrationTableError.Wrap(err)
+		}
+
+		return fn(conn)
+	}
+
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationsLockId)
+
+	if err != nil {
+		return server_errors.ErrMigrationLockError.Wrap(err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationsLockId)
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    version    INT PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`)
+
+	if err != nil {
+		return server_errors.ErrMigrationTableError.Wrap(err)
+	}
+
+	return fn(conn)
+}
+
+// appliedMigrations :
+// Returns the moment each applied migration was applied, by version.
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+
+	if err != nil {
+		return nil, server_errors.ErrMigrationTableError.Wrap(err)
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[int]time.Time)
+
+	for rows.Next() {
+		var version int
+		var at time.Time
+
+		if err = rows.Scan(&version, &at); err != nil {
+			return nil, server_errors.ErrMigrationTableError.Wrap(err)
+		}
+
+		appliedAt[version] = at
+	}
+
+	return appliedAt, rows.Err()
+}
+
+// runInTransaction :
+// Runs the migration script and the bookkeeping statement inside a single transaction, so a failed migration leaves
+// neither schema changes nor a "schema_migrations" row behind.
+func runInTransaction(ctx context.Context, conn *sql.Conn, script string, bookkeeping string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
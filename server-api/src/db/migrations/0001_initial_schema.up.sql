@@ -1,19 +1,18 @@
-CREATE TABLE languages
+-- Deployments created before migrations existed already hold these tables, so every statement is idempotent
+CREATE TABLE IF NOT EXISTS languages
 (
     Id   SERIAL PRIMARY KEY,
     Name VARCHAR(255) UNIQUE NOT NULL
 );
 
-CREATE TABLE news_outlet
+CREATE TABLE IF NOT EXISTS news_outlet
 (
     Id           SERIAL PRIMARY KEY,
     Name         VARCHAR(255) UNIQUE NOT NULL,
     QueryUrl     TEXT                NOT NULL,
     HtmlSelector TEXT                NOT NULL,
     LanguageId   INT                 NOT NULL,
-    Credibility  INT                 NOT NULL
+    Credibility  INT                 NOT NULL,
+    CONSTRAINT fk_language
+        FOREIGN KEY (LanguageId) REFERENCES languages (Id) ON UPDATE CASCADE ON DELETE CASCADE
 );
-
-ALTER TABLE news_outlet
-    ADD CONSTRAINT fk_language
-        FOREIGN KEY (LanguageId) REFERENCES languages (Id) ON UPDATE CASCADE ON DELETE CASCADE;
\ No newline at end of file
@@ -0,0 +1,66 @@
+CREATE TABLE crawl_runs
+(
+    Id           SERIAL PRIMARY KEY,
+    Query        TEXT        NOT NULL,
+    PagesToVisit INT         NOT NULL,
+    StartedAt    TIMESTAMPTZ NOT NULL,
+    FinishedAt   TIMESTAMPTZ NOT NULL,
+    DurationMs   BIGINT      NOT NULL
+);
+
+CREATE INDEX crawl_runs_started_at_idx ON crawl_runs (StartedAt DESC);
+
+-- News outlets are stored by name so the history survives the deletion of an outlet
+CREATE TABLE crawler_runs
+(
+    Id            SERIAL PRIMARY KEY,
+    CrawlRunId    INT         NOT NULL REFERENCES crawl_runs (Id) ON DELETE CASCADE,
+    CrawlerId     INT         NOT NULL,
+    NewsOutlet    TEXT        NOT NULL,
+    Query         TEXT        NOT NULL,
+    Status        TEXT        NOT NULL,
+    Error         TEXT        NOT NULL DEFAULT '',
+    LinkExtractor TEXT        NOT NULL DEFAULT '',
+    VisitedUrls   TEXT[]      NOT NULL DEFAULT '{}',
+    StartedAt     TIMESTAMPTZ NOT NULL,
+    FinishedAt    TIMESTAMPTZ NOT NULL,
+    DurationMs    BIGINT      NOT NULL
+);
+
+CREATE INDEX crawler_runs_crawl_run_id_idx ON crawler_runs (CrawlRunId);
+
+CREATE TABLE articles
+(
+    Id           SERIAL PRIMARY KEY,
+    CrawlerRunId INT         NOT NULL REFERENCES crawler_runs (Id) ON DELETE CASCADE,
+    NewsOutlet   TEXT        NOT NULL,
+    Url          TEXT        NOT NULL,
+    CanonicalUrl TEXT        NOT NULL,
+    Title        TEXT        NOT NULL,
+    Byline       TEXT        NOT NULL DEFAULT '',
+    PublishedAt  TIMESTAMPTZ,
+    FetchedAt    TIMESTAMPTZ NOT NULL,
+    ContentHash  CHAR(64)    NOT NULL,
+    Text         TEXT        NOT NULL
+);
+
+CREATE INDEX articles_crawler_run_id_idx ON articles (CrawlerRunId);
+CREATE INDEX articles_content_hash_idx ON articles (ContentHash);
+
+CREATE TABLE verdicts
+(
+    Id         SERIAL PRIMARY KEY,
+    CrawlRunId INT              NOT NULL REFERENCES crawl_runs (Id) ON DELETE CASCADE,
+    PostUrl    TEXT             NOT NULL,
+    PostTitle  TEXT             NOT NULL,
+    PostText   TEXT             NOT NULL,
+    Prompt     TEXT             NOT NULL,
+    Verdict    TEXT             NOT NULL,
+    Score      DOUBLE PRECISION NOT NULL,
+    Analyses   JSONB            NOT NULL,
+    StartedAt  TIMESTAMPTZ      NOT NULL,
+    FinishedAt TIMESTAMPTZ      NOT NULL,
+    DurationMs BIGINT           NOT NULL
+);
+
+CREATE INDEX verdicts_crawl_run_id_idx ON verdicts (CrawlRunId);
@@ -0,0 +1,2 @@
+ALTER TABLE crawler_runs
+    DROP COLUMN IF EXISTS Failures;
@@ -0,0 +1,2 @@
+ALTER TABLE crawler_runs
+    ADD COLUMN Failures JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,12 @@
+-- Only the SHA-256 hash of each key is stored, the key itself is shown once when it is issued
+CREATE TABLE api_keys
+(
+    Id         SERIAL PRIMARY KEY,
+    Name       TEXT        NOT NULL,
+    Role       TEXT        NOT NULL CHECK (Role IN ('read', 'crawl', 'admin')),
+    Prefix     TEXT        NOT NULL,
+    KeyHash    CHAR(64)    NOT NULL UNIQUE,
+    CreatedAt  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    LastUsedAt TIMESTAMPTZ,
+    RevokedAt  TIMESTAMPTZ
+);
@@ -0,0 +1,8 @@
+-- Crawls and fact checks started by each client on each day, in UTC. Client is either "key:<api key id>" or "ip:<address>"
+CREATE TABLE crawl_quotas
+(
+    Client TEXT NOT NULL,
+    Day    DATE NOT NULL,
+    Used   INT  NOT NULL,
+    PRIMARY KEY (Client, Day)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS news_outlet;
+DROP TABLE IF EXISTS languages;
@@ -0,0 +1,18 @@
+-- AUTOINCREMENT keeps the ids of deleted rows from being reused, like the SERIAL columns of PostgreSQL
+CREATE TABLE languages
+(
+    Id   INTEGER PRIMARY KEY AUTOINCREMENT,
+    Name TEXT UNIQUE NOT NULL
+);
+
+CREATE TABLE news_outlet
+(
+    Id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    Name         TEXT UNIQUE NOT NULL,
+    QueryUrl     TEXT        NOT NULL,
+    HtmlSelector TEXT        NOT NULL,
+    LanguageId   INTEGER     NOT NULL,
+    Credibility  INTEGER     NOT NULL,
+    CONSTRAINT fk_language
+        FOREIGN KEY (LanguageId) REFERENCES languages (Id) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS verdicts;
+DROP TABLE IF EXISTS articles;
+DROP TABLE IF EXISTS crawler_runs;
+DROP TABLE IF EXISTS crawl_runs;
@@ -0,0 +1,67 @@
+-- The times are stored in UTC, so they sort as text. The lists are stored as JSON arrays
+CREATE TABLE crawl_runs
+(
+    Id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    Query        TEXT      NOT NULL,
+    PagesToVisit INTEGER   NOT NULL,
+    StartedAt    TIMESTAMP NOT NULL,
+    FinishedAt   TIMESTAMP NOT NULL,
+    DurationMs   INTEGER   NOT NULL
+);
+
+CREATE INDEX crawl_runs_started_at_idx ON crawl_runs (StartedAt DESC);
+
+-- News outlets are stored by name so the history survives the deletion of an outlet
+CREATE TABLE crawler_runs
+(
+    Id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    CrawlRunId    INTEGER   NOT NULL REFERENCES crawl_runs (Id) ON DELETE CASCADE,
+    CrawlerId     INTEGER   NOT NULL,
+    NewsOutlet    TEXT      NOT NULL,
+    Query         TEXT      NOT NULL,
+    Status        TEXT      NOT NULL,
+    Error         TEXT      NOT NULL DEFAULT '',
+    LinkExtractor TEXT      NOT NULL DEFAULT '',
+    VisitedUrls   TEXT      NOT NULL DEFAULT '[]',
+    StartedAt     TIMESTAMP NOT NULL,
+    FinishedAt    TIMESTAMP NOT NULL,
+    DurationMs    INTEGER   NOT NULL
+);
+
+CREATE INDEX crawler_runs_crawl_run_id_idx ON crawler_runs (CrawlRunId);
+
+CREATE TABLE articles
+(
+    Id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    CrawlerRunId INTEGER   NOT NULL REFERENCES crawler_runs (Id) ON DELETE CASCADE,
+    NewsOutlet   TEXT      NOT NULL,
+    Url          TEXT      NOT NULL,
+    CanonicalUrl TEXT      NOT NULL,
+    Title        TEXT      NOT NULL,
+    Byline       TEXT      NOT NULL DEFAULT '',
+    PublishedAt  TIMESTAMP,
+    FetchedAt    TIMESTAMP NOT NULL,
+    ContentHash  TEXT      NOT NULL,
+    Text         TEXT      NOT NULL
+);
+
+CREATE INDEX articles_crawler_run_id_idx ON articles (CrawlerRunId);
+CREATE INDEX articles_content_hash_idx ON articles (ContentHash);
+
+CREATE TABLE verdicts
+(
+    Id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    CrawlRunId INTEGER   NOT NULL REFERENCES crawl_runs (Id) ON DELETE CASCADE,
+    PostUrl    TEXT      NOT NULL,
+    PostTitle  TEXT      NOT NULL,
+    PostText   TEXT      NOT NULL,
+    Prompt     TEXT      NOT NULL,
+    Verdict    TEXT      NOT NULL,
+    Score      REAL      NOT NULL,
+    Analyses   TEXT      NOT NULL,
+    StartedAt  TIMESTAMP NOT NULL,
+    FinishedAt TIMESTAMP NOT NULL,
+    DurationMs INTEGER   NOT NULL
+);
+
+CREATE INDEX verdicts_crawl_run_id_idx ON verdicts (CrawlRunId);
@@ -0,0 +1,2 @@
+ALTER TABLE news_outlet
+    DROP COLUMN CrawlDelayMs;
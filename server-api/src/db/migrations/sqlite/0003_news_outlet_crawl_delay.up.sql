@@ -0,0 +1,2 @@
+ALTER TABLE news_outlet
+    ADD COLUMN CrawlDelayMs INTEGER NOT NULL DEFAULT 0 CHECK (CrawlDelayMs >= 0);
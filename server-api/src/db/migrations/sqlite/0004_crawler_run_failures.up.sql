@@ -0,0 +1,2 @@
+ALTER TABLE crawler_runs
+    ADD COLUMN Failures TEXT NOT NULL DEFAULT '[]';
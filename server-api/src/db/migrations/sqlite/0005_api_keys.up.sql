@@ -0,0 +1,12 @@
+-- Only the SHA-256 hash of each key is stored, the key itself is shown once when it is issued
+CREATE TABLE api_keys
+(
+    Id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    Name       TEXT      NOT NULL,
+    Role       TEXT      NOT NULL CHECK (Role IN ('read', 'crawl', 'admin')),
+    Prefix     TEXT      NOT NULL,
+    KeyHash    TEXT      NOT NULL UNIQUE,
+    CreatedAt  TIMESTAMP NOT NULL,
+    LastUsedAt TIMESTAMP,
+    RevokedAt  TIMESTAMP
+);
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"aletheia-server/src/errors"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+)
+
+// sqliteBusyTimeoutMs :
+// How long a statement waits for the file to be unlocked by another process before failing.
+const sqliteBusyTimeoutMs = 5000
+
+// connectSqlite :
+// Opens the SQLite file at config.Path, creating it if needed, with the foreign keys enforced and the write-ahead log
+// enabled so the readers never wait for the writer. Times are written in the format SQLite understands, so they can
+// still be compared and sorted in the queries. SQLite only allows one writer at a time, so the pool holds a
+// single connection and the statements queue in the server instead of failing with SQLITE_BUSY.
+//
+// Error: will throw DatabaseUnreachable if the file cannot be opened.
+func connectSqlite(ctx context.Context, config Config) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_time_format=sqlite",
+		(&url.URL{Path: config.Path}).EscapedPath(), sqliteBusyTimeoutMs)
+	db, err := sql.Open(SqliteDriver, dsn)
+
+	if err != nil {
+		return nil, server_errors.ErrDatabaseUnreachable.Wrap(err)
+	}
+
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, server_errors.ErrDatabaseUnreachable.Wrap(err)
+	}
+
+	server_errors.Log("Opened "+config.Path, server_errors.InfoLevel)
+	return db, nil
+}
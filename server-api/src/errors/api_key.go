@@ -0,0 +1,32 @@
+package server_errors
+
+import "net/http"
+
+const (
+	MissingApiKey         = "an API key is required, sent as a bearer token or in the X-Api-Key header"
+	InvalidApiKey         = "API key is unknown or revoked"
+	ForbiddenApiKey       = "API key role does not allow this action"
+	InvalidApiKeyRole     = "role should be read, crawl or admin"
+	ApiKeyNotFound        = "API key not found inside the database"
+	ApiKeyNotSaved        = "API key was not properly saved to the database"
+	ApiKeyNotRevoked      = "API key was not properly revoked"
+	ApiKeyRevoked         = "API key was revoked"
+	ApiKeysQueryError     = "API keys could not be queried from the database"
+	ApiKeyParsingError    = "API key could not be parsed from the database"
+	ApiKeyGenerationError = "API key could not be generated"
+	ApiKeyInvalidCommand  = "usage: apikey [create <name> <role> | list | revoke <id>]"
+)
+
+var (
+	ErrMissingApiKey         = New("MISSING_API_KEY", http.StatusUnauthorized, MissingApiKey)
+	ErrInvalidApiKey         = New("INVALID_API_KEY", http.StatusUnauthorized, InvalidApiKey)
+	ErrForbiddenApiKey       = New("FORBIDDEN_API_KEY", http.StatusForbidden, ForbiddenApiKey)
+	ErrInvalidApiKeyRole     = New("INVALID_API_KEY_ROLE", http.StatusBadRequest, InvalidApiKeyRole)
+	ErrApiKeyNotFound        = New("API_KEY_NOT_FOUND", http.StatusNotFound, ApiKeyNotFound)
+	ErrApiKeyNotSaved        = New("API_KEY_NOT_SAVED", http.StatusInternalServerError, ApiKeyNotSaved)
+	ErrApiKeyNotRevoked      = New("API_KEY_NOT_REVOKED", http.StatusInternalServerError, ApiKeyNotRevoked)
+	ErrApiKeysQueryError     = New("API_KEYS_QUERY_ERROR", http.StatusInternalServerError, ApiKeysQueryError)
+	ErrApiKeyParsingError    = New("API_KEY_PARSING_ERROR", http.StatusInternalServerError, ApiKeyParsingError)
+	ErrApiKeyGenerationError = New("API_KEY_GENERATION_ERROR", http.StatusInternalServerError, ApiKeyGenerationError)
+	ErrApiKeyInvalidCommand  = New("API_KEY_INVALID_COMMAND", http.StatusBadRequest, ApiKeyInvalidCommand)
+)
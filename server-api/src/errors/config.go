@@ -0,0 +1,15 @@
+package server_errors
+
+import "net/http"
+
+const (
+	ConfigFileError      = "configuration file could not be read"
+	ConfigInvalid        = "configuration is invalid"
+	ConfigInvalidCommand = "config command should be \"print\""
+)
+
+var (
+	ErrConfigFileError      = New("CONFIG_FILE_ERROR", http.StatusInternalServerError, ConfigFileError)
+	ErrConfigInvalid        = New("CONFIG_INVALID", http.StatusInternalServerError, ConfigInvalid)
+	ErrConfigInvalidCommand = New("CONFIG_INVALID_COMMAND", http.StatusBadRequest, ConfigInvalidCommand)
+)
@@ -0,0 +1,21 @@
+package server_errors
+
+import "net/http"
+
+const (
+	CrawlRunNotFound       = "crawl run not found inside the database"
+	CrawlRunNotSaved       = "crawl run was not properly saved to the database"
+	CrawlRunParsingError   = "crawl run could not be parsed from the database"
+	CrawlRunsQueryError    = "crawl runs could not be queried from the database"
+	VerdictNotSaved        = "verdict was not properly saved to the database"
+	InvalidPaginationError = "limit and offset should be non negative integers"
+)
+
+var (
+	ErrCrawlRunNotFound       = New("CRAWL_RUN_NOT_FOUND", http.StatusNotFound, CrawlRunNotFound)
+	ErrCrawlRunNotSaved       = New("CRAWL_RUN_NOT_SAVED", http.StatusInternalServerError, CrawlRunNotSaved)
+	ErrCrawlRunParsingError   = New("CRAWL_RUN_PARSING_ERROR", http.StatusInternalServerError, CrawlRunParsingError)
+	ErrCrawlRunsQueryError    = New("CRAWL_RUNS_QUERY_ERROR", http.StatusInternalServerError, CrawlRunsQueryError)
+	ErrVerdictNotSaved        = New("VERDICT_NOT_SAVED", http.StatusInternalServerError, VerdictNotSaved)
+	ErrInvalidPaginationError = New("INVALID_PAGINATION_ERROR", http.StatusBadRequest, InvalidPaginationError)
+)
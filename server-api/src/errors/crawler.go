@@ -1,17 +1,39 @@
 package server_errors
 
+import "net/http"
+
 const (
 	CrawlerReady     = "crawler is ready"
 	CrawlerRunning   = "crawler is running"
 	CrawlerSucceeded = "crawler successfully crawled"
+	CrawlerFailed    = "crawler failed"
+	CrawlerCancelled = "crawler was cancelled"
 )
 
 const (
-	CrawlerEmptyQuery        = "crawler query cannot be empty"
-	CrawlerEmptyQueryUrl     = "crawler query url cannot be empty"
-	CrawlerEmptyHtmlSelector = "crawler html selector cannot be empty"
-	CrawlerFilledPagesBodies = "crawler filled pages bodies needs to be empty"
-	CrawlerClosingPageError  = "crawler did not close the page properly"
+	CrawlJobQueued    = "crawl job is queued"
+	CrawlJobRunning   = "crawl job is running"
+	CrawlJobSucceeded = "crawl job finished"
+	CrawlJobCancelled = "crawl job was cancelled"
+	CrawlJobFailed    = "crawl job failed"
+)
+
+const (
+	CrawlJobNotFound        = "crawl job not found"
+	CrawlJobAlreadyFinished = "crawl job already finished"
+	CrawlJobIdError         = "unable to generate crawl job id"
+	CrawlJobsDraining       = "server is shutting down, no crawl job can be started"
+)
+
+const (
+	CrawlerEmptyQuery          = "crawler query cannot be empty"
+	CrawlerEmptyQueryUrl       = "crawler query url cannot be empty"
+	CrawlerEmptyHtmlSelector   = "crawler html selector cannot be empty"
+	CrawlerInvalidHtmlSelector = "crawler html selector is not a valid CSS selector"
+	CrawlerFilledPagesBodies   = "crawler filled pages bodies needs to be empty"
+	CrawlerClosingPageError    = "crawler did not close the page properly"
+	CrawlerRobotsDisallowed    = "crawling disallowed by robots.txt"
+	CrawlerRobotsParsingError  = "unable to parse robots.txt"
 )
 
 const (
@@ -19,8 +41,35 @@ const (
 )
 
 const (
-	JSONSerializationFailed = "unable to serialize JSON:"
-	FileOpenError           = "unable to open file:"
-	FileWriteError          = "unable to write file:"
-	HttpFetchError          = "unable to fetch URL:"
+	JSONSerializationFailed = "unable to serialize JSON"
+	FileOpenError           = "unable to open file"
+	FileWriteError          = "unable to write file"
+	FileSaveError           = "unable to save crawl results to file"
+	HttpFetchError          = "unable to fetch URL"
+	HttpRequestFailed       = "http request failed"
+	HttpBodyTooLarge        = "response body is too large"
+	HttpRetrying            = "retryable failure on"
+	HttpUnexpectedStatus    = "unexpected response status"
+	HttpForbiddenAddress    = "refusing to connect to a non public address"
+	HttpForbiddenScheme     = "only http and https URLs can be fetched"
+	ArticleExtractionError  = "unable to extract the article from"
+)
+
+var (
+	ErrCrawlJobNotFound           = New("CRAWL_JOB_NOT_FOUND", http.StatusNotFound, CrawlJobNotFound)
+	ErrCrawlJobAlreadyFinished    = New("CRAWL_JOB_ALREADY_FINISHED", http.StatusConflict, CrawlJobAlreadyFinished)
+	ErrCrawlJobIdError            = New("CRAWL_JOB_ID_ERROR", http.StatusInternalServerError, CrawlJobIdError)
+	ErrCrawlJobsDraining          = New("CRAWL_JOBS_DRAINING", http.StatusServiceUnavailable, CrawlJobsDraining)
+	ErrCrawlerEmptyHtmlSelector   = New("CRAWLER_EMPTY_HTML_SELECTOR", http.StatusBadRequest, CrawlerEmptyHtmlSelector)
+	ErrCrawlerInvalidHtmlSelector = New("CRAWLER_INVALID_HTML_SELECTOR", http.StatusBadRequest, CrawlerInvalidHtmlSelector)
+	ErrCrawlerRobotsDisallowed    = New("CRAWLER_ROBOTS_DISALLOWED", http.StatusForbidden, CrawlerRobotsDisallowed)
+	ErrNoCrawlersInitialized      = New("NO_CRAWLERS_INITIALIZED", http.StatusBadRequest, NoCrawlersInitialized)
+	ErrJSONSerializationFailed    = New("JSON_SERIALIZATION_FAILED", http.StatusInternalServerError, JSONSerializationFailed)
+	ErrFileOpenError              = New("FILE_OPEN_ERROR", http.StatusInternalServerError, FileOpenError)
+	ErrFileWriteError             = New("FILE_WRITE_ERROR", http.StatusInternalServerError, FileWriteError)
+	ErrHttpRequestFailed          = New("HTTP_REQUEST_FAILED", http.StatusBadGateway, HttpRequestFailed)
+	ErrHttpBodyTooLarge           = New("HTTP_BODY_TOO_LARGE", http.StatusBadGateway, HttpBodyTooLarge)
+	ErrHttpUnexpectedStatus       = New("HTTP_UNEXPECTED_STATUS", http.StatusBadGateway, HttpUnexpectedStatus)
+	ErrHttpForbiddenAddress       = New("HTTP_FORBIDDEN_ADDRESS", http.StatusForbidden, HttpForbiddenAddress)
+	ErrHttpForbiddenScheme        = New("HTTP_FORBIDDEN_SCHEME", http.StatusBadRequest, HttpForbiddenScheme)
 )
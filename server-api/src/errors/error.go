@@ -0,0 +1,94 @@
+package server_errors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Codes of the errors that are not tied to a domain.
+const (
+	CodeOK       = "OK"
+	CodeInternal = "INTERNAL_ERROR"
+)
+
+// Error :
+// Typed error carrying a stable machine-readable Code, the HTTP Status it is served with and a human-readable Message.
+// Details adds request specific information meant for the client, while the wrapped cause is only meant for the logs.
+// Two errors are considered the same by errors.Is when they share their Code, so sentinels keep matching once details
+// are added or a cause is wrapped.
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Details string
+	cause   error
+}
+
+// New :
+// Creates a new Error. It is meant to declare sentinels, which are then specialized through With and Wrap.
+func New(code string, status int, message string) *Error {
+	return &Error{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+// Error :
+// Returns the message along with the details and the cause, if any.
+func (e *Error) Error() string {
+	message := e.Message
+
+	if e.Details != "" {
+		message += ": " + e.Details
+	}
+
+	if e.cause != nil {
+		message += ": " + e.cause.Error()
+	}
+
+	return message
+}
+
+// Unwrap :
+// Returns the cause of the error, if any.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is :
+// Reports whether target is an Error with the same Code.
+func (e *Error) Is(target error) bool {
+	other, ok := target.(*Error)
+	return ok && e.Code == other.Code
+}
+
+// With :
+// Returns a copy of the error carrying the provided details.
+func (e *Error) With(details string) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// Wrap :
+// Returns a copy of the error wrapping the provided cause, which is left out of the responses.
+func (e *Error) Wrap(cause error) *Error {
+	copied := *e
+	copied.cause = cause
+	return &copied
+}
+
+// As :
+// Returns the first Error in the chain of err. Any other error is reported as an internal error wrapping it, so its
+// message never reaches the client.
+func As(err error) *Error {
+	var typed *Error
+	if errors.As(err, &typed) {
+		return typed
+	}
+
+	return ErrInternal.Wrap(err)
+}
+
+var ErrInternal = New(CodeInternal, http.StatusInternalServerError, "internal server error")
@@ -0,0 +1,24 @@
+package server_errors
+
+import "net/http"
+
+const (
+	FactCheckEmptyUrl         = "the url of the post to be fact-checked cannot be empty"
+	FactCheckEmptyPost        = "no content could be extracted from the post"
+	FactCheckPostFetchError   = "unable to fetch the post to be fact-checked"
+	FactCheckMediaUnsupported = "image and video analysis are not supported yet, only the text of the post is checked"
+	ArticleEmptyText          = "no text could be extracted from the article"
+)
+
+const (
+	AnalyzerRequestError  = "unable to send the request to the AI analyzer"
+	AnalyzerResponseError = "the AI analyzer returned an invalid response"
+)
+
+var (
+	ErrFactCheckEmptyUrl       = New("FACT_CHECK_EMPTY_URL", http.StatusBadRequest, FactCheckEmptyUrl)
+	ErrFactCheckEmptyPost      = New("FACT_CHECK_EMPTY_POST", http.StatusBadRequest, FactCheckEmptyPost)
+	ErrFactCheckPostFetchError = New("FACT_CHECK_POST_FETCH_ERROR", http.StatusBadRequest, FactCheckPostFetchError)
+	ErrAnalyzerRequestError    = New("ANALYZER_REQUEST_ERROR", http.StatusBadGateway, AnalyzerRequestError)
+	ErrAnalyzerResponseError   = New("ANALYZER_RESPONSE_ERROR", http.StatusBadGateway, AnalyzerResponseError)
+)
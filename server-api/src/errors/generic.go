@@ -1,8 +1,23 @@
 package server_errors
 
+import "net/http"
+
 const (
-	EmptyIdError      = "id cannot be empty"
-	InvalidIdError    = "id should be an integer"
-	EmptyNameError    = "name cannot be empty"
-	InvalidParameters = "invalid parameters"
+	EmptyIdError        = "id cannot be empty"
+	InvalidIdError      = "id should be an integer"
+	EmptyNameError      = "name cannot be empty"
+	InvalidParameters   = "invalid parameters"
+	InvalidCascadeError = "cascade should be a boolean"
+	RouteNotFound       = "route not found"
+	MethodNotAllowed    = "method not allowed on this route"
+)
+
+var (
+	ErrEmptyIdError        = New("EMPTY_ID_ERROR", http.StatusBadRequest, EmptyIdError)
+	ErrInvalidIdError      = New("INVALID_ID_ERROR", http.StatusBadRequest, InvalidIdError)
+	ErrEmptyNameError      = New("EMPTY_NAME_ERROR", http.StatusBadRequest, EmptyNameError)
+	ErrInvalidParameters   = New("INVALID_PARAMETERS", http.StatusBadRequest, InvalidParameters)
+	ErrInvalidCascadeError = New("INVALID_CASCADE_ERROR", http.StatusBadRequest, InvalidCascadeError)
+	ErrRouteNotFound       = New("ROUTE_NOT_FOUND", http.StatusNotFound, RouteNotFound)
+	ErrMethodNotAllowed    = New("METHOD_NOT_ALLOWED", http.StatusMethodNotAllowed, MethodNotAllowed)
 )
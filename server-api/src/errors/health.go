@@ -0,0 +1,18 @@
+package server_errors
+
+import "net/http"
+
+const (
+	HealthUp   = "up"
+	HealthDown = "down"
+)
+
+const (
+	DatabaseUnreachable = "database could not be reached"
+	AnalyzerUnreachable = "AI analyzer could not be reached"
+)
+
+var (
+	ErrDatabaseUnreachable = New("DATABASE_UNREACHABLE", http.StatusServiceUnavailable, DatabaseUnreachable)
+	ErrAnalyzerUnreachable = New("ANALYZER_UNREACHABLE", http.StatusServiceUnavailable, AnalyzerUnreachable)
+)
@@ -1,5 +1,7 @@
 package server_errors
 
+import "net/http"
+
 const (
 	LanguageNotFound          = "language not found inside the database"
 	LanguageAlreadyExists     = "language already exists inside the database"
@@ -7,4 +9,21 @@ const (
 	LanguageParsingError      = "language row could not be parsed from the database"
 	LanguageClosingTableError = "language table could not be closed properly"
 	LanguageNotAdded          = "language was not properly added to the database"
+	LanguageNotUpdated        = "language was not properly updated inside the database"
+	LanguageNotDeleted        = "language was not properly deleted from the database"
+	LanguageDeleted           = "language was deleted from the database"
+	LanguageEmptyName         = "language name cannot be empty"
+	LanguageInUse             = "language is still used by news outlets, pass cascade=true to delete them as well"
+)
+
+var (
+	ErrLanguageNotFound          = New("LANGUAGE_NOT_FOUND", http.StatusNotFound, LanguageNotFound)
+	ErrLanguageAlreadyExists     = New("LANGUAGE_ALREADY_EXISTS", http.StatusConflict, LanguageAlreadyExists)
+	ErrLanguageTableMissing      = New("LANGUAGE_TABLE_MISSING", http.StatusInternalServerError, LanguageTableMissing)
+	ErrLanguageParsingError      = New("LANGUAGE_PARSING_ERROR", http.StatusInternalServerError, LanguageParsingError)
+	ErrLanguageClosingTableError = New("LANGUAGE_CLOSING_TABLE_ERROR", http.StatusInternalServerError, LanguageClosingTableError)
+	ErrLanguageNotUpdated        = New("LANGUAGE_NOT_UPDATED", http.StatusInternalServerError, LanguageNotUpdated)
+	ErrLanguageNotDeleted        = New("LANGUAGE_NOT_DELETED", http.StatusInternalServerError, LanguageNotDeleted)
+	ErrLanguageEmptyName         = New("LANGUAGE_EMPTY_NAME", http.StatusBadRequest, LanguageEmptyName)
+	ErrLanguageInUse             = New("LANGUAGE_IN_USE", http.StatusConflict, LanguageInUse)
 )
@@ -1,32 +1,152 @@
 package server_errors
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
 )
 
 const (
+	DebugLevel   = "debug"
 	InfoLevel    = "info"
 	WarningLevel = "warning"
 	ErrorLevel   = "error"
 )
 
+// Formats the logs can be written in.
 const (
-	defaultColor = "\033[0m"
-	errorColor   = "\033[91m"
-	infoColor    = "\033[96m"
-	warningColor = "\033[93m"
+	TextFormat = "text"
+	JSONFormat = "json"
 )
 
+// Keys of the correlation ids attached to the logs.
+const (
+	RequestIdKey  = "request_id"
+	CrawlJobIdKey = "crawl_job_id"
+	CrawlerIdKey  = "crawler_id"
+	ApiKeyIdKey   = "api_key_id"
+)
+
+// LogConfig :
+// Settings of the server logs. Level is one of the level constants and Format is either TextFormat or JSONFormat.
+type LogConfig struct {
+	Level  string
+	Format string
+}
+
+var slogLevels = map[string]slog.Level{
+	DebugLevel:   slog.LevelDebug,
+	InfoLevel:    slog.LevelInfo,
+	WarningLevel: slog.LevelWarn,
+	ErrorLevel:   slog.LevelError,
+}
+
+// SetupLogger :
+// Makes the logger described by config, writing to w, the default one of the server.
+func SetupLogger(config LogConfig, w io.Writer) {
+	slog.SetDefault(NewLogger(config, w))
+}
+
+// NewLogger :
+// Creates a structured logger writing to w. Every record logged with a context carries the correlation ids attached to
+// it through WithLogAttrs.
+func NewLogger(config LogConfig, w io.Writer) *slog.Logger {
+	options := &slog.HandlerOptions{Level: levelOf(config.Level)}
+
+	var handler slog.Handler
+	if config.Format == JSONFormat {
+		handler = slog.NewJSONHandler(w, options)
+	} else {
+		handler = slog.NewTextHandler(w, options)
+	}
+
+	return slog.New(contextHandler{handler})
+}
+
+// Log :
+// Writes a message at the provided level through the default logger. Unknown levels are logged as info.
 func Log(message string, level string) {
-	switch level {
-	case InfoLevel:
-		log.Println(fmt.Sprintf("%sinfo: %s %s", infoColor, message, defaultColor))
-	case WarningLevel:
-		log.Println(fmt.Sprintf("%swarning: %s %s", warningColor, message, defaultColor))
-	case ErrorLevel:
-		log.Println(fmt.Sprintf("%serror: %s %s", errorColor, message, defaultColor))
-	default:
-		log.Println(fmt.Sprintf("%sinfo: %s %s", infoColor, message, defaultColor))
+	slog.Log(context.Background(), levelOf(level), message)
+}
+
+// LogContext :
+// Writes a message at the provided level through the default logger, along with the correlation ids attached to ctx
+// and the provided key-value pairs.
+func LogContext(ctx context.Context, message string, level string, args ...any) {
+	slog.Log(ctx, levelOf(level), message, args...)
+}
+
+// LogEnabled :
+// Reports whether a message at the provided level would be written, to skip building expensive debug messages.
+func LogEnabled(ctx context.Context, level string) bool {
+	return slog.Default().Enabled(ctx, levelOf(level))
+}
+
+// levelOf :
+// Returns the slog level matching one of the level constants.
+func levelOf(level string) slog.Level {
+	if slogLevel, ok := slogLevels[level]; ok {
+		return slogLevel
+	}
+
+	return slog.LevelInfo
+}
+
+// Correlation ids -----------------------------------------------------------------------------------------------------
+
+type logAttrsKey struct{}
+
+// WithLogAttrs :
+// Returns a copy of ctx whose logs carry the provided attributes on top of the ones already attached to it.
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing := LogAttrs(ctx)
+
+	combined := make([]slog.Attr, 0, len(existing)+len(attrs))
+	combined = append(combined, existing...)
+	combined = append(combined, attrs...)
+
+	return context.WithValue(ctx, logAttrsKey{}, combined)
+}
+
+// LogAttrs :
+// Returns the attributes attached to ctx through WithLogAttrs.
+func LogAttrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// NewCorrelationId :
+// Generates a random identifier used to correlate the logs of a request or a crawl.
+func NewCorrelationId() string {
+	id := make([]byte, 8)
+
+	if _, err := rand.Read(id); err != nil {
+		return "unknown"
 	}
+
+	return hex.EncodeToString(id)
+}
+
+// contextHandler :
+// Handler adding the attributes attached to the context of each record before passing it along.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := LogAttrs(ctx); len(attrs) > 0 {
+		record.AddAttrs(attrs...)
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
 }
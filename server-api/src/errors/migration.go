@@ -0,0 +1,30 @@
+package server_errors
+
+import "net/http"
+
+const (
+	MigrationInvalidName      = "migration file name should look like 0001_name.up.sql or 0001_name.down.sql"
+	MigrationDuplicateVersion = "migration version is used by more than one migration"
+	MigrationMissingDirection = "migration is missing its up or down file"
+	MigrationLoadError        = "migrations could not be loaded"
+	MigrationTableError       = "schema_migrations table could not be created"
+	MigrationLockError        = "migrations lock could not be acquired"
+	MigrationFailed           = "migration could not be applied"
+	MigrationRollbackFailed   = "migration could not be rolled back"
+	MigrationUnknownVersion   = "database holds a migration version unknown to this binary"
+	MigrationInvalidCommand   = "usage: migrate [up | down [steps] | status]"
+	MigrationsApplied         = "database schema is up to date"
+)
+
+var (
+	ErrMigrationInvalidName      = New("MIGRATION_INVALID_NAME", http.StatusInternalServerError, MigrationInvalidName)
+	ErrMigrationDuplicateVersion = New("MIGRATION_DUPLICATE_VERSION", http.StatusInternalServerError, MigrationDuplicateVersion)
+	ErrMigrationMissingDirection = New("MIGRATION_MISSING_DIRECTION", http.StatusInternalServerError, MigrationMissingDirection)
+	ErrMigrationLoadError        = New("MIGRATION_LOAD_ERROR", http.StatusInternalServerError, MigrationLoadError)
+	ErrMigrationTableError       = New("MIGRATION_TABLE_ERROR", http.StatusInternalServerError, MigrationTableError)
+	ErrMigrationLockError        = New("MIGRATION_LOCK_ERROR", http.StatusInternalServerError, MigrationLockError)
+	ErrMigrationFailed           = New("MIGRATION_FAILED", http.StatusInternalServerError, MigrationFailed)
+	ErrMigrationRollbackFailed   = New("MIGRATION_ROLLBACK_FAILED", http.StatusInternalServerError, MigrationRollbackFailed)
+	ErrMigrationUnknownVersion   = New("MIGRATION_UNKNOWN_VERSION", http.StatusInternalServerError, MigrationUnknownVersion)
+	ErrMigrationInvalidCommand   = New("MIGRATION_INVALID_COMMAND", http.StatusBadRequest, MigrationInvalidCommand)
+)
@@ -1,5 +1,7 @@
 package server_errors
 
+import "net/http"
+
 const (
 	NewsOutletNotFound          = "news outlet not found inside the database"
 	NewsOutletAlreadyExists     = "news outlet already exists inside the database"
@@ -7,4 +9,31 @@ const (
 	NewsOutletParsingError      = "news outlet could not be parsed from the database"
 	NewsOutletClosingTableError = "news outlet table could not be closed properly"
 	NewsOutletNotAdded          = "news outlet was not properly added to the database"
+	NewsOutletNotUpdated        = "news outlet was not properly updated inside the database"
+	NewsOutletNotDeleted        = "news outlet was not properly deleted from the database"
+	NewsOutletDeleted           = "news outlet was deleted from the database"
+)
+
+const (
+	NewsOutletEmptyName          = "news outlet name cannot be empty"
+	NewsOutletMissingQueryHere   = "news outlet query url must contain the QUERY_HERE placeholder"
+	NewsOutletNegativeCrawlDelay = "news outlet crawl delay cannot be negative"
+	NewsOutletEmptyPatch         = "news outlet patch does not change any field"
+)
+
+var (
+	ErrNewsOutletNotFound           = New("NEWS_OUTLET_NOT_FOUND", http.StatusNotFound, NewsOutletNotFound)
+	ErrNewsOutletAlreadyExists      = New("NEWS_OUTLET_ALREADY_EXISTS", http.StatusConflict, NewsOutletAlreadyExists)
+	ErrNewsOutletTableMissing       = New("NEWS_OUTLET_TABLE_MISSING", http.StatusInternalServerError, NewsOutletTableMissing)
+	ErrNewsOutletParsingError       = New("NEWS_OUTLET_PARSING_ERROR", http.StatusInternalServerError, NewsOutletParsingError)
+	ErrNewsOutletClosingTableError  = New("NEWS_OUTLET_CLOSING_TABLE_ERROR", http.StatusInternalServerError, NewsOutletClosingTableError)
+	ErrNewsOutletNotUpdated         = New("NEWS_OUTLET_NOT_UPDATED", http.StatusInternalServerError, NewsOutletNotUpdated)
+	ErrNewsOutletNotDeleted         = New("NEWS_OUTLET_NOT_DELETED", http.StatusInternalServerError, NewsOutletNotDeleted)
+	ErrNewsOutletEmptyName          = New("NEWS_OUTLET_EMPTY_NAME", http.StatusBadRequest, NewsOutletEmptyName)
+	ErrNewsOutletMissingQueryHere   = New("NEWS_OUTLET_MISSING_QUERY_HERE", http.StatusBadRequest, NewsOutletMissingQueryHere)
+	ErrNewsOutletNegativeCrawlDelay = New("NEWS_OUTLET_NEGATIVE_CRAWL_DELAY", http.StatusBadRequest, NewsOutletNegativeCrawlDelay)
+	ErrNewsOutletEmptyPatch         = New("NEWS_OUTLET_EMPTY_PATCH", http.StatusBadRequest, NewsOutletEmptyPatch)
+	// ErrNewsOutletUnknownLanguage : a news outlet refers to a missing language, which is an invalid body rather than a
+	// missing resource
+	ErrNewsOutletUnknownLanguage = New("NEWS_OUTLET_UNKNOWN_LANGUAGE", http.StatusBadRequest, LanguageNotFound)
 )
@@ -0,0 +1,15 @@
+package server_errors
+
+import "net/http"
+
+const (
+	RateLimited        = "too many requests, slow down"
+	CrawlQuotaExceeded = "daily crawl quota exceeded"
+	CrawlQuotaError    = "crawl quota could not be updated in the database"
+)
+
+var (
+	ErrRateLimited        = New("RATE_LIMITED", http.StatusTooManyRequests, RateLimited)
+	ErrCrawlQuotaExceeded = New("CRAWL_QUOTA_EXCEEDED", http.StatusTooManyRequests, CrawlQuotaExceeded)
+	ErrCrawlQuotaError    = New("CRAWL_QUOTA_ERROR", http.StatusInternalServerError, CrawlQuotaError)
+)
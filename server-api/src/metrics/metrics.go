@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "aletheia"
+
+// Outcomes of the outbound calls, used as the "result" label.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Endpoints of the AI analyzer, used as the "endpoint" label. They only name the endpoints in the metrics, the
+// requests are routed by the analyzer repository.
+const (
+	AnalyzerGetLinks = "/getLinks"
+	AnalyzerAnalyze  = "/analyze"
+)
+
+// Registry :
+// Registry holding every metric of the server, along with the Go runtime and process ones. It is kept apart from the
+// default registry of Prometheus so nothing registers into it by side effect.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HttpRequests : requests served by the API, by method, route and status
+	HttpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Requests served by the API.",
+	}, []string{"method", "route", "status"})
+
+	// HttpRequestDuration : time taken to serve the requests of the API, by method and route
+	HttpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to serve the requests of the API.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// Crawls : crawlers halted, by news outlet and final status
+	Crawls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "crawls_total",
+		Help:      "Crawlers halted, by news outlet and final status.",
+	}, []string{"news_outlet", "status"})
+
+	// CrawlDuration : time taken by a crawler from its start to its halt, by news outlet
+	CrawlDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "crawl_duration_seconds",
+		Help:      "Time taken by a crawler from its start to its halt.",
+		Buckets:   []float64{1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"news_outlet"})
+
+	// ArticleFetchDuration : time taken to fetch an article page, by news outlet and result
+	ArticleFetchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "article_fetch_duration_seconds",
+		Help:      "Time taken to fetch an article page, waits for the rate limits included.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"news_outlet", "result"})
+
+	// ArticleFetchBytes : size of the article pages fetched, by news outlet
+	ArticleFetchBytes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "article_fetch_bytes",
+		Help:      "Size of the bodies of the article pages fetched.",
+		Buckets:   prometheus.ExponentialBuckets(1<<10, 4, 8), // 1KiB to 16MiB
+	}, []string{"news_outlet"})
+
+	// AnalyzerRequests : requests sent to the AI analyzer, by endpoint and result
+	AnalyzerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "analyzer_requests_total",
+		Help:      "Requests sent to the AI analyzer.",
+	}, []string{"endpoint", "result"})
+
+	// AnalyzerRequestDuration : time taken by the AI analyzer to answer, by endpoint
+	AnalyzerRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "analyzer_request_duration_seconds",
+		Help:      "Time taken by the AI analyzer to answer, retries included.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"endpoint"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HttpRequests,
+		HttpRequestDuration,
+		Crawls,
+		CrawlDuration,
+		ArticleFetchDuration,
+		ArticleFetchBytes,
+		AnalyzerRequests,
+		AnalyzerRequestDuration,
+	)
+}
+
+// Handler :
+// Serves every metric of the Registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHttpRequest :
+// Records a request served by the API. route is the pattern the request matched, so paths carrying ids do not blow up
+// the number of series.
+func ObserveHttpRequest(method string, route string, status int, duration time.Duration) {
+	HttpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	HttpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveCrawl :
+// Records a crawler that halted with the provided status.
+func ObserveCrawl(newsOutlet string, status string, duration time.Duration) {
+	Crawls.WithLabelValues(newsOutlet, status).Inc()
+	CrawlDuration.WithLabelValues(newsOutlet).Observe(duration.Seconds())
+}
+
+// ObserveArticleFetch :
+// Records the fetch of an article page. The size is only recorded for the pages that were fetched.
+func ObserveArticleFetch(newsOutlet string, duration time.Duration, size int, err error) {
+	if err != nil {
+		ArticleFetchDuration.WithLabelValues(newsOutlet, ResultError).Observe(duration.Seconds())
+		return
+	}
+
+	ArticleFetchDuration.WithLabelValues(newsOutlet, ResultSuccess).Observe(duration.Seconds())
+	ArticleFetchBytes.WithLabelValues(newsOutlet).Observe(float64(size))
+}
+
+// ObserveAnalyzerRequest :
+// Records a request sent to the provided endpoint of the AI analyzer, which failed when err is not nil.
+func ObserveAnalyzerRequest(endpoint string, duration time.Duration, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultError
+	}
+
+	AnalyzerRequests.WithLabelValues(endpoint, result).Inc()
+	AnalyzerRequestDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+}
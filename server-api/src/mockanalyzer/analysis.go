@@ -0,0 +1,78 @@
+package mockanalyzer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// supportThreshold : share of the keywords of the post an article has to contain to support it
+	supportThreshold = 0.5
+	// partialThreshold : share of the keywords of the post an article has to contain to partially match it
+	partialThreshold = 0.25
+	// minKeywordLength : shortest word counted as a keyword, shorter ones being mostly articles and prepositions
+	minKeywordLength = 3
+)
+
+// stopWords : common English words never counted as keywords
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true, "all": true,
+	"any": true, "can": true, "had": true, "her": true, "was": true, "one": true, "our": true, "out": true,
+	"has": true, "have": true, "his": true, "how": true, "its": true, "who": true, "did": true, "get": true,
+	"this": true, "that": true, "with": true, "from": true, "they": true, "will": true, "would": true,
+	"there": true, "their": true, "what": true, "about": true, "which": true, "when": true, "were": true,
+	"been": true, "into": true, "than": true, "then": true, "them": true, "these": true, "those": true,
+	"some": true, "such": true, "just": true, "also": true, "more": true, "most": true, "over": true,
+	"after": true, "before": true, "said": true, "says": true, "while": true, "where": true, "your": true,
+}
+
+// Analyze :
+// Scores an article against a post by the share of the keywords of the post found in the article, and words the
+// result so the server classifies it: a high share supports the post, a lower one partially matches it and anything
+// below is insufficient. The mock never contradicts a post, as telling a denial apart from a confirmation requires
+// understanding the text.
+func Analyze(postContent string, newsContent string) string {
+	postKeywords := keywords(postContent)
+	newsKeywords := keywords(newsContent)
+
+	if len(postKeywords) == 0 {
+		return "The post contains insufficient information to be compared with the article."
+	}
+
+	shared := 0
+	for keyword := range postKeywords {
+		if newsKeywords[keyword] {
+			shared++
+		}
+	}
+	score := float64(shared) / float64(len(postKeywords))
+
+	overlap := fmt.Sprintf("%d of the %d keywords of the post (%.0f%%)", shared, len(postKeywords), score*100)
+
+	switch {
+	case score >= supportThreshold:
+		return "The article supports the post: it mentions " + overlap + "."
+	case score >= partialThreshold:
+		return "The article partially matches the post: it only mentions " + overlap + "."
+	default:
+		return "The article contains insufficient information about the post: it mentions " + overlap + "."
+	}
+}
+
+// keywords :
+// Returns the lowercase words of a text, leaving out the stop words and the words shorter than minKeywordLength.
+func keywords(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	keywords := make(map[string]bool)
+	for _, word := range words {
+		if len([]rune(word)) >= minKeywordLength && !stopWords[word] {
+			keywords[word] = true
+		}
+	}
+
+	return keywords
+}
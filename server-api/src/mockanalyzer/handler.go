@@ -0,0 +1,83 @@
+package mockanalyzer
+
+import (
+	"aletheia-server/src/errors"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// maxRequestBytes : largest request body accepted, like the Python analyzer
+const maxRequestBytes = 10 << 20
+
+// NewHandler :
+// Returns the handler serving "POST /getLinks" and "POST /analyze" with the request and response bodies of the Python
+// analyzer, errors included.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /getLinks", getLinks)
+	mux.HandleFunc("POST /analyze", analyze)
+
+	return mux
+}
+
+func getLinks(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		HtmlContent string `json:"html_content"`
+	}
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&request); err != nil {
+		writeDetail(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if strings.TrimSpace(request.HtmlContent) == "" {
+		writeDetail(w, http.StatusBadRequest, "HTML content cannot be empty")
+		return
+	}
+
+	links, err := ExtractLinks(request.HtmlContent)
+	if err != nil {
+		writeDetail(w, http.StatusBadRequest, "An error occurred while parsing the request")
+		return
+	}
+
+	server_errors.LogContext(r.Context(), "extracted links", server_errors.DebugLevel,
+		"htmlBytes", len(request.HtmlContent), "links", len(links))
+	writeJson(w, http.StatusOK, links)
+}
+
+func analyze(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		PostContent string `json:"post_content"`
+		NewsContent string `json:"news_content"`
+		UserContext string `json:"user_context"`
+	}
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&request); err != nil {
+		writeDetail(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if strings.TrimSpace(request.PostContent) == "" || strings.TrimSpace(request.NewsContent) == "" {
+		writeDetail(w, http.StatusBadRequest, "Both the post and the news content are required")
+		return
+	}
+
+	analysis := Analyze(request.PostContent, request.NewsContent)
+
+	server_errors.LogContext(r.Context(), "analyzed article", server_errors.DebugLevel, "analysis", analysis)
+	writeJson(w, http.StatusOK, map[string]any{"success": true, "analysis": analysis})
+}
+
+// writeDetail :
+// Answers with an error shaped like the ones of FastAPI.
+func writeDetail(w http.ResponseWriter, status int, detail string) {
+	writeJson(w, status, map[string]string{"detail": detail})
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
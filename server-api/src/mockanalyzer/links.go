@@ -0,0 +1,101 @@
+// Package mockanalyzer implements the "/getLinks" and "/analyze" endpoints of the AI analyzer without any model, so
+// the stack can run on machines unable to run Ollama. Links are found with heuristics on the anchors of the page and
+// analyses are scored by the keywords the post and the article share.
+package mockanalyzer
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	// minTitleWords : fewest words in the text of an anchor for it to be taken as the title of an article
+	minTitleWords = 3
+	// maxTitleWords : most words in the text of an anchor for it to be taken as the title of an article
+	maxTitleWords = 30
+)
+
+// skippedSections : parts of a page holding navigation rather than search results
+const skippedSections = "script, style, noscript, nav, header, footer, aside, form"
+
+// Link :
+// Article link found in a search results page, as answered by "/getLinks".
+type Link struct {
+	Title string `json:"title"`
+	Url   string `json:"url"`
+}
+
+// ExtractLinks :
+// Returns the links of a search results page that look like articles: anchors outside of the navigation, titled by
+// a few words and pointing to a path at least two segments deep or whose last segment is a hyphenated slug. Relative
+// links are resolved against the <base> of the page when it has one, and each link is only returned once.
+func ExtractLinks(html string) ([]Link, error) {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, err
+	}
+
+	var base *url.URL
+	if href, ok := document.Find("base[href]").First().Attr("href"); ok {
+		base, _ = url.Parse(href)
+	}
+
+	document.Find(skippedSections).Remove()
+
+	links := make([]Link, 0)
+	seen := make(map[string]bool)
+
+	document.Find("a[href]").Each(func(_ int, anchor *goquery.Selection) {
+		href, _ := anchor.Attr("href")
+
+		link, err := url.Parse(strings.TrimSpace(href))
+		if err != nil || !looksLikeArticle(link) {
+			return
+		}
+
+		if base != nil {
+			link = base.ResolveReference(link)
+		}
+
+		title := strings.Join(strings.Fields(anchor.Text()), " ")
+		if title == "" {
+			title, _ = anchor.Attr("title")
+		}
+
+		if words := len(strings.Fields(title)); words < minTitleWords || words > maxTitleWords {
+			return
+		}
+
+		link.Fragment = ""
+		if seen[link.String()] {
+			return
+		}
+		seen[link.String()] = true
+
+		links = append(links, Link{Title: title, Url: link.String()})
+	})
+
+	return links, nil
+}
+
+// looksLikeArticle :
+// Tells whether a link may point to an article, skipping anchors within the page, other schemes and the home, search
+// and section pages.
+func looksLikeArticle(link *url.URL) bool {
+	if link.Scheme != "" && link.Scheme != "http" && link.Scheme != "https" {
+		return false
+	}
+
+	segments := strings.FieldsFunc(link.Path, func(r rune) bool { return r == '/' })
+
+	switch {
+	case len(segments) == 0:
+		return false
+	case len(segments) >= 2:
+		return true
+	default:
+		return strings.Count(segments[0], "-") >= 2
+	}
+}
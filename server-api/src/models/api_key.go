@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Roles of the API keys, each one allowing everything the previous ones allow.
+const (
+	// RoleRead : reads the languages, the news outlets, the crawl jobs and the crawl runs
+	RoleRead = "read"
+	// RoleCrawl : also launches and cancels crawls and fact checks
+	RoleCrawl = "crawl"
+	// RoleAdmin : also adds, updates and deletes the languages and the news outlets, and manages the API keys
+	RoleAdmin = "admin"
+)
+
+var roleRanks = map[string]int{
+	RoleRead:  1,
+	RoleCrawl: 2,
+	RoleAdmin: 3,
+}
+
+// ValidRole :
+// Reports whether role is one of the roles of the API keys.
+func ValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// RoleAllows :
+// Reports whether a key with the provided role may do what the required role allows.
+func RoleAllows(role string, required string) bool {
+	rank, ok := roleRanks[role]
+	return ok && rank >= roleRanks[required]
+}
+
+// ApiKey :
+// An API key as stored, without the key itself. Prefix holds its first characters so it can be recognized.
+type ApiKey struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	Prefix     string     `json:"prefix"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// ApiKeyInitializer :
+// Body of the requests issuing a new API key.
+type ApiKeyInitializer struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// IssuedApiKey :
+// A newly issued API key along with the key itself, which is only ever sent back once.
+type IssuedApiKey struct {
+	ApiKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Article :
+// Main content of a news article, extracted from its page. ContentHash is the hex encoded SHA-256 of Text, so the
+// same article fetched by different runs can be recognized. RawHtml is only filled when explicitly requested.
+type Article struct {
+	Url          string     `json:"url"`
+	CanonicalUrl string     `json:"canonicalUrl"`
+	Title        string     `json:"title"`
+	Byline       string     `json:"byline,omitempty"`
+	PublishedAt  *time.Time `json:"publishedAt,omitempty"`
+	FetchedAt    time.Time  `json:"fetchedAt"`
+	ContentHash  string     `json:"contentHash"`
+	Text         string     `json:"text"`
+	RawHtml      string     `json:"rawHtml,omitempty"`
+}
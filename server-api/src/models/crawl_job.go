@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// CrawlerProgress :
+// Current state of one of the crawlers of a CrawlJob.
+type CrawlerProgress struct {
+	CrawlerId  int    `json:"crawlerId"`
+	NewsOutlet string `json:"newsOutlet"`
+	Status     string `json:"status"`
+}
+
+// CrawlJob :
+// A crawl running in the background. Result is only filled once every crawler of the job halted.
+type CrawlJob struct {
+	Id           string            `json:"id"`
+	Status       string            `json:"status"`
+	Query        string            `json:"query"`
+	PagesToVisit int               `json:"pagesToVisit"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	FinishedAt   *time.Time        `json:"finishedAt,omitempty"`
+	Crawlers     []CrawlerProgress `json:"crawlers"`
+	Result       *CrawlResult      `json:"result,omitempty"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Ways a crawler can extract the article links from the search results page of a news outlet.
+const (
+	LinkExtractorSelector = "selector"
+	LinkExtractorAI       = "ai"
+)
+
+// CrawlerResult :
+// Outcome of a single crawler, i.e. of a single news outlet, after it halted. Failures lists the pages that could not
+// be collected, the crawler keeping going without them.
+type CrawlerResult struct {
+	CrawlerId     int            `json:"crawlerId"`
+	NewsOutlet    string         `json:"newsOutlet"`
+	Query         string         `json:"query"`
+	Status        string         `json:"status"`
+	Error         string         `json:"error,omitempty"`
+	LinkExtractor string         `json:"linkExtractor,omitempty"`
+	VisitedUrls   []string       `json:"visitedUrls"`
+	Articles      []Article      `json:"articles"`
+	Failures      []FetchFailure `json:"failures,omitempty"`
+	StartedAt     time.Time      `json:"startedAt"`
+	FinishedAt    time.Time      `json:"finishedAt"`
+	DurationMs    int64          `json:"durationMs"`
+}
+
+// FetchFailure :
+// A page of a news outlet that could not be collected, along with the reason.
+type FetchFailure struct {
+	Url   string `json:"url"`
+	Error string `json:"error"`
+}
+
+// CrawlResult :
+// Aggregated outcome of a crawl request, containing one CrawlerResult per news outlet that was crawled. RunId is only
+// present once the result was stored in the database.
+type CrawlResult struct {
+	RunId        int             `json:"runId,omitempty"`
+	Query        string          `json:"query"`
+	PagesToVisit int             `json:"pagesToVisit"`
+	StartedAt    time.Time       `json:"startedAt"`
+	FinishedAt   time.Time       `json:"finishedAt"`
+	DurationMs   int64           `json:"durationMs"`
+	Crawlers     []CrawlerResult `json:"crawlers"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// CrawlRunSummary :
+// A stored crawl run without its crawlers, as listed when browsing the history.
+type CrawlRunSummary struct {
+	Id           int       `json:"id"`
+	Query        string    `json:"query"`
+	PagesToVisit int       `json:"pagesToVisit"`
+	StartedAt    time.Time `json:"startedAt"`
+	FinishedAt   time.Time `json:"finishedAt"`
+	DurationMs   int64     `json:"durationMs"`
+	Crawlers     int       `json:"crawlers"`
+	Articles     int       `json:"articles"`
+	Verdict      string    `json:"verdict,omitempty"`
+}
+
+// CrawlRun :
+// A stored crawl run with the outcome of each of its crawlers. Verdict is only present when the run was part of a
+// fact check.
+type CrawlRun struct {
+	CrawlResult
+	Verdict *Verdict `json:"verdict,omitempty"`
+}
+
+// Verdict :
+// A stored fact check verdict, i.e. a FactCheckResult without the crawl it is based on.
+type Verdict struct {
+	Post       Post              `json:"post"`
+	Prompt     string            `json:"prompt"`
+	Verdict    string            `json:"verdict"`
+	Score      float64           `json:"score"`
+	Analyses   []ArticleAnalysis `json:"analyses"`
+	StartedAt  time.Time         `json:"startedAt"`
+	FinishedAt time.Time         `json:"finishedAt"`
+	DurationMs int64             `json:"durationMs"`
+}
@@ -3,4 +3,5 @@ package models
 type CrawlerInitializer struct {
 	PagesToVisit int    `json:"pagesToVisit"`
 	Query        string `json:"query"`
+	KeepRawHtml  bool   `json:"keepRawHtml,omitempty"`
 }
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Stances an article can take towards the submitted post, according to the AI analyzer.
+const (
+	StanceSupports     = "supports"
+	StanceContradicts  = "contradicts"
+	StancePartial      = "partial"
+	StanceInsufficient = "insufficient"
+)
+
+// Verdicts a fact check can reach after weighting every stance by the credibility of its news outlet.
+const (
+	VerdictLikelyTrue  = "likely true"
+	VerdictLikelyFalse = "likely false"
+	VerdictMixed       = "mixed"
+	VerdictUnverified  = "unverified"
+)
+
+// Post :
+// Content submitted by the user to be fact-checked.
+type Post struct {
+	Url     string `json:"url"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// ArticleAnalysis :
+// What the AI analyzer concluded when comparing a single article against the submitted post.
+type ArticleAnalysis struct {
+	NewsOutlet  string `json:"newsOutlet"`
+	Credibility int    `json:"credibility"`
+	Url         string `json:"url"`
+	Title       string `json:"title"`
+	Analysis    string `json:"analysis"`
+	Stance      string `json:"stance"`
+	Error       string `json:"error,omitempty"`
+}
+
+// FactCheckResult :
+// Aggregated verdict of a fact check. Score ranges from -1 (contradicted by every article) to 1 (supported by every
+// article) and is weighted by the credibility of each news outlet.
+type FactCheckResult struct {
+	Post       Post              `json:"post"`
+	Query      string            `json:"query"`
+	Prompt     string            `json:"prompt"`
+	Verdict    string            `json:"verdict"`
+	Score      float64           `json:"score"`
+	Analyses   []ArticleAnalysis `json:"analyses"`
+	Crawl      CrawlResult       `json:"crawl"`
+	StartedAt  time.Time         `json:"startedAt"`
+	FinishedAt time.Time         `json:"finishedAt"`
+	DurationMs int64             `json:"durationMs"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Health :
+// State of the server. Status is "up" only when every dependency is up; a liveness check carries no dependency.
+type Health struct {
+	Status       string             `json:"status"`
+	CheckedAt    time.Time          `json:"checkedAt"`
+	Dependencies []DependencyHealth `json:"dependencies,omitempty"`
+}
+
+// DependencyHealth :
+// Outcome of the check of a single dependency, along with how long it took. Error is only present when it is down.
+type DependencyHealth struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
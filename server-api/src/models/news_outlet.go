@@ -7,4 +7,48 @@ type NewsOutlet struct {
 	Language     string `json:"language"`
 	Name         string `json:"name"`
 	QueryUrl     string `json:"queryUrl"`
+	// CrawlDelayMs : when positive, overrides the delay between two requests to the news outlet
+	CrawlDelayMs int `json:"crawlDelayMs,omitempty"`
+}
+
+// NewsOutletPatch :
+// Partial update of a news outlet. Only the fields that are present are changed.
+type NewsOutletPatch struct {
+	Credibility  *int    `json:"credibility"`
+	HtmlSelector *string `json:"htmlSelector"`
+	Language     *string `json:"language"`
+	Name         *string `json:"name"`
+	QueryUrl     *string `json:"queryUrl"`
+	CrawlDelayMs *int    `json:"crawlDelayMs"`
+}
+
+// Apply :
+// Returns a copy of the news outlet with the fields present in the patch replaced.
+func (p NewsOutletPatch) Apply(newsOutlet NewsOutlet) NewsOutlet {
+	if p.Credibility != nil {
+		newsOutlet.Credibility = *p.Credibility
+	}
+	if p.HtmlSelector != nil {
+		newsOutlet.HtmlSelector = *p.HtmlSelector
+	}
+	if p.Language != nil {
+		newsOutlet.Language = *p.Language
+	}
+	if p.Name != nil {
+		newsOutlet.Name = *p.Name
+	}
+	if p.QueryUrl != nil {
+		newsOutlet.QueryUrl = *p.QueryUrl
+	}
+	if p.CrawlDelayMs != nil {
+		newsOutlet.CrawlDelayMs = *p.CrawlDelayMs
+	}
+	return newsOutlet
+}
+
+// IsEmpty :
+// Reports whether the patch does not change any field.
+func (p NewsOutletPatch) IsEmpty() bool {
+	return p.Credibility == nil && p.HtmlSelector == nil && p.Language == nil && p.Name == nil && p.QueryUrl == nil &&
+		p.CrawlDelayMs == nil
 }
@@ -7,12 +7,23 @@ import (
 	"strings"
 )
 
+// QueryPlaceholder :
+// Placeholder inside the query url of a news outlet that is replaced by the encoded query.
+const QueryPlaceholder = "QUERY_HERE"
+
 type QueryParser struct {
 	NewsOutletName string
 	QueryParam     string
 	QueryUrl       string
 }
 
+// Valid :
+// Reports whether Parse would generate a query url, without logging anything.
+func (qp *QueryParser) Valid() bool {
+	return strings.TrimSpace(qp.NewsOutletName) != "" && strings.TrimSpace(qp.QueryParam) != "" &&
+		strings.TrimSpace(qp.QueryUrl) != ""
+}
+
 func (qp *QueryParser) Parse() string {
 	qp.NewsOutletName = strings.TrimSpace(qp.NewsOutletName)
 	if qp.NewsOutletName == "" {
@@ -37,7 +48,7 @@ func (qp *QueryParser) Parse() string {
 		return ""
 	}
 
-	finalQuery := strings.ReplaceAll(qp.QueryUrl, "QUERY_HERE", encodedQuery)
+	finalQuery := strings.ReplaceAll(qp.QueryUrl, QueryPlaceholder, encodedQuery)
 	server_errors.Log(fmt.Sprintf("QueryParam to %s generated: %s", qp.NewsOutletName, finalQuery), server_errors.InfoLevel)
 	return finalQuery
 }
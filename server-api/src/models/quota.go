@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Quota :
+// Daily crawl quota of a client. A Limit of 0 means the client is not limited.
+type Quota struct {
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	ResetsAt  time.Time `json:"resetsAt"`
+}
@@ -1,6 +1,11 @@
 package models
 
+// Response :
+// Body of the error responses and of the responses confirming an action. Code is a stable machine-readable identifier
+// of the outcome, "OK" on success, while Details adds request specific information to the Message.
 type Response struct {
 	Status  int    `json:"status"`
+	Code    string `json:"code"`
 	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
 }
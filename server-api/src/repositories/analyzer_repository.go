@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/metrics"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultAiAnalyzerUrl : used when no URL is configured for the AI analyzer
+	defaultAiAnalyzerUrl = "http://localhost:7654"
+	// defaultAiAnalyzerTimeout : used when no timeout is configured for the AI analyzer, which answers far slower than
+	// a news outlet
+	defaultAiAnalyzerTimeout = 2 * time.Minute
+)
+
+// Paths of the endpoints of the AI analyzer, relative to its base URL.
+const (
+	analyzerGetLinksPath = "/getLinks"
+	analyzerAnalyzePath  = "/analyze"
+)
+
+// AnalyzerConfig :
+// Settings of the AI analyzer. Url is its base URL and Timeout bounds each request attempt sent to it.
+type AnalyzerConfig struct {
+	Url     string
+	Timeout time.Duration
+}
+
+// AnalyzerRepository :
+// Client for the "/getLinks" and "/analyze" endpoints of the AI analyzer.
+type AnalyzerRepository struct {
+	baseUrl string
+	client  *HttpClient
+}
+
+// NewAnalyzerRepository :
+// Creates a new AnalyzerRepository. Missing settings cascade to their defaults and a nil client to a shared one.
+func NewAnalyzerRepository(config AnalyzerConfig, client *HttpClient) AnalyzerRepository {
+	if config.Url == "" {
+		config.Url = defaultAiAnalyzerUrl
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = defaultAiAnalyzerTimeout
+	}
+
+	if client == nil {
+		client = defaultHttpClient
+	}
+
+	return AnalyzerRepository{
+		baseUrl: strings.TrimSuffix(config.Url, "/"),
+		client:  client.WithTimeout(config.Timeout),
+	}
+}
+
+// defaultAnalyzerRepository :
+// AnalyzerRepository used by the crawlers that were not given one.
+var defaultAnalyzerRepository = NewAnalyzerRepository(AnalyzerConfig{}, nil)
+
+// GetLinks :
+// Asks the AI analyzer to find the article links inside a search results page.
+//
+// Error: will throw AnalyzerRequestError if the request cannot be sent or the analyzer does not answer with 200.
+//
+// Error: will throw AnalyzerResponseError if the analyzer answers with an unexpected body.
+func (ar *AnalyzerRepository) GetLinks(ctx context.Context, htmlContent string) (urls []string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveAnalyzerRequest(metrics.AnalyzerGetLinks, time.Since(start), err)
+	}()
+
+	htmlContent = strings.ReplaceAll(htmlContent, "\"", "'") // Escape quotes
+	htmlContent = strings.ReplaceAll(htmlContent, "\n", "")  // Remove newlines
+
+	requestBody, err := json.Marshal(map[string]string{
+		"html_content": htmlContent,
+	})
+
+	if err != nil {
+		return nil, server_errors.ErrAnalyzerRequestError.Wrap(err)
+	}
+
+	resp, err := ar.client.PostJSON(ctx, ar.baseUrl+analyzerGetLinksPath, requestBody)
+
+	if err != nil {
+		return nil, server_errors.ErrAnalyzerRequestError.Wrap(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, server_errors.ErrAnalyzerRequestError.With(fmt.Sprintf("status %d", resp.StatusCode))
+	}
+
+	var links []struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	}
+
+	if err := json.Unmarshal(resp.Body, &links); err != nil {
+		server_errors.LogContext(ctx, "AI analyzer returned invalid links", server_errors.WarningLevel, "body", string(resp.Body))
+		return nil, server_errors.ErrAnalyzerResponseError.Wrap(err)
+	}
+
+	for _, link := range links {
+		urls = append(urls, link.URL)
+	}
+
+	return urls, nil
+}
+
+// Analyze :
+// Asks the AI analyzer to compare the content of a post against the content of a news article, returning its
+// analysis as free text. userContext is optional.
+//
+// Error: will throw AnalyzerRequestError if the request cannot be sent or the analyzer does not answer with 200.
+//
+// Error: will throw AnalyzerResponseError if the analyzer answers with an unexpected body.
+func (ar *AnalyzerRepository) Analyze(ctx context.Context, postContent string, newsContent string, userContext string) (analysis string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveAnalyzerRequest(metrics.AnalyzerAnalyze, time.Since(start), err)
+	}()
+
+	requestBody, err := json.Marshal(map[string]string{
+		"post_content": postContent,
+		"news_content": newsContent,
+		"user_context": userContext,
+	})
+
+	if err != nil {
+		return "", server_errors.ErrAnalyzerRequestError.Wrap(err)
+	}
+
+	resp, err := ar.client.PostJSON(ctx, ar.baseUrl+analyzerAnalyzePath, requestBody)
+
+	if err != nil {
+		return "", server_errors.ErrAnalyzerRequestError.Wrap(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", server_errors.ErrAnalyzerRequestError.With(fmt.Sprintf("status %d", resp.StatusCode))
+	}
+
+	var body struct {
+		Success  bool   `json:"success"`
+		Analysis string `json:"analysis"`
+	}
+
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		return "", server_errors.ErrAnalyzerResponseError.Wrap(err)
+	}
+
+	if !body.Success {
+		return "", server_errors.ErrAnalyzerResponseError.With("analysis was not successful")
+	}
+
+	return body.Analysis, nil
+}
+
+// Ping :
+// Checks that the AI analyzer answers on its base URL through a single attempt, bounded by the deadline of ctx. Any
+// response below 500 counts, as the analyzer serves no route on its base URL.
+//
+// Error: will throw AnalyzerUnreachable if no response could be collected or the analyzer answers with a 5xx status.
+func (ar *AnalyzerRepository) Ping(ctx context.Context) error {
+	resp, err := ar.client.WithMaxRetries(0).Get(ctx, ar.baseUrl, nil)
+
+	if err != nil {
+		return server_errors.ErrAnalyzerUnreachable.Wrap(err)
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return server_errors.ErrAnalyzerUnreachable.With(fmt.Sprintf("status %d", resp.StatusCode))
+	}
+
+	return nil
+}
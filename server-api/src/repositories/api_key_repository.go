@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// apiKeyColumns : columns read into a models.ApiKey by scanApiKey
+const apiKeyColumns = "Id, Name, Role, Prefix, CreatedAt, LastUsedAt, RevokedAt"
+
+// ApiKeyRepository :
+// Stores the API keys by the hash of the key, so a leaked database does not leak the keys themselves.
+type ApiKeyRepository interface {
+	AddApiKey(ctx context.Context, name string, role string, prefix string, hash string) (*models.ApiKey, error)
+	GetApiKeys(ctx context.Context) ([]models.ApiKey, error)
+	GetApiKeyByHash(ctx context.Context, hash string) (*models.ApiKey, error)
+	RevokeApiKey(ctx context.Context, id int) error
+}
+
+// SqlApiKeyRepository :
+// ApiKeyRepository storing the API keys in the "api_keys" table. The times are set by the server, as SQLite has no
+// time zone aware clock.
+type SqlApiKeyRepository struct {
+	connection *sql.DB
+	dialect    dialect
+}
+
+func NewPostgresApiKeyRepository(connection *sql.DB) *SqlApiKeyRepository {
+	return &SqlApiKeyRepository{
+		connection: connection,
+		dialect:    postgresDialect{},
+	}
+}
+
+func NewSqliteApiKeyRepository(connection *sql.DB) *SqlApiKeyRepository {
+	return &SqlApiKeyRepository{
+		connection: connection,
+		dialect:    sqliteDialect{},
+	}
+}
+
+// Create --------------------------------------------------------------------------------------------------------------
+
+// AddApiKey :
+// Stores a new API key with the provided hash and returns it as stored.
+//
+// Error: will throw ApiKeyNotSaved if the row cannot be inserted.
+func (ar *SqlApiKeyRepository) AddApiKey(ctx context.Context, name string, role string, prefix string, hash string) (*models.ApiKey, error) {
+	apiKey, err := scanApiKey(ar.connection.QueryRowContext(ctx,
+		`INSERT INTO api_keys (Name, Role, Prefix, KeyHash, CreatedAt) VALUES ($1, $2, $3, $4, $5) RETURNING `+apiKeyColumns,
+		name, role, prefix, hash, ar.dialect.time(time.Now()),
+	))
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return nil, server_errors.ErrApiKeyNotSaved
+	}
+
+	return apiKey, nil
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetApiKeys :
+// Returns every API key, revoked ones included, oldest first.
+//
+// Error: will throw ApiKeysQueryError if the API keys cannot be queried.
+//
+// Error: will throw ApiKeyParsingError if a row cannot be parsed.
+func (ar *SqlApiKeyRepository) GetApiKeys(ctx context.Context) ([]models.ApiKey, error) {
+	rows, err := ar.connection.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY Id")
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return nil, server_errors.ErrApiKeysQueryError
+	}
+	defer rows.Close()
+
+	apiKeys := make([]models.ApiKey, 0)
+
+	for rows.Next() {
+		apiKey, err := scanApiKey(rows)
+
+		if err != nil {
+			server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+			return nil, server_errors.ErrApiKeyParsingError
+		}
+
+		apiKeys = append(apiKeys, *apiKey)
+	}
+
+	if err = rows.Err(); err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return nil, server_errors.ErrApiKeysQueryError
+	}
+
+	return apiKeys, nil
+}
+
+// GetApiKeyByHash :
+// Returns the API key with the provided hash, as long as it was not revoked, and records that it was used. The time
+// of use is only written once a minute, so busy keys do not turn every request into a write.
+//
+// Error: will throw ApiKeyNotFound if no active API key has the provided hash.
+//
+// Error: will throw ApiKeyParsingError if the row cannot be parsed.
+func (ar *SqlApiKeyRepository) GetApiKeyByHash(ctx context.Context, hash string) (*models.ApiKey, error) {
+	apiKey, err := scanApiKey(ar.connection.QueryRowContext(ctx,
+		"SELECT "+apiKeyColumns+" FROM api_keys WHERE KeyHash = $1 AND RevokedAt IS NULL", hash,
+	))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, server_errors.ErrApiKeyNotFound
+	}
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return nil, server_errors.ErrApiKeyParsingError
+	}
+
+	now := ar.dialect.time(time.Now())
+	_, err = ar.connection.ExecContext(ctx,
+		"UPDATE api_keys SET LastUsedAt = $1 WHERE Id = $2 AND (LastUsedAt IS NULL OR LastUsedAt < $3)",
+		now, apiKey.Id, now.Add(-time.Minute),
+	)
+
+	// Failing to record the use does not prevent the key from being used
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.WarningLevel)
+	}
+
+	return apiKey, nil
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// RevokeApiKey :
+// Revokes the API key with the provided id, which is kept so the keys issued can still be audited. Revoking a revoked
+// key changes nothing.
+//
+// Error: will throw ApiKeyNotFound if an API key with the provided id is not found.
+//
+// Error: will throw ApiKeyNotRevoked if the database fails to update the row.
+func (ar *SqlApiKeyRepository) RevokeApiKey(ctx context.Context, id int) error {
+	result, err := ar.connection.ExecContext(ctx,
+		"UPDATE api_keys SET RevokedAt = COALESCE(RevokedAt, $1) WHERE Id = $2", ar.dialect.time(time.Now()), id,
+	)
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrApiKeyNotRevoked
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrApiKeyNotRevoked
+	}
+
+	if affected == 0 {
+		return server_errors.ErrApiKeyNotFound
+	}
+
+	return nil
+}
+
+// scanApiKey :
+// Reads a row holding the apiKeyColumns.
+func scanApiKey(row rowScanner) (*models.ApiKey, error) {
+	var apiKey models.ApiKey
+	var lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&apiKey.Id, &apiKey.Name, &apiKey.Role, &apiKey.Prefix, &apiKey.CreatedAt, &lastUsedAt, &revokedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if lastUsedAt.Valid {
+		apiKey.LastUsedAt = &lastUsedAt.Time
+	}
+
+	if revokedAt.Valid {
+		apiKey.RevokedAt = &revokedAt.Time
+	}
+
+	return &apiKey, nil
+}
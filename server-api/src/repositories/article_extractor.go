@@ -0,0 +1,314 @@
+package repositories
+
+import (
+	"aletheia-server/src/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+const (
+	// minParagraphLength : paragraphs shorter than this are considered boilerplate and do not score their containers
+	minParagraphLength = 25
+	// minArticleLength : an <article> element with less text than this is not trusted as the main content
+	minArticleLength = 250
+)
+
+// unlikelyCandidates :
+// Class names and ids of elements that are almost never part of the main content of an article.
+var unlikelyCandidates = regexp.MustCompile(
+	`(?i)(^|[\s_-])(ad|ads|advert|banner|breadcrumbs?|comments?|cookie|footer|header|menu|modal|nav|newsletter|outbrain|popup|promo|related|share|sharing|sidebar|social|sponsor|subscribe|taboola)([\s_-]|$)`,
+)
+
+// boilerplateElements :
+// Elements removed from the page before looking for its main content.
+const boilerplateElements = "script, style, noscript, template, iframe, svg, form, nav, header, footer, aside, button"
+
+// contentElements :
+// Elements whose text makes up the body of an article once its main container is found.
+const contentElements = "p, h2, h3, h4, li, blockquote, pre"
+
+// publishedDateLayouts :
+// Layouts tried, in order, when parsing the publishing date of an article.
+var publishedDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.RFC1123,
+	time.RFC1123Z,
+	"2006-01-02",
+}
+
+// ExtractArticle :
+// Extracts the title, byline, publishing date, canonical url and main text of the news article at pageUrl. The main
+// text is found readability-style: boilerplate is stripped and the element containing the densest paragraphs wins.
+// When keepRawHtml is set, the original HTML is kept inside the article as well.
+func ExtractArticle(page string, pageUrl string, keepRawHtml bool) (models.Article, error) {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+
+	if err != nil {
+		return models.Article{}, err
+	}
+
+	article := models.Article{
+		Url:          pageUrl,
+		CanonicalUrl: extractCanonicalUrl(document, pageUrl),
+		Title:        extractTitle(document),
+		Byline:       extractByline(document),
+		PublishedAt:  extractPublishedAt(document),
+	}
+
+	if keepRawHtml {
+		article.RawHtml = page
+	}
+
+	article.Text = extractText(document)
+	article.ContentHash = contentHash(article.Text)
+
+	return article, nil
+}
+
+// contentHash :
+// Returns the hex encoded SHA-256 of the text of an article.
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// extractCanonicalUrl :
+// Returns the canonical url declared by the page, resolved against pageUrl, or pageUrl itself.
+func extractCanonicalUrl(document *goquery.Document, pageUrl string) string {
+	candidates := []string{
+		attribute(document, `link[rel="canonical"]`, "href"),
+		attribute(document, `meta[property="og:url"]`, "content"),
+	}
+
+	base, err := url.Parse(pageUrl)
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+
+		if err != nil {
+			return candidate
+		}
+
+		if resolved, ok := resolveLink(base, candidate); ok {
+			return resolved
+		}
+	}
+
+	return pageUrl
+}
+
+// extractTitle :
+// Returns the title of the article, preferring the one meant for sharing over the main heading and the page title.
+func extractTitle(document *goquery.Document) string {
+	candidates := []string{
+		attribute(document, `meta[property="og:title"]`, "content"),
+		attribute(document, `meta[name="twitter:title"]`, "content"),
+		text(document.Find("article h1, h1").First()),
+		text(document.Find("title").First()),
+	}
+
+	return firstNonEmpty(candidates)
+}
+
+// extractByline :
+// Returns the author of the article, if it declares one.
+func extractByline(document *goquery.Document) string {
+	candidates := []string{
+		attribute(document, `meta[name="author"]`, "content"),
+		attribute(document, `meta[property="article:author"]`, "content"),
+		text(document.Find(`[itemprop="author"]`).First()),
+		text(document.Find(`[rel="author"]`).First()),
+		text(document.Find(".byline, .author").First()),
+	}
+
+	return firstNonEmpty(candidates)
+}
+
+// extractPublishedAt :
+// Returns the publishing date of the article, if it declares one in a known layout.
+func extractPublishedAt(document *goquery.Document) *time.Time {
+	candidates := []string{
+		attribute(document, `meta[property="article:published_time"]`, "content"),
+		attribute(document, `meta[itemprop="datePublished"]`, "content"),
+		attribute(document, `meta[name="date"]`, "content"),
+		attribute(document, `meta[name="pubdate"]`, "content"),
+		attribute(document, `meta[name="publishdate"]`, "content"),
+		attribute(document, `[itemprop="datePublished"]`, "datetime"),
+		attribute(document, `time[datetime]`, "datetime"),
+	}
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+
+		for _, layout := range publishedDateLayouts {
+			if publishedAt, err := time.Parse(layout, candidate); err == nil {
+				return &publishedAt
+			}
+		}
+	}
+
+	return nil
+}
+
+// extractText :
+// Strips the boilerplate from the document and returns the text of its main content, one paragraph per line.
+func extractText(document *goquery.Document) string {
+	document.Find(boilerplateElements).Remove()
+	document.Find("[class], [id]").Each(func(_ int, selection *goquery.Selection) {
+		class, _ := selection.Attr("class")
+		id, _ := selection.Attr("id")
+
+		if selection.Is("body, article, main") {
+			return
+		}
+
+		if unlikelyCandidates.MatchString(class) || unlikelyCandidates.MatchString(id) {
+			selection.Remove()
+		}
+	})
+
+	container := mainContainer(document)
+	if container == nil {
+		return text(document.Find("body"))
+	}
+
+	paragraphs := make([]string, 0)
+	container.Find(contentElements).Each(func(_ int, selection *goquery.Selection) {
+		// Nested content elements are already part of their parent's text
+		if selection.ParentsFiltered(contentElements).Length() > 0 {
+			return
+		}
+
+		if paragraph := text(selection); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	})
+
+	if len(paragraphs) == 0 {
+		return text(container)
+	}
+
+	return strings.Join(paragraphs, "\n")
+}
+
+// mainContainer :
+// Finds the element holding the main content of the page. A single <article> with enough text is trusted right away;
+// otherwise every paragraph scores its parent, and half as much its grandparent, by its length and its commas.
+func mainContainer(document *goquery.Document) *goquery.Selection {
+	articles := document.Find("article")
+	if articles.Length() == 1 && len(text(articles)) >= minArticleLength {
+		return articles
+	}
+
+	// Candidates are kept in document order so ties are always broken the same way
+	candidates := make([]*goquery.Selection, 0)
+	scores := make(map[*html.Node]float64)
+
+	score := func(selection *goquery.Selection, value float64) {
+		if selection.Length() == 0 {
+			return
+		}
+
+		node := selection.Get(0)
+		if _, ok := scores[node]; !ok {
+			candidates = append(candidates, selection)
+		}
+		scores[node] += value
+	}
+
+	document.Find("p, pre, td").Each(func(_ int, paragraph *goquery.Selection) {
+		content := text(paragraph)
+		if len(content) < minParagraphLength {
+			return
+		}
+
+		value := 1 + float64(strings.Count(content, ",")) + float64(len(content))/100
+		parent := paragraph.Parent()
+		score(parent, value)
+		score(parent.Parent(), value/2)
+	})
+
+	var best *goquery.Selection
+	var bestScore float64
+
+	for _, candidate := range candidates {
+		if value := scores[candidate.Get(0)]; value > bestScore {
+			best = candidate
+			bestScore = value
+		}
+	}
+
+	return best
+}
+
+// attribute :
+// Returns the trimmed value of an attribute of the first element matching the selector.
+func attribute(document *goquery.Document, selector string, name string) string {
+	value, _ := document.Find(selector).First().Attr(name)
+	return strings.TrimSpace(value)
+}
+
+// blockElements :
+// Elements whose text is separated from the text around them.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "blockquote": true, "br": true, "dd": true, "div": true, "dl": true, "dt": true,
+	"figcaption": true, "figure": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"hr": true, "li": true, "main": true, "ol": true, "p": true, "pre": true, "section": true, "table": true,
+	"td": true, "th": true, "tr": true, "ul": true,
+}
+
+// text :
+// Returns the text of the selection with whitespace collapsed. Unlike goquery's Text, the text of block elements is
+// kept apart from the text around them.
+func text(selection *goquery.Selection) string {
+	var builder strings.Builder
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			builder.WriteString(node.Data)
+		case html.ElementNode:
+			if blockElements[node.Data] {
+				builder.WriteString(" ")
+				defer builder.WriteString(" ")
+			}
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	for _, node := range selection.Nodes {
+		walk(node)
+	}
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// firstNonEmpty :
+// Returns the first value that is not empty.
+func firstNonEmpty(values []string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
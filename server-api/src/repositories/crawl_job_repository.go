@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"sync"
+	"time"
+)
+
+// finishedCrawlJobTTL :
+// How long a finished crawl job is kept in memory so its result can still be polled.
+const finishedCrawlJobTTL = 24 * time.Hour
+
+// CrawlJobRepository :
+// In-memory storage for the crawl jobs running in the background. It is safe for concurrent use.
+type CrawlJobRepository struct {
+	mutex   sync.RWMutex
+	jobs    map[string]*models.CrawlJob
+	cancels map[string]context.CancelFunc
+	// draining : set once the server stops, after which no crawl job is added
+	draining bool
+}
+
+func NewCrawlJobRepository() *CrawlJobRepository {
+	return &CrawlJobRepository{
+		jobs:    make(map[string]*models.CrawlJob),
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+// Create --------------------------------------------------------------------------------------------------------------
+
+// AddCrawlJob :
+// Stores a new crawl job alongside the function used to cancel it, then calls start, if any, while still holding the
+// lock, so a job cannot start once StopAccepting was called. Finished jobs older than finishedCrawlJobTTL are
+// discarded in the process.
+//
+// Error: will throw CrawlJobsDraining if StopAccepting was called.
+func (cj *CrawlJobRepository) AddCrawlJob(job models.CrawlJob, cancel context.CancelFunc, start func()) error {
+	cj.mutex.Lock()
+	defer cj.mutex.Unlock()
+
+	if cj.draining {
+		return server_errors.ErrCrawlJobsDraining
+	}
+
+	cj.pruneFinished()
+	cj.jobs[job.Id] = &job
+	cj.cancels[job.Id] = cancel
+
+	if start != nil {
+		start()
+	}
+
+	return nil
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetCrawlJob :
+// Returns a snapshot of the crawl job with the provided id.
+//
+// Error: will throw CrawlJobNotFound if there is no crawl job with the provided id.
+func (cj *CrawlJobRepository) GetCrawlJob(id string) (models.CrawlJob, error) {
+	cj.mutex.RLock()
+	defer cj.mutex.RUnlock()
+
+	job, ok := cj.jobs[id]
+
+	if !ok {
+		return models.CrawlJob{}, server_errors.ErrCrawlJobNotFound
+	}
+
+	return snapshot(job), nil
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// SetCrawlJobStatus :
+// Updates the status of the crawl job with the provided id. Unknown ids are ignored.
+func (cj *CrawlJobRepository) SetCrawlJobStatus(id string, status string) {
+	cj.mutex.Lock()
+	defer cj.mutex.Unlock()
+
+	if job, ok := cj.jobs[id]; ok {
+		job.Status = status
+	}
+}
+
+// SetCrawlerStatus :
+// Updates the status of a single crawler of the crawl job with the provided id. Unknown ids are ignored.
+func (cj *CrawlJobRepository) SetCrawlerStatus(id string, crawlerId int, status string) {
+	cj.mutex.Lock()
+	defer cj.mutex.Unlock()
+
+	job, ok := cj.jobs[id]
+
+	if !ok {
+		return
+	}
+
+	for i := range job.Crawlers {
+		if job.Crawlers[i].CrawlerId == crawlerId {
+			job.Crawlers[i].Status = status
+			return
+		}
+	}
+}
+
+// FinishCrawlJob :
+// Stores the result of the crawl job with the provided id and marks it with its final status.
+func (cj *CrawlJobRepository) FinishCrawlJob(id string, status string, result models.CrawlResult) {
+	cj.mutex.Lock()
+	defer cj.mutex.Unlock()
+
+	job, ok := cj.jobs[id]
+
+	if !ok {
+		return
+	}
+
+	finishedAt := time.Now()
+	job.Status = status
+	job.FinishedAt = &finishedAt
+	job.Result = &result
+
+	if cancel, ok := cj.cancels[id]; ok {
+		cancel()
+		delete(cj.cancels, id)
+	}
+}
+
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// CancelCrawlJob :
+// Cancels the context of the crawl job with the provided id, stopping its crawlers.
+//
+// Error: will throw CrawlJobNotFound if there is no crawl job with the provided id.
+//
+// Error: will throw CrawlJobAlreadyFinished if the crawl job already halted.
+func (cj *CrawlJobRepository) CancelCrawlJob(id string) (models.CrawlJob, error) {
+	cj.mutex.Lock()
+	defer cj.mutex.Unlock()
+
+	job, ok := cj.jobs[id]
+
+	if !ok {
+		return models.CrawlJob{}, server_errors.ErrCrawlJobNotFound
+	}
+
+	cancel, ok := cj.cancels[id]
+
+	if !ok || job.FinishedAt != nil {
+		return snapshot(job), server_errors.ErrCrawlJobAlreadyFinished
+	}
+
+	cancel()
+	delete(cj.cancels, id)
+
+	return snapshot(job), nil
+}
+
+// StopAccepting :
+// Rejects every crawl job added from now on. The jobs already added are left running.
+func (cj *CrawlJobRepository) StopAccepting() {
+	cj.mutex.Lock()
+	defer cj.mutex.Unlock()
+
+	cj.draining = true
+}
+
+// CancelCrawlJobs :
+// Cancels the context of every crawl job still running, returning how many of them were cancelled.
+func (cj *CrawlJobRepository) CancelCrawlJobs() int {
+	cj.mutex.Lock()
+	defer cj.mutex.Unlock()
+
+	cancelled := 0
+	for id, cancel := range cj.cancels {
+		cancel()
+		delete(cj.cancels, id)
+		cancelled++
+	}
+
+	return cancelled
+}
+
+// pruneFinished :
+// Discards the finished crawl jobs older than finishedCrawlJobTTL. The caller must hold the write lock.
+func (cj *CrawlJobRepository) pruneFinished() {
+	for id, job := range cj.jobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > finishedCrawlJobTTL {
+			delete(cj.jobs, id)
+		}
+	}
+}
+
+// snapshot :
+// Copies the crawl job so callers cannot race with the goroutines still updating it.
+func snapshot(job *models.CrawlJob) models.CrawlJob {
+	copied := *job
+	copied.Crawlers = append([]models.CrawlerProgress(nil), job.Crawlers...)
+	return copied
+}
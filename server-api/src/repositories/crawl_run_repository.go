@@ -0,0 +1,389 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+)
+
+// CrawlRunRepository :
+// Stores the crawl runs, the outcome of each of their crawlers, the articles they fetched and the verdicts of the fact
+// checks based on them.
+type CrawlRunRepository interface {
+	SaveCrawlRun(ctx context.Context, result models.CrawlResult) (int, error)
+	SaveVerdict(ctx context.Context, runId int, result models.FactCheckResult) error
+	GetCrawlRuns(ctx context.Context, limit int, offset int) ([]models.CrawlRunSummary, error)
+	GetCrawlRunById(ctx context.Context, id int) (*models.CrawlRun, error)
+}
+
+// SqlCrawlRunRepository :
+// CrawlRunRepository storing the crawl runs in the "crawl_runs", "crawler_runs", "articles" and "verdicts" tables. The
+// failures and the analyses are stored as JSON.
+type SqlCrawlRunRepository struct {
+	connection *sql.DB
+	dialect    dialect
+}
+
+func NewPostgresCrawlRunRepository(connection *sql.DB) *SqlCrawlRunRepository {
+	return &SqlCrawlRunRepository{
+		connection: connection,
+		dialect:    postgresDialect{},
+	}
+}
+
+func NewSqliteCrawlRunRepository(connection *sql.DB) *SqlCrawlRunRepository {
+	return &SqlCrawlRunRepository{
+		connection: connection,
+		dialect:    sqliteDialect{},
+	}
+}
+
+// Create --------------------------------------------------------------------------------------------------------------
+
+// SaveCrawlRun :
+// Stores the crawl result along with its crawlers and their articles inside a single transaction and returns the id
+// of the new crawl run.
+//
+// Error: will throw CrawlRunNotSaved if any of the rows cannot be inserted.
+func (cr *SqlCrawlRunRepository) SaveCrawlRun(ctx context.Context, result models.CrawlResult) (int, error) {
+	tx, err := cr.connection.BeginTx(ctx, nil)
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return -1, server_errors.ErrCrawlRunNotSaved
+	}
+	defer tx.Rollback()
+
+	var runId int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO crawl_runs (Query, PagesToVisit, StartedAt, FinishedAt, DurationMs)
+		VALUES ($1, $2, $3, $4, $5) RETURNING Id`,
+		result.Query, result.PagesToVisit, cr.dialect.time(result.StartedAt), cr.dialect.time(result.FinishedAt),
+		result.DurationMs,
+	).Scan(&runId)
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return -1, server_errors.ErrCrawlRunNotSaved
+	}
+
+	for _, crawler := range result.Crawlers {
+		if err = cr.saveCrawlerRun(ctx, tx, runId, crawler); err != nil {
+			server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+			return -1, server_errors.ErrCrawlRunNotSaved
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return -1, server_errors.ErrCrawlRunNotSaved
+	}
+
+	return runId, nil
+}
+
+// saveCrawlerRun :
+// Stores the outcome of a single crawler and its articles as part of the crawl run with the provided id.
+func (cr *SqlCrawlRunRepository) saveCrawlerRun(ctx context.Context, tx *sql.Tx, runId int, crawler models.CrawlerResult) error {
+	failures := crawler.Failures
+	if failures == nil {
+		failures = make([]models.FetchFailure, 0)
+	}
+
+	// A nil slice would be stored as NULL, which the column does not accept
+	visitedUrls := crawler.VisitedUrls
+	if visitedUrls == nil {
+		visitedUrls = make([]string, 0)
+	}
+
+	failuresJson, err := json.Marshal(failures)
+
+	if err != nil {
+		return err
+	}
+
+	var crawlerRunId int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO crawler_runs
+		(CrawlRunId, CrawlerId, NewsOutlet, Query, Status, Error, LinkExtractor, VisitedUrls, Failures, StartedAt, FinishedAt, DurationMs)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING Id`,
+		runId, crawler.CrawlerId, crawler.NewsOutlet, crawler.Query, crawler.Status, crawler.Error,
+		crawler.LinkExtractor, cr.dialect.array(visitedUrls), string(failuresJson), cr.dialect.time(crawler.StartedAt),
+		cr.dialect.time(crawler.FinishedAt), crawler.DurationMs,
+	).Scan(&crawlerRunId)
+
+	if err != nil {
+		return err
+	}
+
+	if len(crawler.Articles) == 0 {
+		return nil
+	}
+
+	query, err := tx.PrepareContext(ctx,
+		`INSERT INTO articles
+		(CrawlerRunId, NewsOutlet, Url, CanonicalUrl, Title, Byline, PublishedAt, FetchedAt, ContentHash, Text)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+	)
+
+	if err != nil {
+		return err
+	}
+	defer query.Close()
+
+	for _, article := range crawler.Articles {
+		hash := article.ContentHash
+		if hash == "" {
+			hash = contentHash(article.Text)
+		}
+
+		_, err = query.ExecContext(ctx,
+			crawlerRunId, crawler.NewsOutlet, article.Url, article.CanonicalUrl, article.Title, article.Byline,
+			nullTime(cr.dialect, article.PublishedAt), cr.dialect.time(article.FetchedAt), hash, article.Text,
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveVerdict :
+// Stores the verdict of a fact check as part of the crawl run it is based on.
+//
+// Error: will throw VerdictNotSaved if the row cannot be inserted.
+func (cr *SqlCrawlRunRepository) SaveVerdict(ctx context.Context, runId int, result models.FactCheckResult) error {
+	analyses, err := json.Marshal(result.Analyses)
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrVerdictNotSaved
+	}
+
+	_, err = cr.connection.ExecContext(ctx,
+		`INSERT INTO verdicts
+		(CrawlRunId, PostUrl, PostTitle, PostText, Prompt, Verdict, Score, Analyses, StartedAt, FinishedAt, DurationMs)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		runId, result.Post.Url, result.Post.Title, result.Post.Content, result.Prompt, result.Verdict, result.Score,
+		string(analyses), cr.dialect.time(result.StartedAt), cr.dialect.time(result.FinishedAt), result.DurationMs,
+	)
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrVerdictNotSaved
+	}
+
+	return nil
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetCrawlRuns :
+// Returns a page of the stored crawl runs, newest first, along with how many crawlers and articles each of them had.
+//
+// Error: will throw CrawlRunsQueryError if the crawl runs cannot be queried.
+//
+// Error: will throw CrawlRunParsingError if a row cannot be parsed.
+func (cr *SqlCrawlRunRepository) GetCrawlRuns(ctx context.Context, limit int, offset int) ([]models.CrawlRunSummary, error) {
+	rows, err := cr.connection.QueryContext(ctx,
+		`SELECT r.Id, r.Query, r.PagesToVisit, r.StartedAt, r.FinishedAt, r.DurationMs,
+			(SELECT COUNT(*) FROM crawler_runs c WHERE c.CrawlRunId = r.Id),
+			(SELECT COUNT(*) FROM articles a JOIN crawler_runs c ON a.CrawlerRunId = c.Id WHERE c.CrawlRunId = r.Id),
+			COALESCE((SELECT v.Verdict FROM verdicts v WHERE v.CrawlRunId = r.Id ORDER BY v.Id DESC LIMIT 1), '')
+		FROM crawl_runs r
+		ORDER BY r.StartedAt DESC, r.Id DESC
+		LIMIT $1 OFFSET $2`,
+		limit, offset,
+	)
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return nil, server_errors.ErrCrawlRunsQueryError
+	}
+	defer rows.Close()
+
+	runs := make([]models.CrawlRunSummary, 0)
+
+	for rows.Next() {
+		var run models.CrawlRunSummary
+		err = rows.Scan(
+			&run.Id, &run.Query, &run.PagesToVisit, &run.StartedAt, &run.FinishedAt, &run.DurationMs,
+			&run.Crawlers, &run.Articles, &run.Verdict,
+		)
+
+		if err != nil {
+			server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+			return nil, server_errors.ErrCrawlRunParsingError
+		}
+
+		runs = append(runs, run)
+	}
+
+	if err = rows.Err(); err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return nil, server_errors.ErrCrawlRunsQueryError
+	}
+
+	return runs, nil
+}
+
+// GetCrawlRunById :
+// Returns the crawl run with the provided id along with its crawlers, their articles and its verdict, if any.
+//
+// Error: will throw CrawlRunNotFound if a crawl run with the provided id is not found.
+//
+// Error: will throw CrawlRunParsingError if a row cannot be parsed.
+func (cr *SqlCrawlRunRepository) GetCrawlRunById(ctx context.Context, id int) (*models.CrawlRun, error) {
+	run := models.CrawlRun{}
+	run.Crawlers = make([]models.CrawlerResult, 0)
+
+	err := cr.connection.QueryRowContext(ctx,
+		"SELECT Id, Query, PagesToVisit, StartedAt, FinishedAt, DurationMs FROM crawl_runs WHERE Id = $1", id,
+	).Scan(&run.RunId, &run.Query, &run.PagesToVisit, &run.StartedAt, &run.FinishedAt, &run.DurationMs)
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, server_errors.ErrCrawlRunNotFound
+		}
+		return nil, server_errors.ErrCrawlRunParsingError
+	}
+
+	crawlerRunIds, err := cr.getCrawlerRuns(ctx, &run)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cr.getArticles(ctx, &run, crawlerRunIds); err != nil {
+		return nil, err
+	}
+
+	if run.Verdict, err = cr.getVerdict(ctx, run.RunId); err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// getCrawlerRuns :
+// Appends the crawlers of the run and returns the position of each of them by crawler run id.
+func (cr *SqlCrawlRunRepository) getCrawlerRuns(ctx context.Context, run *models.CrawlRun) (map[int]int, error) {
+	rows, err := cr.connection.QueryContext(ctx,
+		`SELECT Id, CrawlerId, NewsOutlet, Query, Status, Error, LinkExtractor, VisitedUrls, Failures, StartedAt, FinishedAt,
+		DurationMs FROM crawler_runs WHERE CrawlRunId = $1 ORDER BY CrawlerId`,
+		run.RunId,
+	)
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return nil, server_errors.ErrCrawlRunsQueryError
+	}
+	defer rows.Close()
+
+	positions := make(map[int]int)
+
+	for rows.Next() {
+		var crawlerRunId int
+		var failures []byte
+		crawler := models.CrawlerResult{Articles: make([]models.Article, 0)}
+		err = rows.Scan(
+			&crawlerRunId, &crawler.CrawlerId, &crawler.NewsOutlet, &crawler.Query, &crawler.Status, &crawler.Error,
+			&crawler.LinkExtractor, cr.dialect.scanArray(&crawler.VisitedUrls), &failures, &crawler.StartedAt, &crawler.FinishedAt,
+			&crawler.DurationMs,
+		)
+
+		if err == nil {
+			err = json.Unmarshal(failures, &crawler.Failures)
+		}
+
+		if err != nil {
+			server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+			return nil, server_errors.ErrCrawlRunParsingError
+		}
+
+		positions[crawlerRunId] = len(run.Crawlers)
+		run.Crawlers = append(run.Crawlers, crawler)
+	}
+
+	return positions, rows.Err()
+}
+
+// getArticles :
+// Appends every article of the run to the crawler that fetched it.
+func (cr *SqlCrawlRunRepository) getArticles(ctx context.Context, run *models.CrawlRun, crawlerRunIds map[int]int) error {
+	rows, err := cr.connection.QueryContext(ctx,
+		`SELECT a.CrawlerRunId, a.Url, a.CanonicalUrl, a.Title, a.Byline, a.PublishedAt, a.FetchedAt, a.ContentHash, a.Text
+		FROM articles a JOIN crawler_runs c ON a.CrawlerRunId = c.Id
+		WHERE c.CrawlRunId = $1 ORDER BY a.Id`,
+		run.RunId,
+	)
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrCrawlRunsQueryError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var crawlerRunId int
+		var article models.Article
+		var publishedAt sql.NullTime
+
+		err = rows.Scan(
+			&crawlerRunId, &article.Url, &article.CanonicalUrl, &article.Title, &article.Byline, &publishedAt,
+			&article.FetchedAt, &article.ContentHash, &article.Text,
+		)
+
+		if err != nil {
+			server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+			return server_errors.ErrCrawlRunParsingError
+		}
+
+		if publishedAt.Valid {
+			article.PublishedAt = &publishedAt.Time
+		}
+
+		position := crawlerRunIds[crawlerRunId]
+		run.Crawlers[position].Articles = append(run.Crawlers[position].Articles, article)
+	}
+
+	return rows.Err()
+}
+
+// getVerdict :
+// Returns the latest verdict based on the run, or nil if the run was not part of a fact check.
+func (cr *SqlCrawlRunRepository) getVerdict(ctx context.Context, runId int) (*models.Verdict, error) {
+	var verdict models.Verdict
+	var analyses []byte
+
+	err := cr.connection.QueryRowContext(ctx,
+		`SELECT PostUrl, PostTitle, PostText, Prompt, Verdict, Score, Analyses, StartedAt, FinishedAt, DurationMs
+		FROM verdicts WHERE CrawlRunId = $1 ORDER BY Id DESC LIMIT 1`,
+		runId,
+	).Scan(
+		&verdict.Post.Url, &verdict.Post.Title, &verdict.Post.Content, &verdict.Prompt, &verdict.Verdict, &verdict.Score, &analyses,
+		&verdict.StartedAt, &verdict.FinishedAt, &verdict.DurationMs,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return nil, server_errors.ErrCrawlRunParsingError
+	}
+
+	if err = json.Unmarshal(analyses, &verdict.Analyses); err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return nil, server_errors.ErrCrawlRunParsingError
+	}
+
+	return &verdict, nil
+}
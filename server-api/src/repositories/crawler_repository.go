@@ -2,186 +2,321 @@ package repositories
 
 import (
 	"aletheia-server/src/errors"
+	"aletheia-server/src/metrics"
 	"aletheia-server/src/models"
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"os"
+	"net/url"
+	"runtime/debug"
 	"strings"
+	"sync"
+	"time"
 )
 
+// CrawlerStatusListener :
+// Called every time the status of a crawler changes.
+type CrawlerStatusListener func(crawlerId int, status string)
+
 type CrawlerRepository struct {
-	Crawler models.Crawler
+	Crawler        models.Crawler
+	NewsOutlet     string
+	Result         models.CrawlerResult
+	StatusListener CrawlerStatusListener
+	// KeepRawHtml : whether the raw HTML of each article is kept alongside its extracted content
+	KeepRawHtml bool
+	// Fetcher : polite HTTP client used to request the pages of the news outlet, defaults to a shared one
+	Fetcher *Fetcher
+	// Analyzer : AI analyzer finding the article links when the HTML selector yields none, defaults to a shared one
+	Analyzer *AnalyzerRepository
+	// CrawlDelay : when positive, overrides the delay between two requests to the news outlet
+	CrawlDelay time.Duration
 }
 
-func NewCrawlerRepository(crawler models.Crawler) CrawlerRepository {
+func NewCrawlerRepository(crawler models.Crawler, newsOutlet string) CrawlerRepository {
 	return CrawlerRepository{
-		Crawler: crawler,
+		Crawler:    crawler,
+		NewsOutlet: newsOutlet,
+		Result: models.CrawlerResult{
+			CrawlerId:   crawler.Id,
+			NewsOutlet:  newsOutlet,
+			Query:       crawler.Query,
+			Status:      crawler.Status,
+			VisitedUrls: make([]string, 0),
+			Articles:    make([]models.Article, 0),
+		},
 	}
 }
 
-func (cr *CrawlerRepository) Crawl() {
-	cr.Crawler.Status = server_errors.CrawlerRunning
+// Crawl :
+// Visits the query page of the news outlet, extracts the article links inside of it and collects the body of up to
+// Crawler.PagesToVisit of them. The outcome is stored inside Result, even when the crawler fails. Once
+// ctx is cancelled, every pending request is aborted and the crawler halts with the CrawlerCancelled status.
+func (cr *CrawlerRepository) Crawl(ctx context.Context) {
+	cr.Result.StartedAt = time.Now()
+	cr.setStatus(server_errors.CrawlerRunning)
+	defer cr.finish()
+	// A bug in a crawler fails it, the other crawlers of its job carry on
+	defer func() {
+		if r := recover(); r != nil {
+			server_errors.LogContext(ctx, "crawler panicked", server_errors.ErrorLevel,
+				"panic", r, "stack", string(debug.Stack()))
+			cr.fail(fmt.Errorf("crawler panicked: %v", r))
+		}
+	}()
 
-	if cr.badCrawler() {
+	if cr.badCrawler(ctx) {
 		return
 	}
 
 	// Get the initial page content
-	resp, err := http.Get(cr.Crawler.Query)
+	resp, err := cr.get(ctx, cr.Crawler.Query)
 	if err != nil {
-		server_errors.Log(
-			fmt.Sprintf("crawler %d failed to fetch initial page: %v", cr.Crawler.Id, err),
-			server_errors.ErrorLevel)
-		cr.Crawler.Status = err.Error()
+		server_errors.LogContext(ctx, "crawler failed to fetch its initial page", server_errors.ErrorLevel,
+			"url", cr.Crawler.Query, "error", err)
+		cr.fail(err)
 		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		server_errors.Log(
-			fmt.Sprintf("crawler %d failed to read initial page: %v", cr.Crawler.Id, err),
-			server_errors.ErrorLevel)
-		cr.Crawler.Status = err.Error()
-		return
-	}
+	// Resolve relative links against the page that was actually served, which may differ from the query after redirects
+	pageUrl := resp.Url
+	body := resp.Body
 
-	// Send HTML content to AI analyzer to get links
-	links, err := getLinksFromAI(string(body))
+	links, err := cr.collectLinks(ctx, string(body), pageUrl)
 	if err != nil {
-		server_errors.Log(
-			fmt.Sprintf("crawler %d failed to get links from AI: %v", cr.Crawler.Id, err),
-			server_errors.ErrorLevel)
-		cr.Crawler.Status = err.Error()
+		server_errors.LogContext(ctx, "crawler failed to get links from AI", server_errors.ErrorLevel, "error", err)
+		cr.fail(err)
 		return
 	}
 
 	// Limit the number of pages to visit
 	if len(links) > cr.Crawler.PagesToVisit {
-		links = links[:cr.Crawler.PagesToVisit]
+		links = links[:max(cr.Crawler.PagesToVisit, 0)]
 	}
 
 	// Fetch and save the body content of each link
-	for _, link := range links {
-		cr.collectCandidateBody(link)
+	cr.collectCandidateBodies(ctx, links)
+
+	if ctx.Err() != nil {
+		cr.fail(ctx.Err())
+		return
 	}
-	cr.Crawler.Status = server_errors.CrawlerSucceeded
+	cr.setStatus(server_errors.CrawlerSucceeded)
 }
 
-func (cr *CrawlerRepository) badCrawler() bool {
-	if cr.Crawler.Query == "" {
-		server_errors.Log(
-			fmt.Sprintf("crawler %d failed because it was initialized without a query", cr.Crawler.Id),
-			server_errors.ErrorLevel,
-		)
-		cr.Crawler.Status = server_errors.CrawlerEmptyQueryUrl
-		return true
+// get :
+// Requests a page of the news outlet through the fetcher of the crawler.
+//
+// Error: will throw HttpUnexpectedStatus if the page is not served with a 2xx status.
+func (cr *CrawlerRepository) get(ctx context.Context, url string) (*HttpResponse, error) {
+	resp, err := cr.fetcher().Get(ctx, url, cr.CrawlDelay)
+
+	if err != nil {
+		return nil, err
 	}
 
-	// crawler.PagesBodies should be empty
-	if len(cr.Crawler.PagesBodies) > 0 {
-		server_errors.Log(
-			fmt.Sprintf("crawler %d failed because its page bodies was initialized with values already maintained", cr.Crawler.Id),
-			server_errors.ErrorLevel,
-		)
-		cr.Crawler.Status = server_errors.CrawlerFilledPagesBodies
-		return true
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, server_errors.ErrHttpUnexpectedStatus.With(fmt.Sprintf("%d after %d attempts", resp.StatusCode, resp.Attempts))
 	}
 
-	return false
+	return resp, nil
+}
+
+// fetcher :
+// Returns the fetcher of the crawler, or the shared one if it was not given one.
+func (cr *CrawlerRepository) fetcher() *Fetcher {
+	if cr.Fetcher == nil {
+		return defaultFetcher
+	}
+
+	return cr.Fetcher
 }
 
-func (cr *CrawlerRepository) collectCandidateBody(link string) {
-	if !strings.HasPrefix(link, "http") {
-		link = "https://" + link // Ensure the link has a valid scheme
+// analyzer :
+// Returns the AI analyzer of the crawler, or the shared one if it was not given one.
+func (cr *CrawlerRepository) analyzer() *AnalyzerRepository {
+	if cr.Analyzer == nil {
+		return &defaultAnalyzerRepository
+	}
+
+	return cr.Analyzer
+}
+
+// collectLinks :
+// Extracts the article links from the search results page using the HTML selector of the news outlet, only falling
+// back to the AI analyzer when the selector is missing, invalid or yields nothing.
+func (cr *CrawlerRepository) collectLinks(ctx context.Context, html string, pageUrl string) ([]string, error) {
+	links, err := ExtractLinks(html, cr.Crawler.HtmlSelector, pageUrl)
+
+	if err == nil && len(links) > 0 {
+		cr.Result.LinkExtractor = models.LinkExtractorSelector
+		return links, nil
 	}
 
-	resp, err := http.Get(link)
 	if err != nil {
-		server_errors.Log(fmt.Sprintf("%s %s ->", server_errors.HttpFetchError, link), server_errors.ErrorLevel)
-		return
+		server_errors.LogContext(ctx, "crawler could not apply its html selector, falling back to the AI analyzer",
+			server_errors.WarningLevel, "selector", cr.Crawler.HtmlSelector, "error", err)
+	} else {
+		server_errors.LogContext(ctx, "crawler html selector yielded no links, falling back to the AI analyzer",
+			server_errors.WarningLevel, "selector", cr.Crawler.HtmlSelector)
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			server_errors.Log(
-				server_errors.CrawlerClosingPageError,
-				server_errors.WarningLevel,
-			)
-		}
-	}(resp.Body)
 
-	body, err := io.ReadAll(resp.Body)
+	// Send HTML content to AI analyzer to get links
+	links, err = cr.analyzer().GetLinks(ctx, html)
 	if err != nil {
-		server_errors.Log(
-			fmt.Sprintf("unable to read body from %s: %v", link, err),
-			server_errors.ErrorLevel,
-		)
-		return
+		return nil, err
 	}
 
-	// Store the body
-	candidateBody := string(body)
-	cr.Crawler.PagesBodies = append(cr.Crawler.PagesBodies, candidateBody)
+	cr.Result.LinkExtractor = models.LinkExtractorAI
 
-	server_errors.Log(
-		fmt.Sprintf("added %s to crawler %d pagebodies", link, cr.Crawler.Id),
-		server_errors.InfoLevel,
-	)
+	// The AI analyzer may answer with relative links as well
+	base, err := url.Parse(pageUrl)
+	if err != nil {
+		return links, nil
+	}
+
+	resolved := make([]string, 0, len(links))
+	for _, link := range links {
+		if absolute, ok := resolveLink(base, link); ok {
+			resolved = append(resolved, absolute)
+		} else {
+			resolved = append(resolved, link)
+		}
+	}
+
+	return resolved, nil
 }
 
-func getLinksFromAI(htmlContent string) ([]string, error) {
-	htmlContent = strings.ReplaceAll(htmlContent, "\"", "'") // Escape quotes
-	htmlContent = strings.ReplaceAll(htmlContent, "\n", "")  // Remove newlines
+// setStatus :
+// Updates the status of the crawler, notifying the StatusListener if there is one.
+func (cr *CrawlerRepository) setStatus(status string) {
+	cr.Crawler.Status = status
 
-	// Prepare request to AI analyzer
-	requestBody, err := json.Marshal(map[string]string{
-		"html_content": htmlContent,
-	})
+	if cr.StatusListener != nil {
+		cr.StatusListener(cr.Crawler.Id, status)
+	}
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %v", err)
+// fail :
+// Marks the crawler as failed, or as cancelled if err was caused by the cancellation of its context, keeping the
+// reason inside Result.
+func (cr *CrawlerRepository) fail(err error) {
+	if errors.Is(err, context.Canceled) {
+		cr.setStatus(server_errors.CrawlerCancelled)
+	} else {
+		cr.setStatus(server_errors.CrawlerFailed)
 	}
+	cr.Result.Error = err.Error()
+}
+
+// finish :
+// Copies the final state of the crawler into Result and records it in the metrics.
+func (cr *CrawlerRepository) finish() {
+	cr.Result.FinishedAt = time.Now()
+	duration := cr.Result.FinishedAt.Sub(cr.Result.StartedAt)
+	cr.Result.DurationMs = duration.Milliseconds()
+	cr.Result.Status = cr.Crawler.Status
 
-	aiServiceURL := os.Getenv("AI_ANALYZER_URL")
-	if aiServiceURL == "" {
-		aiServiceURL = "http://localhost:7654" // Default fallback
+	metrics.ObserveCrawl(cr.NewsOutlet, cr.Result.Status, duration)
+}
+
+func (cr *CrawlerRepository) badCrawler(ctx context.Context) bool {
+	if cr.Crawler.Query == "" {
+		server_errors.LogContext(ctx, "crawler failed because it was initialized without a query", server_errors.ErrorLevel)
+		cr.setStatus(server_errors.CrawlerEmptyQueryUrl)
+		cr.Result.Error = server_errors.CrawlerEmptyQueryUrl
+		return true
 	}
 
-	resp, err := http.Post(
-		fmt.Sprintf("%s/getLinks", aiServiceURL),
-		"application/json",
-		bytes.NewBuffer(requestBody),
-	)
+	// crawler.PagesBodies should be empty
+	if len(cr.Crawler.PagesBodies) > 0 {
+		server_errors.LogContext(ctx, "crawler failed because its page bodies was initialized with values already maintained",
+			server_errors.ErrorLevel)
+		cr.setStatus(server_errors.CrawlerFilledPagesBodies)
+		cr.Result.Error = server_errors.CrawlerFilledPagesBodies
+		return true
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to request links from AI: %v", err)
+	return false
+}
+
+// candidate :
+// Outcome of the fetch of a single article page, holding either the article or the failure.
+type candidate struct {
+	article *models.Article
+	failure *models.FetchFailure
+}
+
+// collectCandidateBodies :
+// Fetches the article pages concurrently, up to the outlet concurrency of the fetcher at once, and stores the outcome
+// of each of them in the order of the links once all of them were fetched. No link is dispatched once ctx is
+// cancelled.
+func (cr *CrawlerRepository) collectCandidateBodies(ctx context.Context, links []string) {
+	outlet := NewWorkerPool(cr.fetcher().OutletConcurrency())
+	candidates := make([]candidate, len(links))
+
+	var wg sync.WaitGroup
+	for i, link := range links {
+		if !strings.HasPrefix(link, "http") {
+			link = "https://" + link // Ensure the link has a valid scheme
+		}
+
+		if err := outlet.Acquire(ctx); err != nil {
+			break
+		}
+
+		cr.Result.VisitedUrls = append(cr.Result.VisitedUrls, link)
+
+		wg.Add(1)
+		go func(i int, link string) {
+			defer wg.Done()
+			defer outlet.Release()
+			// Each goroutine writes its own slot only, the results are merged once all of them are done
+			candidates[i] = cr.collectCandidateBody(ctx, link)
+		}(i, link)
 	}
-	defer resp.Body.Close()
+	wg.Wait()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("AI analyzer returned status %d", resp.StatusCode)
+	for _, c := range candidates {
+		if c.failure != nil {
+			cr.Result.Failures = append(cr.Result.Failures, *c.failure)
+		}
+
+		if c.article != nil {
+			cr.Crawler.PagesBodies = append(cr.Crawler.PagesBodies, c.article.Text)
+			cr.Result.Articles = append(cr.Result.Articles, *c.article)
+		}
 	}
+}
+
+// collectCandidateBody :
+// Fetches a single article page and extracts its main content. It must not touch the state of the crawler, as it runs
+// concurrently with the other fetches of the same crawler.
+func (cr *CrawlerRepository) collectCandidateBody(ctx context.Context, link string) candidate {
+	start := time.Now()
+	resp, err := cr.get(ctx, link)
 
-	// Define the correct structure to match the response
-	var links []struct {
-		Title string `json:"title"`
-		URL   string `json:"url"`
+	size := 0
+	if resp != nil {
+		size = len(resp.Body)
 	}
+	metrics.ObserveArticleFetch(cr.NewsOutlet, time.Since(start), size, err)
 
-	if err := json.NewDecoder(resp.Body).Decode(&links); err != nil {
-		server_errors.Log(fmt.Sprintf("resp.Body = %s", resp.Body), server_errors.WarningLevel)
-		return nil, fmt.Errorf("failed to decode AI response: %v", err)
+	if err != nil {
+		server_errors.LogContext(ctx, server_errors.HttpFetchError, server_errors.ErrorLevel, "url", link, "error", err)
+		return candidate{failure: &models.FetchFailure{Url: link, Error: err.Error()}}
 	}
 
-	// Extract URLs from the links
-	var urls []string
-	for _, link := range links {
-		urls = append(urls, link.URL)
+	// Store the main content of the article instead of the whole page
+	article, err := ExtractArticle(string(resp.Body), link, cr.KeepRawHtml)
+	if err != nil {
+		server_errors.LogContext(ctx, server_errors.ArticleExtractionError, server_errors.ErrorLevel, "url", link, "error", err)
+		return candidate{failure: &models.FetchFailure{Url: link, Error: err.Error()}}
 	}
+	article.FetchedAt = time.Now()
+
+	server_errors.LogContext(ctx, "added article to crawler pagebodies", server_errors.DebugLevel, "url", link)
 
-	return urls, nil
+	return candidate{article: &article}
 }
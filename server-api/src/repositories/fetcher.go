@@ -0,0 +1,337 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+)
+
+const (
+	defaultUserAgent         = "AletheiaCrawler/1.0"
+	defaultRequestsPerSecond = 1.0
+	defaultBurst             = 1
+	defaultRobotsTTL         = time.Hour
+	defaultWorkers           = 8
+	defaultOutletConcurrency = 2
+	// maxRobotsSize : robots.txt files larger than this are truncated, as most crawlers do
+	maxRobotsSize = 512 * 1024
+)
+
+// FetcherConfig :
+// Settings of the polite HTTP fetcher shared by every crawler. RequestsPerSecond and Burst configure the token bucket
+// kept for each host. Workers bounds the requests in flight across every crawler, while OutletConcurrency bounds the
+// article pages each crawler fetches at once.
+type FetcherConfig struct {
+	UserAgent         string
+	RequestsPerSecond float64
+	Burst             int
+	RobotsTTL         time.Duration
+	Workers           int
+	OutletConcurrency int
+}
+
+// Fetcher :
+// HTTP client used to crawl the news outlets politely: every request carries the configured User-Agent, paths
+// disallowed by the robots.txt of the host are never requested and requests to the same host are throttled by a token
+// bucket. A crawl delay, either declared by the robots.txt or set on the news outlet, replaces the bucket rate. Once
+// the host is ready, the request waits for a slot of the worker pool, so the crawlers never burst past Workers
+// requests in flight.
+type Fetcher struct {
+	client  *HttpClient
+	config  FetcherConfig
+	workers *WorkerPool
+
+	mu     sync.Mutex
+	hosts  map[string]*hostLimiter
+	robots map[string]*robotsEntry
+}
+
+// hostLimiter :
+// Token bucket of a single host. Tokens can go negative, which reserves a slot for a request waiting its turn.
+type hostLimiter struct {
+	tokens float64
+	last   time.Time
+}
+
+// robotsEntry :
+// Cached robots.txt of a single host. The entry is stored as soon as its fetch starts, so the crawlers asking for it
+// meanwhile wait for done instead of fetching it again. data is nil when the fetch was abandoned.
+type robotsEntry struct {
+	data      *robotstxt.RobotsData
+	fetchedAt time.Time
+	done      chan struct{}
+}
+
+// NewFetcher :
+// Creates a new Fetcher. Missing settings cascade to their defaults and a nil client cascades to a shared HttpClient.
+func NewFetcher(client *HttpClient, config FetcherConfig) *Fetcher {
+	if client == nil {
+		client = defaultHttpClient
+	}
+
+	if config.UserAgent == "" {
+		config.UserAgent = defaultUserAgent
+	}
+
+	if config.RequestsPerSecond <= 0 {
+		config.RequestsPerSecond = defaultRequestsPerSecond
+	}
+
+	if config.Burst < 1 {
+		config.Burst = defaultBurst
+	}
+
+	if config.RobotsTTL <= 0 {
+		config.RobotsTTL = defaultRobotsTTL
+	}
+
+	if config.Workers < 1 {
+		config.Workers = defaultWorkers
+	}
+
+	if config.OutletConcurrency < 1 {
+		config.OutletConcurrency = defaultOutletConcurrency
+	}
+
+	return &Fetcher{
+		client:  client,
+		config:  config,
+		workers: NewWorkerPool(config.Workers),
+		hosts:   make(map[string]*hostLimiter),
+		robots:  make(map[string]*robotsEntry),
+	}
+}
+
+// defaultFetcher :
+// Fetcher used by the crawlers that were not given one.
+var defaultFetcher = NewFetcher(nil, FetcherConfig{})
+
+// Get :
+// Requests the page at rawUrl once its robots.txt allows it and the host is ready for another request. A positive
+// crawlDelay overrides the delay between two requests to the same host. Retries wait for the host like the first
+// attempt, on top of their backoff or Retry-After. The wait is aborted once ctx is cancelled.
+//
+// Error: will throw CrawlerRobotsDisallowed if the robots.txt of the host disallows the page.
+func (f *Fetcher) Get(ctx context.Context, rawUrl string, crawlDelay time.Duration) (*HttpResponse, error) {
+	target, err := url.Parse(rawUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	robots, err := f.robotsFor(ctx, target, crawlDelay)
+
+	if err != nil {
+		return nil, err
+	}
+
+	group := robots.FindGroup(f.agent())
+
+	if !group.Test(target.RequestURI()) {
+		return nil, server_errors.ErrCrawlerRobotsDisallowed.With(rawUrl)
+	}
+
+	if crawlDelay <= 0 {
+		crawlDelay = group.CrawlDelay
+	}
+
+	// Every attempt waits for the host, so the retries of a throttled page are throttled too
+	return f.do(ctx, rawUrl, func(ctx context.Context) error {
+		return f.wait(ctx, target.Host, crawlDelay)
+	})
+}
+
+// do :
+// Sends a GET request carrying the User-Agent of the fetcher. Each attempt first goes through wait, if any, then holds
+// a slot of the worker pool until it is over, so no slot is held while waiting to retry.
+func (f *Fetcher) do(ctx context.Context, rawUrl string, wait func(ctx context.Context) error) (*HttpResponse, error) {
+	header := http.Header{}
+	header.Set("User-Agent", f.config.UserAgent)
+
+	return f.client.DoPaced(ctx, http.MethodGet, rawUrl, header, nil, func(ctx context.Context) (func(), error) {
+		if wait != nil {
+			if err := wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := f.workers.Acquire(ctx); err != nil {
+			return nil, err
+		}
+
+		return f.workers.Release, nil
+	})
+}
+
+// Workers :
+// Returns the worker pool bounding the requests in flight.
+func (f *Fetcher) Workers() *WorkerPool {
+	return f.workers
+}
+
+// OutletConcurrency :
+// Returns how many article pages of a single news outlet may be fetched at once.
+func (f *Fetcher) OutletConcurrency() int {
+	return f.config.OutletConcurrency
+}
+
+// Client :
+// Returns the HttpClient the fetcher sends its requests through.
+func (f *Fetcher) Client() *HttpClient {
+	return f.client
+}
+
+// agent :
+// Returns the product token of the User-Agent, which is what robots.txt groups are matched against.
+func (f *Fetcher) agent() string {
+	agent, _, _ := strings.Cut(f.config.UserAgent, "/")
+	return strings.TrimSpace(agent)
+}
+
+// robotsFor :
+// Returns the robots.txt of the host of target, fetching it again once the cached copy is older than RobotsTTL. A
+// single request is sent for each host, however many crawlers ask for it at once, and it waits for the host like any
+// other request. A robots.txt that cannot be fetched or parsed allows everything, while a server error disallows
+// everything.
+//
+// Error: will throw the error of ctx if it is cancelled before the robots.txt is known.
+func (f *Fetcher) robotsFor(ctx context.Context, target *url.URL, crawlDelay time.Duration) (*robotstxt.RobotsData, error) {
+	key := target.Scheme + "://" + target.Host
+
+	for {
+		f.mu.Lock()
+		entry, ok := f.robots[key]
+
+		if !ok || (entry.data != nil && time.Since(entry.fetchedAt) >= f.config.RobotsTTL) {
+			entry = &robotsEntry{done: make(chan struct{})}
+			f.robots[key] = entry
+			f.mu.Unlock()
+
+			return f.loadRobots(ctx, key, target.Host, crawlDelay, entry)
+		}
+		f.mu.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if entry.data != nil {
+			return entry.data, nil
+		}
+		// The crawl fetching it was cancelled, so another one takes over
+	}
+}
+
+// loadRobots :
+// Fetches the robots.txt of the host into entry, then wakes up the crawlers waiting for it.
+//
+// Error: will throw the error of ctx if it is cancelled during the fetch.
+func (f *Fetcher) loadRobots(ctx context.Context, root string, host string, crawlDelay time.Duration, entry *robotsEntry) (*robotstxt.RobotsData, error) {
+	data := f.fetchRobots(ctx, root, host, crawlDelay)
+	err := ctx.Err()
+
+	f.mu.Lock()
+	// A robots.txt missed because the crawl was cancelled says nothing about the host
+	if err != nil {
+		if f.robots[root] == entry {
+			delete(f.robots, root)
+		}
+	} else {
+		entry.data = data
+		entry.fetchedAt = time.Now()
+	}
+	f.mu.Unlock()
+	close(entry.done)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// fetchRobots :
+// Downloads and parses the robots.txt at the root of the host, once the host is ready for another request.
+func (f *Fetcher) fetchRobots(ctx context.Context, root string, host string, crawlDelay time.Duration) *robotstxt.RobotsData {
+	allowAll, _ := robotstxt.FromStatusAndBytes(http.StatusNotFound, nil)
+
+	resp, err := f.do(ctx, root+"/robots.txt", func(ctx context.Context) error {
+		return f.wait(ctx, host, crawlDelay)
+	})
+
+	if err != nil {
+		server_errors.LogContext(ctx, server_errors.HttpFetchError, server_errors.WarningLevel, "url", root+"/robots.txt", "error", err)
+		return allowAll
+	}
+
+	body := resp.Body
+	if len(body) > maxRobotsSize {
+		body = body[:maxRobotsSize]
+	}
+
+	data, err := robotstxt.FromStatusAndBytes(resp.StatusCode, body)
+
+	if err != nil {
+		server_errors.LogContext(ctx, server_errors.CrawlerRobotsParsingError, server_errors.WarningLevel,
+			"url", root+"/robots.txt", "error", err)
+		return allowAll
+	}
+
+	return data
+}
+
+// wait :
+// Blocks until the host is ready for another request. Without a crawl delay, the host bucket refills at
+// RequestsPerSecond up to Burst tokens; with one, a single request is allowed every crawlDelay. The token is given back
+// when ctx is cancelled while waiting, so an abandoned request does not delay the next ones.
+func (f *Fetcher) wait(ctx context.Context, host string, crawlDelay time.Duration) error {
+	interval := time.Duration(float64(time.Second) / f.config.RequestsPerSecond)
+	burst := float64(f.config.Burst)
+
+	if crawlDelay > 0 {
+		interval = crawlDelay
+		burst = 1
+	}
+
+	f.mu.Lock()
+	limiter, ok := f.hosts[host]
+	if !ok {
+		limiter = &hostLimiter{tokens: burst, last: time.Now()}
+		f.hosts[host] = limiter
+	}
+
+	now := time.Now()
+	limiter.tokens += float64(now.Sub(limiter.last)) / float64(interval)
+	if limiter.tokens > burst {
+		limiter.tokens = burst
+	}
+	limiter.last = now
+	limiter.tokens--
+
+	delay := time.Duration(0)
+	if limiter.tokens < 0 {
+		delay = time.Duration(-limiter.tokens * float64(interval))
+	}
+	f.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, delay); err != nil {
+		f.mu.Lock()
+		limiter.tokens = min(limiter.tokens+1, burst)
+		f.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,398 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultHttpTimeout     = 15 * time.Second
+	defaultHttpMaxRetries  = 2
+	defaultHttpMaxBodySize = 5 << 20
+	defaultHttpBaseBackoff = 500 * time.Millisecond
+	defaultHttpMaxBackoff  = 10 * time.Second
+)
+
+// HttpClientConfig :
+// Settings shared by every outbound HTTP call. Timeout bounds each attempt, MaxRetries is how many attempts follow a
+// failed one and MaxBodySize caps how many bytes of a response body are read.
+type HttpClientConfig struct {
+	Timeout     time.Duration
+	MaxRetries  int
+	MaxBodySize int64
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// HttpResponse :
+// A response whose body was already read. Url is the one that was finally served, after redirects, and Attempts is
+// how many requests it took to get it.
+type HttpResponse struct {
+	StatusCode int
+	Url        string
+	Header     http.Header
+	Body       []byte
+	Attempts   int
+}
+
+// HttpClient :
+// Wrapper of http.Client used for every outbound call: each attempt has its own deadline, bodies are read up to
+// MaxBodySize and server errors, rate limits and timeouts are retried with a jittered exponential backoff, or after the
+// delay asked by the server.
+type HttpClient struct {
+	client *http.Client
+	config HttpClientConfig
+}
+
+// NewHttpClient :
+// Creates a new HttpClient. Missing settings cascade to their defaults.
+func NewHttpClient(config HttpClientConfig) *HttpClient {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultHttpTimeout
+	}
+
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = defaultHttpMaxBodySize
+	}
+
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaultHttpBaseBackoff
+	}
+
+	if config.MaxBackoff < config.BaseBackoff {
+		config.MaxBackoff = config.BaseBackoff
+	}
+
+	return &HttpClient{
+		client: &http.Client{},
+		config: config,
+	}
+}
+
+// defaultHttpClient :
+// HttpClient used by the repositories that were not given one.
+var defaultHttpClient = NewHttpClient(HttpClientConfig{MaxRetries: defaultHttpMaxRetries})
+
+// WithTimeout :
+// Returns a copy of the client whose attempts are bounded by timeout instead, for endpoints known to be slow.
+func (hc *HttpClient) WithTimeout(timeout time.Duration) *HttpClient {
+	config := hc.config
+	config.Timeout = timeout
+
+	return &HttpClient{
+		client: hc.client,
+		config: config,
+	}
+}
+
+// WithMaxRetries :
+// Returns a copy of the client retrying a failed attempt up to maxRetries times instead.
+func (hc *HttpClient) WithMaxRetries(maxRetries int) *HttpClient {
+	config := hc.config
+	config.MaxRetries = max(maxRetries, 0)
+
+	return &HttpClient{
+		client: hc.client,
+		config: config,
+	}
+}
+
+// WithPublicNetworksOnly :
+// Returns a copy of the client refusing to connect to loopback, private, link-local and other non public addresses,
+// for the URLs given by the users. The address is checked once resolved, right before connecting, so neither a
+// redirect nor a DNS answer changing in between can lead the request inside the network of the server.
+func (hc *HttpClient) WithPublicNetworksOnly() *HttpClient {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicAddressOnly,
+	}
+
+	client := *hc.client
+	// Going through a proxy would only check the address of the proxy
+	client.Transport = &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &HttpClient{
+		client: &client,
+		config: hc.config,
+	}
+}
+
+// nonPublicPrefixes : ranges that are not reachable from the internet but are not reported by netip as private
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// publicAddressOnly :
+// Control hook of a dialer refusing every address that is not a public unicast one.
+//
+// Error: will throw HttpForbiddenAddress if the address is not public.
+func publicAddressOnly(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+
+	forbidden := !ip.IsGlobalUnicast() || ip.IsPrivate()
+	for _, prefix := range nonPublicPrefixes {
+		forbidden = forbidden || prefix.Contains(ip)
+	}
+
+	if forbidden {
+		return server_errors.ErrHttpForbiddenAddress.With(ip.String())
+	}
+
+	return nil
+}
+
+// Get :
+// Sends a GET request with the provided headers.
+//
+// Error: see Do.
+func (hc *HttpClient) Get(ctx context.Context, url string, header http.Header) (*HttpResponse, error) {
+	return hc.Do(ctx, http.MethodGet, url, header, nil)
+}
+
+// PostJSON :
+// Sends a POST request with a JSON body.
+//
+// Error: see Do.
+func (hc *HttpClient) PostJSON(ctx context.Context, url string, body []byte) (*HttpResponse, error) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+
+	return hc.Do(ctx, http.MethodPost, url, header, body)
+}
+
+// Pace :
+// Called before every attempt of a request, the first one included, to hold it until the target is ready for it. The
+// returned release, if any, is called once the attempt is over.
+type Pace func(ctx context.Context) (release func(), err error)
+
+// Do :
+// Sends the request until it succeeds or runs out of attempts. Only responses with a 5xx or 429 status and timeouts
+// are retried; any other response is returned as is, whatever its status.
+//
+// Error: see DoPaced.
+func (hc *HttpClient) Do(ctx context.Context, method string, url string, header http.Header, body []byte) (*HttpResponse, error) {
+	return hc.DoPaced(ctx, method, url, header, body, nil)
+}
+
+// DoPaced :
+// Sends the request like Do, calling pace before every attempt, so a caller throttling its requests to a host also
+// throttles the retries. The wait before a retry is the Retry-After of a 429 or 503 response when it has one, and the
+// backoff otherwise, both capped at MaxBackoff.
+//
+// Error: will throw HttpRequestFailed along with the number of attempts if no response could be collected, wrapping
+// HttpBodyTooLarge when the body exceeds MaxBodySize, which is never retried, or the error of pace.
+func (hc *HttpClient) DoPaced(ctx context.Context, method string, url string, header http.Header, body []byte, pace Pace) (*HttpResponse, error) {
+	var lastErr error
+
+	for attempt := 1; ; attempt++ {
+		resp, err := hc.pacedAttempt(ctx, method, url, header, body, pace)
+
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			resp.Attempts = attempt
+			return resp, nil
+		}
+
+		if err != nil {
+			if errors.Is(err, server_errors.ErrHttpBodyTooLarge) || !retryableError(ctx, err) {
+				return nil, server_errors.ErrHttpRequestFailed.With(fmt.Sprintf("after %d attempts", attempt)).Wrap(err)
+			}
+			lastErr = err
+		} else {
+			lastErr = fmt.Errorf("status %d", resp.StatusCode)
+		}
+
+		if attempt > hc.config.MaxRetries {
+			if err == nil {
+				// Out of attempts, the last server error is still worth returning to the caller
+				resp.Attempts = attempt
+				return resp, nil
+			}
+			return nil, server_errors.ErrHttpRequestFailed.With(fmt.Sprintf("after %d attempts", attempt)).Wrap(lastErr)
+		}
+
+		delay := hc.backoff(attempt)
+		if retryAfter, ok := hc.retryAfter(resp); ok {
+			delay = retryAfter
+		}
+
+		server_errors.LogContext(ctx, server_errors.HttpRetrying, server_errors.WarningLevel,
+			"method", method, "url", url, "attempt", attempt, "delay", delay, "error", lastErr)
+
+		if err = sleep(ctx, delay); err != nil {
+			return nil, server_errors.ErrHttpRequestFailed.With(fmt.Sprintf("after %d attempts", attempt)).Wrap(err)
+		}
+	}
+}
+
+// pacedAttempt :
+// Sends the request once pace lets it through, releasing what pace holds as soon as the attempt is over.
+func (hc *HttpClient) pacedAttempt(ctx context.Context, method string, url string, header http.Header, body []byte, pace Pace) (*HttpResponse, error) {
+	if pace != nil {
+		release, err := pace(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if release != nil {
+			defer release()
+		}
+	}
+
+	return hc.attempt(ctx, method, url, header, body)
+}
+
+// attempt :
+// Sends the request once, bounded by Timeout, and reads its body up to MaxBodySize.
+func (hc *HttpClient) attempt(ctx context.Context, method string, url string, header http.Header, body []byte) (*HttpResponse, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, hc.config.Timeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(attemptCtx, method, url, reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := hc.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Reading one byte past the cap tells a body of exactly MaxBodySize apart from a larger one
+	content, err := io.ReadAll(io.LimitReader(resp.Body, hc.config.MaxBodySize+1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(content)) > hc.config.MaxBodySize {
+		return nil, server_errors.ErrHttpBodyTooLarge.With(fmt.Sprintf("more than %d bytes", hc.config.MaxBodySize))
+	}
+
+	return &HttpResponse{
+		StatusCode: resp.StatusCode,
+		Url:        resp.Request.URL.String(),
+		Header:     resp.Header,
+		Body:       content,
+	}, nil
+}
+
+// backoff :
+// Returns how long to wait before the attempt following the provided one: a random duration up to
+// BaseBackoff * 2^(attempt-1), capped at MaxBackoff. The jitter keeps crawlers from retrying in lockstep.
+func (hc *HttpClient) backoff(attempt int) time.Duration {
+	ceiling := hc.config.BaseBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > hc.config.MaxBackoff {
+		ceiling = hc.config.MaxBackoff
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling))) + 1
+}
+
+// retryAfter :
+// Returns how long a 429 or 503 response asks to wait before the next attempt, capped at MaxBackoff. Its Retry-After
+// header holds either a number of seconds or an HTTP date.
+func (hc *HttpClient) retryAfter(resp *HttpResponse) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+	} else {
+		return 0, false
+	}
+
+	return min(max(delay, 0), hc.config.MaxBackoff), true
+}
+
+// retryableStatus :
+// Reports whether a response status is worth another attempt.
+func retryableStatus(status int) bool {
+	return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+}
+
+// retryableError :
+// Reports whether an error is a timeout of the attempt itself, rather than the cancellation of the whole request.
+func retryableError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleep :
+// Waits for the provided duration, returning early with the context error once ctx is done.
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
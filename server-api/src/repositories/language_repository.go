@@ -3,19 +3,44 @@ package repositories
 import (
 	"aletheia-server/src/errors"
 	"aletheia-server/src/models"
+	"context"
 	"database/sql"
 	"errors"
-	"github.com/lib/pq"
 	"strings"
 )
 
-type LanguageRepository struct {
+// languageColumns : columns read into a models.Language by scanLanguage
+const languageColumns = "id, name"
+
+// LanguageRepository :
+// Stores the languages the news outlets are written in. Names are stored lower-cased and are unique.
+type LanguageRepository interface {
+	AddLanguage(ctx context.Context, language models.Language) (int, error)
+	GetLanguages(ctx context.Context) ([]models.Language, error)
+	GetLanguageById(ctx context.Context, id int) (*models.Language, error)
+	GetLanguageByName(ctx context.Context, name string) (*models.Language, error)
+	RenameLanguage(ctx context.Context, id int, name string) error
+	DeleteLanguage(ctx context.Context, id int, cascade bool) error
+}
+
+// SqlLanguageRepository :
+// LanguageRepository storing the languages in the "languages" table.
+type SqlLanguageRepository struct {
 	connection *sql.DB
+	dialect    dialect
+}
+
+func NewPostgresLanguageRepository(connection *sql.DB) *SqlLanguageRepository {
+	return &SqlLanguageRepository{
+		connection: connection,
+		dialect:    postgresDialect{},
+	}
 }
 
-func NewLanguageRepository(connection *sql.DB) *LanguageRepository {
-	return &LanguageRepository{
+func NewSqliteLanguageRepository(connection *sql.DB) *SqlLanguageRepository {
+	return &SqlLanguageRepository{
 		connection: connection,
+		dialect:    sqliteDialect{},
 	}
 }
 
@@ -26,42 +51,25 @@ func NewLanguageRepository(connection *sql.DB) *LanguageRepository {
 //
 // Error: will throw LanguageTableMissing if the database is incorrectly set and the "languages" table is missing.
 //
+// Error: will throw LanguageAlreadyExists if another language already uses the provided name.
+//
 // Error: will throw LanguageParsingError if for some reason it is unable to parse the values it receives from the
 // database.
-//
-// Error: will throw LanguageClosingTableError if it fails to close the database rows.
-func (lr *LanguageRepository) AddLanguage(newsOutlet models.Language) (int, error) {
-	query, err := lr.connection.Prepare("INSERT INTO languages (name) VALUES ($1) RETURNING id")
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			server_errors.Log(server_errors.LanguageTableMissing, server_errors.ErrorLevel)
-			return -1, errors.New(server_errors.LanguageTableMissing)
-		}
-		return -1, err
-	}
-
+func (lr *SqlLanguageRepository) AddLanguage(ctx context.Context, language models.Language) (int, error) {
 	var id int
-	name := strings.ToLower(newsOutlet.Name)
-	err = query.QueryRow(name).Scan(&id)
+	err := lr.connection.QueryRowContext(ctx,
+		"INSERT INTO languages (name) VALUES ($1) RETURNING id", strings.ToLower(language.Name),
+	).Scan(&id)
 
 	if err != nil {
-		var pqErr *pq.Error
-		if errors.As(err, &pqErr) {
-			if pqErr.Code == "23505" && pqErr.Constraint == "languages_name_key" {
-				server_errors.Log(server_errors.LanguageAlreadyExists, server_errors.ErrorLevel)
-				return -1, errors.New(server_errors.LanguageAlreadyExists)
-			}
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		switch {
+		case lr.dialect.isMissingTable(err):
+			return -1, server_errors.ErrLanguageTableMissing
+		case lr.dialect.isUniqueViolation(err):
+			return -1, server_errors.ErrLanguageAlreadyExists
 		}
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return -1, errors.New(server_errors.LanguageParsingError)
-	}
-
-	err = query.Close()
-
-	if err != nil {
-		server_errors.Log(server_errors.LanguageClosingTableError, server_errors.ErrorLevel)
-		return -1, errors.New(server_errors.LanguageClosingTableError)
+		return -1, server_errors.ErrLanguageParsingError.Wrap(err)
 	}
 
 	return id, nil
@@ -78,38 +86,32 @@ func (lr *LanguageRepository) AddLanguage(newsOutlet models.Language) (int, erro
 // Error: will throw LanguageParsingError if for some reason it is unable to parse the values it receives from the
 // database.
 //
-// Error: will throw LanguageClosingTableError if it fails to close the database rows.
-func (lr *LanguageRepository) GetLanguages() ([]models.Language, error) {
-	query := "SELECT * FROM languages"
-	rows, err := lr.connection.Query(query)
+// Error: will throw LanguageClosingTableError if it fails to read every row.
+func (lr *SqlLanguageRepository) GetLanguages(ctx context.Context) ([]models.Language, error) {
+	rows, err := lr.connection.QueryContext(ctx, "SELECT "+languageColumns+" FROM languages ORDER BY id")
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return []models.Language{}, errors.New(server_errors.LanguageTableMissing)
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return []models.Language{}, server_errors.ErrLanguageTableMissing.Wrap(err)
 	}
+	defer rows.Close()
 
-	var languageList []models.Language
-	var languageObj models.Language
+	languageList := make([]models.Language, 0)
 
 	for rows.Next() {
-		err = rows.Scan(
-			&languageObj.Id,
-			&languageObj.Name,
-		)
+		language, err := scanLanguage(rows)
 
 		if err != nil {
-			server_errors.Log(err.Error(), server_errors.ErrorLevel)
-			return []models.Language{}, errors.New(server_errors.LanguageParsingError)
+			server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+			return []models.Language{}, server_errors.ErrLanguageParsingError.Wrap(err)
 		}
 
-		languageList = append(languageList, languageObj)
+		languageList = append(languageList, *language)
 	}
 
-	err = rows.Close()
-
-	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return []models.Language{}, errors.New(server_errors.LanguageClosingTableError)
+	if err = rows.Err(); err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return []models.Language{}, server_errors.ErrLanguageClosingTableError.Wrap(err)
 	}
 
 	return languageList, nil
@@ -120,46 +122,146 @@ func (lr *LanguageRepository) GetLanguages() ([]models.Language, error) {
 // moment.
 //
 // Error: will throw LanguageNotFound if a language with the provided id is not found.
-func (lr *LanguageRepository) GetLanguageById(id int) (*models.Language, error) {
-	query, err := lr.connection.Prepare("SELECT * FROM languages WHERE id = $1")
+//
+// Error: will throw LanguageParsingError if the row cannot be read.
+func (lr *SqlLanguageRepository) GetLanguageById(ctx context.Context, id int) (*models.Language, error) {
+	return lr.getLanguage(ctx, "id = $1", id)
+}
+
+// GetLanguageByName :
+// Returns a "language" instance by name. Even though it may fail, it should not crash the application at any given
+// moment.
+//
+// Error: will throw LanguageNotFound if a language with the provided name is not found.
+//
+// Error: will throw LanguageParsingError if the row cannot be read.
+func (lr *SqlLanguageRepository) GetLanguageByName(ctx context.Context, name string) (*models.Language, error) {
+	return lr.getLanguage(ctx, "name = $1", strings.ToLower(name))
+}
+
+// getLanguage :
+// Returns the single language matching the provided condition.
+func (lr *SqlLanguageRepository) getLanguage(ctx context.Context, condition string, arg any) (*models.Language, error) {
+	language, err := scanLanguage(lr.connection.QueryRowContext(ctx,
+		"SELECT "+languageColumns+" FROM languages WHERE "+condition, arg,
+	))
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return nil, errors.New(server_errors.LanguageNotFound)
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, server_errors.ErrLanguageNotFound
+		}
+		return nil, server_errors.ErrLanguageParsingError.Wrap(err)
 	}
 
-	var languageObj models.Language
-	err = query.QueryRow(id).Scan(&languageObj.Id, &languageObj.Name)
+	return language, nil
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// RenameLanguage :
+// Changes the name of the language with the provided id.
+//
+// Error: will throw LanguageNotFound if a language with the provided id is not found.
+//
+// Error: will throw LanguageAlreadyExists if another language already uses the provided name.
+//
+// Error: will throw LanguageNotUpdated if the database fails to update the row.
+func (lr *SqlLanguageRepository) RenameLanguage(ctx context.Context, id int, name string) error {
+	result, err := lr.connection.ExecContext(ctx,
+		"UPDATE languages SET name = $1 WHERE id = $2", strings.ToLower(name), id,
+	)
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return nil, errors.New(server_errors.LanguageNotFound)
+		if lr.dialect.isUniqueViolation(err) {
+			server_errors.LogContext(ctx, server_errors.LanguageAlreadyExists, server_errors.ErrorLevel)
+			return server_errors.ErrLanguageAlreadyExists
+		}
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrLanguageNotUpdated.Wrap(err)
 	}
 
-	return &languageObj, nil
+	return checkAffectedRows(result, server_errors.ErrLanguageNotFound, server_errors.ErrLanguageNotUpdated)
 }
 
-// GetLanguageByName :
-// Returns a "language" instance by name. Even though it may fail, it should not crash the application at any given
-// moment.
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// DeleteLanguage :
+// Removes the language with the provided id from the database. The news outlets using the language are removed along
+// with it only when cascade is set; otherwise the language is kept while any of them references it. The check and the
+// removal run in the same transaction so an outlet added in between is never wiped silently.
 //
-// Error: will throw LanguageNotFound if a language with the provided name is not found.
-func (lr *LanguageRepository) GetLanguageByName(name string) (*models.Language, error) {
-	query, err := lr.connection.Prepare("SELECT * FROM languages WHERE name = $1")
+// Error: will throw LanguageNotFound if a language with the provided id is not found.
+//
+// Error: will throw LanguageInUse if news outlets still use the language and cascade is not set.
+//
+// Error: will throw LanguageNotDeleted if the database fails to delete the row.
+func (lr *SqlLanguageRepository) DeleteLanguage(ctx context.Context, id int, cascade bool) error {
+	tx, err := lr.connection.BeginTx(ctx, nil)
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return nil, err
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrLanguageNotDeleted.Wrap(err)
+	}
+	defer tx.Rollback()
+
+	// Locking the language keeps news outlets from being added to it until the transaction ends
+	var languageId int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM languages WHERE id = $1"+lr.dialect.forUpdate(), id).Scan(&languageId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			server_errors.LogContext(ctx, server_errors.LanguageNotFound, server_errors.ErrorLevel)
+			return server_errors.ErrLanguageNotFound
+		}
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrLanguageNotDeleted.Wrap(err)
 	}
 
-	var languageObj models.Language
-	name = strings.ToLower(name)
-	err = query.QueryRow(name).Scan(&languageObj.Id, &languageObj.Name)
+	if !cascade {
+		var newsOutlets int
+		err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM news_outlet WHERE languageId = $1", id).Scan(&newsOutlets)
+
+		if err != nil {
+			server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+			return server_errors.ErrLanguageNotDeleted.Wrap(err)
+		}
+
+		if newsOutlets > 0 {
+			server_errors.LogContext(ctx, server_errors.LanguageInUse, server_errors.WarningLevel)
+			return server_errors.ErrLanguageInUse
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM languages WHERE id = $1", id)
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return nil, errors.New(server_errors.LanguageNotFound)
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrLanguageNotDeleted.Wrap(err)
+	}
+
+	err = checkAffectedRows(result, server_errors.ErrLanguageNotFound, server_errors.ErrLanguageNotDeleted)
+
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrLanguageNotDeleted.Wrap(err)
+	}
+
+	return nil
+}
+
+// scanLanguage :
+// Reads a row holding the languageColumns.
+func scanLanguage(row rowScanner) (*models.Language, error) {
+	var language models.Language
+
+	if err := row.Scan(&language.Id, &language.Name); err != nil {
+		return nil, err
 	}
 
-	return &languageObj, nil
+	return &language, nil
 }
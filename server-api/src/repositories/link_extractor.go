@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// ExtractLinks :
+// Applies the CSS selector of a news outlet to one of its search results pages and returns the article links found,
+// in document order and without duplicates. The selector may target the anchors themselves or the elements wrapping
+// them. Relative links are resolved against pageUrl, and only http(s) links are kept.
+//
+// Error: will throw CrawlerEmptyHtmlSelector if the selector is empty.
+//
+// Error: will throw CrawlerInvalidHtmlSelector if the selector cannot be compiled.
+func ExtractLinks(html string, selector string, pageUrl string) ([]string, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return nil, server_errors.ErrCrawlerEmptyHtmlSelector
+	}
+
+	matcher, err := cascadia.Compile(selector)
+
+	if err != nil {
+		return nil, server_errors.ErrCrawlerInvalidHtmlSelector.Wrap(err)
+	}
+
+	base, err := url.Parse(pageUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]string, 0)
+	seen := make(map[string]bool)
+
+	document.FindMatcher(matcher).Each(func(_ int, selection *goquery.Selection) {
+		anchors := selection.Filter("a[href]")
+		if anchors.Length() == 0 {
+			anchors = selection.Find("a[href]")
+		}
+
+		anchors.Each(func(_ int, anchor *goquery.Selection) {
+			href, _ := anchor.Attr("href")
+			link, ok := resolveLink(base, href)
+
+			if ok && !seen[link] {
+				seen[link] = true
+				links = append(links, link)
+			}
+		})
+	})
+
+	return links, nil
+}
+
+// resolveLink :
+// Resolves href against base, discarding fragments and anything that is not an http(s) link.
+func resolveLink(base *url.URL, href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return "", false
+	}
+
+	reference, err := url.Parse(href)
+
+	if err != nil {
+		return "", false
+	}
+
+	resolved := base.ResolveReference(reference)
+	resolved.Fragment = ""
+
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return "", false
+	}
+
+	return resolved.String(), true
+}
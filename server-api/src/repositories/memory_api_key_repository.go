@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"time"
+)
+
+// MemoryApiKeyRepository :
+// ApiKeyRepository keeping the API keys in a MemoryStore, by the hash of the key.
+type MemoryApiKeyRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryApiKeyRepository(store *MemoryStore) *MemoryApiKeyRepository {
+	return &MemoryApiKeyRepository{
+		store: store,
+	}
+}
+
+// Create --------------------------------------------------------------------------------------------------------------
+
+// AddApiKey :
+// Stores a new API key with the provided hash and returns it as stored.
+//
+// Error: will throw ApiKeyNotSaved if another API key already has the provided hash.
+func (ar *MemoryApiKeyRepository) AddApiKey(_ context.Context, name string, role string, prefix string, hash string) (*models.ApiKey, error) {
+	ar.store.mutex.Lock()
+	defer ar.store.mutex.Unlock()
+
+	for _, stored := range ar.store.apiKeys {
+		if stored.hash == hash {
+			return nil, server_errors.ErrApiKeyNotSaved
+		}
+	}
+
+	ar.store.lastApiKeyId++
+	apiKey := models.ApiKey{
+		Id:        ar.store.lastApiKeyId,
+		Name:      name,
+		Role:      role,
+		Prefix:    prefix,
+		CreatedAt: time.Now(),
+	}
+	ar.store.apiKeys = append(ar.store.apiKeys, memoryApiKey{apiKey: apiKey, hash: hash})
+
+	return &apiKey, nil
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetApiKeys :
+// Returns every API key, revoked ones included, oldest first.
+func (ar *MemoryApiKeyRepository) GetApiKeys(_ context.Context) ([]models.ApiKey, error) {
+	ar.store.mutex.Lock()
+	defer ar.store.mutex.Unlock()
+
+	apiKeys := make([]models.ApiKey, 0, len(ar.store.apiKeys))
+
+	for _, stored := range ar.store.apiKeys {
+		apiKeys = append(apiKeys, stored.apiKey)
+	}
+
+	return apiKeys, nil
+}
+
+// GetApiKeyByHash :
+// Returns the API key with the provided hash, as long as it was not revoked, and records that it was used. The time
+// of use is only updated once a minute, like in the database.
+//
+// Error: will throw ApiKeyNotFound if no active API key has the provided hash.
+func (ar *MemoryApiKeyRepository) GetApiKeyByHash(_ context.Context, hash string) (*models.ApiKey, error) {
+	ar.store.mutex.Lock()
+	defer ar.store.mutex.Unlock()
+
+	for i, stored := range ar.store.apiKeys {
+		if stored.hash != hash || stored.apiKey.RevokedAt != nil {
+			continue
+		}
+
+		now := time.Now()
+		if lastUsedAt := stored.apiKey.LastUsedAt; lastUsedAt == nil || lastUsedAt.Before(now.Add(-time.Minute)) {
+			ar.store.apiKeys[i].apiKey.LastUsedAt = &now
+		}
+
+		// The key is returned as it was read, before its use is recorded
+		apiKey := stored.apiKey
+		return &apiKey, nil
+	}
+
+	return nil, server_errors.ErrApiKeyNotFound
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// RevokeApiKey :
+// Revokes the API key with the provided id, which is kept so the keys issued can still be audited. Revoking a revoked
+// key changes nothing.
+//
+// Error: will throw ApiKeyNotFound if an API key with the provided id is not found.
+func (ar *MemoryApiKeyRepository) RevokeApiKey(_ context.Context, id int) error {
+	ar.store.mutex.Lock()
+	defer ar.store.mutex.Unlock()
+
+	for i, stored := range ar.store.apiKeys {
+		if stored.apiKey.Id != id {
+			continue
+		}
+
+		if stored.apiKey.RevokedAt == nil {
+			now := time.Now()
+			ar.store.apiKeys[i].apiKey.RevokedAt = &now
+		}
+
+		return nil
+	}
+
+	return server_errors.ErrApiKeyNotFound
+}
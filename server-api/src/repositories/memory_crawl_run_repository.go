@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"cmp"
+	"context"
+	"slices"
+)
+
+// MemoryCrawlRunRepository :
+// CrawlRunRepository keeping the crawl runs and their verdicts in a MemoryStore.
+type MemoryCrawlRunRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryCrawlRunRepository(store *MemoryStore) *MemoryCrawlRunRepository {
+	return &MemoryCrawlRunRepository{
+		store: store,
+	}
+}
+
+// Create --------------------------------------------------------------------------------------------------------------
+
+// SaveCrawlRun :
+// Stores the crawl result along with its crawlers and their articles and returns the id of the new crawl run. Like in
+// the database, the raw pages of the articles are not kept.
+func (cr *MemoryCrawlRunRepository) SaveCrawlRun(_ context.Context, result models.CrawlResult) (int, error) {
+	cr.store.mutex.Lock()
+	defer cr.store.mutex.Unlock()
+
+	cr.store.lastCrawlRunId++
+
+	run := models.CrawlResult{
+		RunId:        cr.store.lastCrawlRunId,
+		Query:        result.Query,
+		PagesToVisit: result.PagesToVisit,
+		StartedAt:    result.StartedAt,
+		FinishedAt:   result.FinishedAt,
+		DurationMs:   result.DurationMs,
+		Crawlers:     make([]models.CrawlerResult, 0, len(result.Crawlers)),
+	}
+
+	for _, crawler := range result.Crawlers {
+		stored := copyCrawlerResult(crawler)
+
+		if stored.Failures == nil {
+			stored.Failures = make([]models.FetchFailure, 0)
+		}
+
+		if stored.VisitedUrls == nil {
+			stored.VisitedUrls = make([]string, 0)
+		}
+
+		for i := range stored.Articles {
+			stored.Articles[i].RawHtml = ""
+			if stored.Articles[i].ContentHash == "" {
+				stored.Articles[i].ContentHash = contentHash(stored.Articles[i].Text)
+			}
+		}
+
+		run.Crawlers = append(run.Crawlers, stored)
+	}
+
+	slices.SortStableFunc(run.Crawlers, func(a, b models.CrawlerResult) int { return cmp.Compare(a.CrawlerId, b.CrawlerId) })
+	cr.store.crawlRuns = append(cr.store.crawlRuns, memoryCrawlRun{run: run})
+
+	return run.RunId, nil
+}
+
+// SaveVerdict :
+// Stores the verdict of a fact check as part of the crawl run it is based on.
+//
+// Error: will throw VerdictNotSaved if the crawl run with the provided id is not found.
+func (cr *MemoryCrawlRunRepository) SaveVerdict(_ context.Context, runId int, result models.FactCheckResult) error {
+	cr.store.mutex.Lock()
+	defer cr.store.mutex.Unlock()
+
+	i := cr.store.crawlRun(runId)
+
+	if i < 0 {
+		return server_errors.ErrVerdictNotSaved
+	}
+
+	cr.store.crawlRuns[i].verdicts = append(cr.store.crawlRuns[i].verdicts, models.Verdict{
+		Post:       result.Post,
+		Prompt:     result.Prompt,
+		Verdict:    result.Verdict,
+		Score:      result.Score,
+		Analyses:   slices.Clone(result.Analyses),
+		StartedAt:  result.StartedAt,
+		FinishedAt: result.FinishedAt,
+		DurationMs: result.DurationMs,
+	})
+
+	return nil
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetCrawlRuns :
+// Returns a page of the stored crawl runs, newest first, along with how many crawlers and articles each of them had.
+func (cr *MemoryCrawlRunRepository) GetCrawlRuns(_ context.Context, limit int, offset int) ([]models.CrawlRunSummary, error) {
+	cr.store.mutex.Lock()
+	defer cr.store.mutex.Unlock()
+
+	stored := slices.Clone(cr.store.crawlRuns)
+	slices.SortFunc(stored, func(a, b memoryCrawlRun) int {
+		if order := b.run.StartedAt.Compare(a.run.StartedAt); order != 0 {
+			return order
+		}
+		return cmp.Compare(b.run.RunId, a.run.RunId)
+	})
+
+	runs := make([]models.CrawlRunSummary, 0)
+
+	for i := offset; i < len(stored) && len(runs) < limit; i++ {
+		run := models.CrawlRunSummary{
+			Id:           stored[i].run.RunId,
+			Query:        stored[i].run.Query,
+			PagesToVisit: stored[i].run.PagesToVisit,
+			StartedAt:    stored[i].run.StartedAt,
+			FinishedAt:   stored[i].run.FinishedAt,
+			DurationMs:   stored[i].run.DurationMs,
+			Crawlers:     len(stored[i].run.Crawlers),
+		}
+
+		for _, crawler := range stored[i].run.Crawlers {
+			run.Articles += len(crawler.Articles)
+		}
+
+		if verdicts := stored[i].verdicts; len(verdicts) > 0 {
+			run.Verdict = verdicts[len(verdicts)-1].Verdict
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// GetCrawlRunById :
+// Returns the crawl run with the provided id along with its crawlers, their articles and its latest verdict, if any.
+//
+// Error: will throw CrawlRunNotFound if a crawl run with the provided id is not found.
+func (cr *MemoryCrawlRunRepository) GetCrawlRunById(_ context.Context, id int) (*models.CrawlRun, error) {
+	cr.store.mutex.Lock()
+	defer cr.store.mutex.Unlock()
+
+	i := cr.store.crawlRun(id)
+
+	if i < 0 {
+		return nil, server_errors.ErrCrawlRunNotFound
+	}
+
+	stored := cr.store.crawlRuns[i]
+	run := models.CrawlRun{CrawlResult: stored.run}
+	run.Crawlers = make([]models.CrawlerResult, 0, len(stored.run.Crawlers))
+
+	for _, crawler := range stored.run.Crawlers {
+		run.Crawlers = append(run.Crawlers, copyCrawlerResult(crawler))
+	}
+
+	if len(stored.verdicts) > 0 {
+		verdict := stored.verdicts[len(stored.verdicts)-1]
+		verdict.Analyses = slices.Clone(verdict.Analyses)
+		run.Verdict = &verdict
+	}
+
+	return &run, nil
+}
+
+// copyCrawlerResult :
+// Returns a copy of the crawler result sharing none of its slices, so the stored runs cannot be changed from outside.
+func copyCrawlerResult(crawler models.CrawlerResult) models.CrawlerResult {
+	crawler.VisitedUrls = slices.Clone(crawler.VisitedUrls)
+	crawler.Failures = slices.Clone(crawler.Failures)
+	crawler.Articles = append(make([]models.Article, 0, len(crawler.Articles)), crawler.Articles...)
+
+	return crawler
+}
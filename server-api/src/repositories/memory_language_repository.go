@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"slices"
+	"strings"
+)
+
+// MemoryLanguageRepository :
+// LanguageRepository keeping the languages in a MemoryStore.
+type MemoryLanguageRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryLanguageRepository(store *MemoryStore) *MemoryLanguageRepository {
+	return &MemoryLanguageRepository{
+		store: store,
+	}
+}
+
+// Create --------------------------------------------------------------------------------------------------------------
+
+// AddLanguage :
+// Stores a new language based on the model received as parameter and returns its id.
+//
+// Error: will throw LanguageAlreadyExists if another language already uses the provided name.
+func (lr *MemoryLanguageRepository) AddLanguage(_ context.Context, language models.Language) (int, error) {
+	lr.store.mutex.Lock()
+	defer lr.store.mutex.Unlock()
+
+	name := strings.ToLower(language.Name)
+
+	if lr.store.languageByName(name) >= 0 {
+		return -1, server_errors.ErrLanguageAlreadyExists
+	}
+
+	lr.store.lastLanguageId++
+	lr.store.languages = append(lr.store.languages, models.Language{Id: lr.store.lastLanguageId, Name: name})
+
+	return lr.store.lastLanguageId, nil
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetLanguages :
+// Returns all the languages stored, by id.
+func (lr *MemoryLanguageRepository) GetLanguages(_ context.Context) ([]models.Language, error) {
+	lr.store.mutex.Lock()
+	defer lr.store.mutex.Unlock()
+
+	return slices.Clone(lr.store.languages), nil
+}
+
+// GetLanguageById :
+// Returns the language with the provided id.
+//
+// Error: will throw LanguageNotFound if a language with the provided id is not found.
+func (lr *MemoryLanguageRepository) GetLanguageById(_ context.Context, id int) (*models.Language, error) {
+	lr.store.mutex.Lock()
+	defer lr.store.mutex.Unlock()
+
+	i := lr.store.language(id)
+
+	if i < 0 {
+		return nil, server_errors.ErrLanguageNotFound
+	}
+
+	language := lr.store.languages[i]
+	return &language, nil
+}
+
+// GetLanguageByName :
+// Returns the language with the provided name, whatever its case.
+//
+// Error: will throw LanguageNotFound if a language with the provided name is not found.
+func (lr *MemoryLanguageRepository) GetLanguageByName(_ context.Context, name string) (*models.Language, error) {
+	lr.store.mutex.Lock()
+	defer lr.store.mutex.Unlock()
+
+	i := lr.store.languageByName(strings.ToLower(name))
+
+	if i < 0 {
+		return nil, server_errors.ErrLanguageNotFound
+	}
+
+	language := lr.store.languages[i]
+	return &language, nil
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// RenameLanguage :
+// Changes the name of the language with the provided id.
+//
+// Error: will throw LanguageNotFound if a language with the provided id is not found.
+//
+// Error: will throw LanguageAlreadyExists if another language already uses the provided name.
+func (lr *MemoryLanguageRepository) RenameLanguage(_ context.Context, id int, name string) error {
+	lr.store.mutex.Lock()
+	defer lr.store.mutex.Unlock()
+
+	i := lr.store.language(id)
+
+	if i < 0 {
+		return server_errors.ErrLanguageNotFound
+	}
+
+	name = strings.ToLower(name)
+
+	if other := lr.store.languageByName(name); other >= 0 && other != i {
+		return server_errors.ErrLanguageAlreadyExists
+	}
+
+	lr.store.languages[i].Name = name
+	return nil
+}
+
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// DeleteLanguage :
+// Removes the language with the provided id. The news outlets using the language are removed along with it only when
+// cascade is set; otherwise the language is kept while any of them references it.
+//
+// Error: will throw LanguageNotFound if a language with the provided id is not found.
+//
+// Error: will throw LanguageInUse if news outlets still use the language and cascade is not set.
+func (lr *MemoryLanguageRepository) DeleteLanguage(_ context.Context, id int, cascade bool) error {
+	lr.store.mutex.Lock()
+	defer lr.store.mutex.Unlock()
+
+	i := lr.store.language(id)
+
+	if i < 0 {
+		return server_errors.ErrLanguageNotFound
+	}
+
+	usesLanguage := func(stored memoryNewsOutlet) bool { return stored.languageId == id }
+
+	if !cascade && slices.ContainsFunc(lr.store.newsOutlets, usesLanguage) {
+		return server_errors.ErrLanguageInUse
+	}
+
+	lr.store.newsOutlets = slices.DeleteFunc(lr.store.newsOutlets, usesLanguage)
+	lr.store.languages = slices.Delete(lr.store.languages, i, i+1)
+
+	return nil
+}
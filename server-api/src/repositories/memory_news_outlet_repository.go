@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"slices"
+	"strings"
+)
+
+// MemoryNewsOutletRepository :
+// NewsOutletRepository keeping the news outlets in a MemoryStore, along with the languages they reference.
+type MemoryNewsOutletRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryNewsOutletRepository(store *MemoryStore) *MemoryNewsOutletRepository {
+	return &MemoryNewsOutletRepository{
+		store: store,
+	}
+}
+
+// Create --------------------------------------------------------------------------------------------------------------
+
+// AddNewsOutlet :
+// Stores a new news outlet based on the model received as parameter and returns its id.
+//
+// Error: will throw NewsOutletUnknownLanguage if the provided language is not stored.
+//
+// Error: will throw NewsOutletAlreadyExists if another news outlet already uses the provided name.
+func (no *MemoryNewsOutletRepository) AddNewsOutlet(_ context.Context, newsOutlet models.NewsOutlet) (int, error) {
+	no.store.mutex.Lock()
+	defer no.store.mutex.Unlock()
+
+	languageId, err := no.languageId(newsOutlet.Language)
+
+	if err != nil {
+		return -1, err
+	}
+
+	newsOutlet.Name = strings.ToLower(newsOutlet.Name)
+
+	if no.store.newsOutletByName(newsOutlet.Name) >= 0 {
+		return -1, server_errors.ErrNewsOutletAlreadyExists
+	}
+
+	no.store.lastNewsOutletId++
+	newsOutlet.Id = no.store.lastNewsOutletId
+	no.store.newsOutlets = append(no.store.newsOutlets, memoryNewsOutlet{newsOutlet: newsOutlet, languageId: languageId})
+
+	return newsOutlet.Id, nil
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetNewsOutlets :
+// Returns all the news outlets stored, by id, along with the name of their language.
+func (no *MemoryNewsOutletRepository) GetNewsOutlets(_ context.Context) ([]models.NewsOutlet, error) {
+	no.store.mutex.Lock()
+	defer no.store.mutex.Unlock()
+
+	newsOutletList := make([]models.NewsOutlet, 0, len(no.store.newsOutlets))
+
+	for _, stored := range no.store.newsOutlets {
+		newsOutletList = append(newsOutletList, no.read(stored))
+	}
+
+	return newsOutletList, nil
+}
+
+// GetNewsOutletByName :
+// Returns the news outlet with the provided name, whatever its case.
+//
+// Error: will throw NewsOutletNotFound if a news outlet with the provided name is not found.
+func (no *MemoryNewsOutletRepository) GetNewsOutletByName(_ context.Context, name string) (*models.NewsOutlet, error) {
+	no.store.mutex.Lock()
+	defer no.store.mutex.Unlock()
+
+	i := no.store.newsOutletByName(strings.ToLower(name))
+
+	if i < 0 {
+		return nil, server_errors.ErrNewsOutletNotFound
+	}
+
+	newsOutlet := no.read(no.store.newsOutlets[i])
+	return &newsOutlet, nil
+}
+
+// GetNewsOutletById :
+// Returns the news outlet with the provided id.
+//
+// Error: will throw NewsOutletNotFound if a news outlet with the provided id is not found.
+func (no *MemoryNewsOutletRepository) GetNewsOutletById(_ context.Context, id int) (*models.NewsOutlet, error) {
+	no.store.mutex.Lock()
+	defer no.store.mutex.Unlock()
+
+	i := no.store.newsOutlet(id)
+
+	if i < 0 {
+		return nil, server_errors.ErrNewsOutletNotFound
+	}
+
+	newsOutlet := no.read(no.store.newsOutlets[i])
+	return &newsOutlet, nil
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// UpdateNewsOutlet :
+// Replaces every field of the news outlet with the provided id by the values of the model received as parameter.
+//
+// Error: will throw NewsOutletUnknownLanguage if the provided language is not stored.
+//
+// Error: will throw NewsOutletNotFound if a news outlet with the provided id is not found.
+//
+// Error: will throw NewsOutletAlreadyExists if another news outlet already uses the provided name.
+func (no *MemoryNewsOutletRepository) UpdateNewsOutlet(_ context.Context, id int, newsOutlet models.NewsOutlet) error {
+	no.store.mutex.Lock()
+	defer no.store.mutex.Unlock()
+
+	languageId, err := no.languageId(newsOutlet.Language)
+
+	if err != nil {
+		return err
+	}
+
+	i := no.store.newsOutlet(id)
+
+	if i < 0 {
+		return server_errors.ErrNewsOutletNotFound
+	}
+
+	newsOutlet.Id = id
+	newsOutlet.Name = strings.ToLower(newsOutlet.Name)
+
+	if other := no.store.newsOutletByName(newsOutlet.Name); other >= 0 && other != i {
+		return server_errors.ErrNewsOutletAlreadyExists
+	}
+
+	no.store.newsOutlets[i] = memoryNewsOutlet{newsOutlet: newsOutlet, languageId: languageId}
+	return nil
+}
+
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// DeleteNewsOutlet :
+// Removes the news outlet with the provided id.
+//
+// Error: will throw NewsOutletNotFound if a news outlet with the provided id is not found.
+func (no *MemoryNewsOutletRepository) DeleteNewsOutlet(_ context.Context, id int) error {
+	no.store.mutex.Lock()
+	defer no.store.mutex.Unlock()
+
+	i := no.store.newsOutlet(id)
+
+	if i < 0 {
+		return server_errors.ErrNewsOutletNotFound
+	}
+
+	no.store.newsOutlets = slices.Delete(no.store.newsOutlets, i, i+1)
+	return nil
+}
+
+// languageId :
+// Returns the id of the language with the provided name. The store must be locked.
+//
+// Error: will throw NewsOutletUnknownLanguage if the language is not stored.
+func (no *MemoryNewsOutletRepository) languageId(name string) (int, error) {
+	i := no.store.languageByName(strings.ToLower(name))
+
+	if i < 0 {
+		return -1, server_errors.ErrNewsOutletUnknownLanguage.With(name)
+	}
+
+	return no.store.languages[i].Id, nil
+}
+
+// read :
+// Returns the stored news outlet along with the current name of its language. The store must be locked.
+func (no *MemoryNewsOutletRepository) read(stored memoryNewsOutlet) models.NewsOutlet {
+	newsOutlet := stored.newsOutlet
+	newsOutlet.Language = no.store.languages[no.store.language(stored.languageId)].Name
+
+	return newsOutlet
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// MemoryQuotaRepository :
+// QuotaRepository counting the crawls of each client in a MemoryStore.
+type MemoryQuotaRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryQuotaRepository(store *MemoryStore) *MemoryQuotaRepository {
+	return &MemoryQuotaRepository{
+		store: store,
+	}
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// ConsumeQuota :
+// Counts one more crawl for the client on the provided day, as long as it started fewer than limit crawls that day,
+// and returns how many it started. When the limit is reached, nothing is counted and false is returned.
+func (qr *MemoryQuotaRepository) ConsumeQuota(_ context.Context, client string, day time.Time, limit int) (int, bool, error) {
+	qr.store.mutex.Lock()
+	defer qr.store.mutex.Unlock()
+
+	key := memoryQuota{client: client, day: day.Format(time.DateOnly)}
+	used, counted := qr.store.quotas[key]
+
+	// Like the upsert of the database, the first crawl of the day is always counted
+	if counted && used >= limit {
+		return limit, false, nil
+	}
+
+	qr.store.quotas[key] = used + 1
+	return used + 1, true, nil
+}
+
+// RefundQuota :
+// Gives back a crawl counted for the client on the provided day, when the crawl could not be started after all, and
+// returns how many crawls remain counted. The count never goes below zero.
+func (qr *MemoryQuotaRepository) RefundQuota(_ context.Context, client string, day time.Time) (int, error) {
+	qr.store.mutex.Lock()
+	defer qr.store.mutex.Unlock()
+
+	key := memoryQuota{client: client, day: day.Format(time.DateOnly)}
+	used := max(qr.store.quotas[key]-1, 0)
+
+	if _, counted := qr.store.quotas[key]; counted {
+		qr.store.quotas[key] = used
+	}
+
+	return used, nil
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"aletheia-server/src/models"
+	"sync"
+)
+
+// MemoryStore :
+// In-memory counterpart of the database, shared by the Memory*Repository implementations the way the Postgres ones
+// share a connection, so deleting a language can look at the news outlets using it. Ids are never reused, like the
+// SERIAL columns they stand for. It is safe for concurrent use, and meant for tests and for trying the API out.
+type MemoryStore struct {
+	mutex sync.Mutex
+
+	languages   []models.Language
+	newsOutlets []memoryNewsOutlet
+	apiKeys     []memoryApiKey
+	crawlRuns   []memoryCrawlRun
+	quotas      map[memoryQuota]int
+
+	lastLanguageId   int
+	lastNewsOutletId int
+	lastApiKeyId     int
+	lastCrawlRunId   int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		languages:   make([]models.Language, 0),
+		newsOutlets: make([]memoryNewsOutlet, 0),
+		apiKeys:     make([]memoryApiKey, 0),
+		crawlRuns:   make([]memoryCrawlRun, 0),
+		quotas:      make(map[memoryQuota]int),
+	}
+}
+
+// memoryNewsOutlet : a news outlet as stored, referencing its language by id so renaming the language renames it too
+type memoryNewsOutlet struct {
+	newsOutlet models.NewsOutlet
+	languageId int
+}
+
+// memoryApiKey : an API key as stored, along with the hash it is looked up by
+type memoryApiKey struct {
+	apiKey models.ApiKey
+	hash   string
+}
+
+// memoryCrawlRun : a crawl run as stored, along with the verdicts based on it, oldest first
+type memoryCrawlRun struct {
+	run      models.CrawlResult
+	verdicts []models.Verdict
+}
+
+// memoryQuota : key of the crawls counted for a client on a day
+type memoryQuota struct {
+	client string
+	day    string
+}
+
+// language :
+// Returns the position of the language with the provided id, or -1. The store must be locked.
+func (ms *MemoryStore) language(id int) int {
+	for i, language := range ms.languages {
+		if language.Id == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// languageByName :
+// Returns the position of the language with the provided lower-cased name, or -1. The store must be locked.
+func (ms *MemoryStore) languageByName(name string) int {
+	for i, language := range ms.languages {
+		if language.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// newsOutlet :
+// Returns the position of the news outlet with the provided id, or -1. The store must be locked.
+func (ms *MemoryStore) newsOutlet(id int) int {
+	for i, stored := range ms.newsOutlets {
+		if stored.newsOutlet.Id == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// newsOutletByName :
+// Returns the position of the news outlet with the provided lower-cased name, or -1. The store must be locked.
+func (ms *MemoryStore) newsOutletByName(name string) int {
+	for i, stored := range ms.newsOutlets {
+		if stored.newsOutlet.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// crawlRun :
+// Returns the position of the crawl run with the provided id, or -1. The store must be locked.
+func (ms *MemoryStore) crawlRun(id int) int {
+	for i, stored := range ms.crawlRuns {
+		if stored.run.RunId == id {
+			return i
+		}
+	}
+
+	return -1
+}
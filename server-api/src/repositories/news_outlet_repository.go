@@ -3,20 +3,48 @@ package repositories
 import (
 	"aletheia-server/src/errors"
 	"aletheia-server/src/models"
+	"context"
 	"database/sql"
 	"errors"
 	"strings"
 )
 
-type NewsOutletRepository struct {
+// newsOutletSelect : reads the news outlets along with the name of their language, in the order of scanNewsOutlet
+const newsOutletSelect = `SELECT n.id, n.name, n.queryurl, n.htmlselector, l.name, n.credibility, n.crawldelayms
+	FROM news_outlet n JOIN languages l ON l.id = n.languageid`
+
+// NewsOutletRepository :
+// Stores the news outlets along with the language they are written in. Names are stored lower-cased and are unique.
+type NewsOutletRepository interface {
+	AddNewsOutlet(ctx context.Context, newsOutlet models.NewsOutlet) (int, error)
+	GetNewsOutlets(ctx context.Context) ([]models.NewsOutlet, error)
+	GetNewsOutletByName(ctx context.Context, name string) (*models.NewsOutlet, error)
+	GetNewsOutletById(ctx context.Context, id int) (*models.NewsOutlet, error)
+	UpdateNewsOutlet(ctx context.Context, id int, newsOutlet models.NewsOutlet) error
+	DeleteNewsOutlet(ctx context.Context, id int) error
+}
+
+// SqlNewsOutletRepository :
+// NewsOutletRepository storing the news outlets in the "news_outlet" table.
+type SqlNewsOutletRepository struct {
 	connection         *sql.DB
-	languageRepository *LanguageRepository
+	dialect            dialect
+	languageRepository LanguageRepository
 }
 
-func NewNewsOutletRepository(connection *sql.DB, languageRepository LanguageRepository) NewsOutletRepository {
-	return NewsOutletRepository{
+func NewPostgresNewsOutletRepository(connection *sql.DB, languageRepository LanguageRepository) *SqlNewsOutletRepository {
+	return &SqlNewsOutletRepository{
 		connection:         connection,
-		languageRepository: &languageRepository,
+		dialect:            postgresDialect{},
+		languageRepository: languageRepository,
+	}
+}
+
+func NewSqliteNewsOutletRepository(connection *sql.DB, languageRepository LanguageRepository) *SqlNewsOutletRepository {
+	return &SqlNewsOutletRepository{
+		connection:         connection,
+		dialect:            sqliteDialect{},
+		languageRepository: languageRepository,
 	}
 }
 
@@ -25,53 +53,38 @@ func NewNewsOutletRepository(connection *sql.DB, languageRepository LanguageRepo
 // AddNewsOutlet :
 // Creates a new news outlet inside the database based on the model received as parameter.
 //
-// Error: will throw LanguageNotFound if the provided language is not maintained inside the database.
+// Error: will throw NewsOutletUnknownLanguage if the provided language is not maintained inside the database.
 //
 // Error: will throw NewsOutletTableMissing if the database is incorrectly set and the "news_outlet" table is missing.
 //
+// Error: will throw NewsOutletAlreadyExists if another news outlet already uses the provided name.
+//
 // Error: will throw NewsOutletParsingError if for some reason it is unable to parse the values it receives from the
 // database.
-//
-// Error: will throw NewsOutletClosingTableError if it fails to close the database rows.
-func (no *NewsOutletRepository) AddNewsOutlet(newsOutlet models.NewsOutlet) (int, error) {
-	// Collect newsOutlet.Language id inside the languages table
-	language, err := no.languageRepository.GetLanguageByName(newsOutlet.Language)
-
-	if err != nil {
-		server_errors.Log(server_errors.LanguageNotFound, server_errors.ErrorLevel)
-		if errors.Is(err, sql.ErrNoRows) {
-			return -1, errors.New(server_errors.NewsOutletNotFound)
-		}
-		return -1, err
-	}
-
-	languageId := language.Id
-
-	// Insert newsOutlet into the database
-	query, err := no.connection.Prepare("INSERT INTO news_outlet (name, queryurl, htmlselector, languageid, credibility) VALUES ($1, $2, $3, $4, $5) RETURNING id")
+func (no *SqlNewsOutletRepository) AddNewsOutlet(ctx context.Context, newsOutlet models.NewsOutlet) (int, error) {
+	languageId, err := no.languageId(ctx, newsOutlet.Language)
 
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			server_errors.Log(server_errors.NewsOutletTableMissing, server_errors.ErrorLevel)
-			return -1, errors.New(server_errors.NewsOutletTableMissing)
-		}
 		return -1, err
 	}
 
 	var id int
-	name := strings.ToLower(newsOutlet.Name)
-	err = query.QueryRow(name, newsOutlet.QueryUrl, newsOutlet.HtmlSelector, languageId, newsOutlet.Credibility).Scan(&id)
+	err = no.connection.QueryRowContext(ctx,
+		`INSERT INTO news_outlet (name, queryurl, htmlselector, languageid, credibility, crawldelayms)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		strings.ToLower(newsOutlet.Name), newsOutlet.QueryUrl, newsOutlet.HtmlSelector, languageId,
+		newsOutlet.Credibility, newsOutlet.CrawlDelayMs,
+	).Scan(&id)
 
 	if err != nil {
-		server_errors.Log(server_errors.NewsOutletParsingError, server_errors.ErrorLevel)
-		return -1, errors.New(server_errors.NewsOutletParsingError)
-	}
-
-	err = query.Close()
-
-	if err != nil {
-		server_errors.Log(server_errors.NewsOutletClosingTableError, server_errors.ErrorLevel)
-		return -1, errors.New(server_errors.NewsOutletClosingTableError)
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		switch {
+		case no.dialect.isMissingTable(err):
+			return -1, server_errors.ErrNewsOutletTableMissing
+		case no.dialect.isUniqueViolation(err):
+			return -1, server_errors.ErrNewsOutletAlreadyExists
+		}
+		return -1, server_errors.ErrNewsOutletParsingError.Wrap(err)
 	}
 
 	return id, nil
@@ -80,59 +93,40 @@ func (no *NewsOutletRepository) AddNewsOutlet(newsOutlet models.NewsOutlet) (int
 // Read ----------------------------------------------------------------------------------------------------------------
 
 // GetNewsOutlets :
-// Returns all the news outlets stored in the database. Even though it may fail, it should not crash the application at
-// any given moment.
+// Returns all the news outlets stored in the database along with the name of their language, read in a single query.
+// Even though it may fail, it should not crash the application at any given moment.
 //
 // Error: will throw NewsOutletTableMissing if the database is incorrectly set and the "news_outlet" table is missing.
 //
 // Error: will throw NewsOutletParsingError if for some reason it is unable to parse the values it receives from the
 // database.
 //
-// Error: will throw NewsOutletClosingTableError if it fails to close the database rows.
-func (no *NewsOutletRepository) GetNewsOutlets() ([]models.NewsOutlet, error) {
-	query := "SELECT * FROM news_outlet"
-	rows, err := no.connection.Query(query)
+// Error: will throw NewsOutletClosingTableError if it fails to read every row.
+func (no *SqlNewsOutletRepository) GetNewsOutlets(ctx context.Context) ([]models.NewsOutlet, error) {
+	rows, err := no.connection.QueryContext(ctx, newsOutletSelect+" ORDER BY n.id")
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return []models.NewsOutlet{}, errors.New(server_errors.NewsOutletTableMissing)
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return []models.NewsOutlet{}, server_errors.ErrNewsOutletTableMissing.Wrap(err)
 	}
+	defer rows.Close()
 
-	var newsOutletList []models.NewsOutlet
-	var newsOutletObj models.NewsOutlet
-	var languageId int
+	newsOutletList := make([]models.NewsOutlet, 0)
 
 	for rows.Next() {
-		err = rows.Scan(
-			&newsOutletObj.Id,
-			&newsOutletObj.Name,
-			&newsOutletObj.QueryUrl,
-			&newsOutletObj.HtmlSelector,
-			&languageId,
-			&newsOutletObj.Credibility,
-		)
-
-		if err != nil {
-			server_errors.Log(err.Error(), server_errors.ErrorLevel)
-			return []models.NewsOutlet{}, errors.New(server_errors.NewsOutletParsingError)
-		}
-
-		language, err := no.languageRepository.GetLanguageById(languageId)
+		newsOutlet, err := scanNewsOutlet(rows)
 
 		if err != nil {
-			server_errors.Log(server_errors.LanguageNotFound, server_errors.ErrorLevel)
-			return []models.NewsOutlet{}, err
+			server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+			return []models.NewsOutlet{}, server_errors.ErrNewsOutletParsingError.Wrap(err)
 		}
 
-		newsOutletObj.Language = language.Name
-		newsOutletList = append(newsOutletList, newsOutletObj)
+		newsOutletList = append(newsOutletList, *newsOutlet)
 	}
 
-	err = rows.Close()
-
-	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return []models.NewsOutlet{}, errors.New(server_errors.NewsOutletClosingTableError)
+	if err = rows.Err(); err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return []models.NewsOutlet{}, server_errors.ErrNewsOutletClosingTableError.Wrap(err)
 	}
 
 	return newsOutletList, nil
@@ -143,66 +137,132 @@ func (no *NewsOutletRepository) GetNewsOutlets() ([]models.NewsOutlet, error) {
 // moment.
 //
 // Error: will throw NewsOutletNotFound if a news outlet with the provided name is not found.
-func (no *NewsOutletRepository) GetNewsOutletByName(name string) (*models.NewsOutlet, error) {
-	query, err := no.connection.Prepare("SELECT * FROM news_outlet WHERE name = $1")
+//
+// Error: will throw NewsOutletParsingError if the row cannot be read.
+func (no *SqlNewsOutletRepository) GetNewsOutletByName(ctx context.Context, name string) (*models.NewsOutlet, error) {
+	return no.getNewsOutlet(ctx, "n.name = $1", strings.ToLower(name))
+}
+
+// GetNewsOutletById :
+// Returns a "NewsOutlet" instance by id. Even though it may fail, it should not crash the application at any given
+// moment.
+//
+// Error: will throw NewsOutletNotFound if a news outlet with the provided id is not found.
+//
+// Error: will throw NewsOutletParsingError if the row cannot be read.
+func (no *SqlNewsOutletRepository) GetNewsOutletById(ctx context.Context, id int) (*models.NewsOutlet, error) {
+	return no.getNewsOutlet(ctx, "n.id = $1", id)
+}
+
+// getNewsOutlet :
+// Returns the single news outlet matching the provided condition.
+func (no *SqlNewsOutletRepository) getNewsOutlet(ctx context.Context, condition string, arg any) (*models.NewsOutlet, error) {
+	newsOutlet, err := scanNewsOutlet(no.connection.QueryRowContext(ctx, newsOutletSelect+" WHERE "+condition, arg))
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return nil, err
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, server_errors.ErrNewsOutletNotFound
+		}
+		return nil, server_errors.ErrNewsOutletParsingError.Wrap(err)
 	}
 
-	var newsOutletObj models.NewsOutlet
-	var languageId int
-	name = strings.ToLower(name)
-	err = query.QueryRow(name).Scan(&newsOutletObj.Id, &newsOutletObj.Name, &newsOutletObj.QueryUrl, &newsOutletObj.HtmlSelector, &languageId, &newsOutletObj.Credibility)
+	return newsOutlet, nil
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// UpdateNewsOutlet :
+// Replaces every field of the news outlet with the provided id by the values of the model received as parameter.
+//
+// Error: will throw NewsOutletUnknownLanguage if the provided language is not maintained inside the database.
+//
+// Error: will throw NewsOutletNotFound if a news outlet with the provided id is not found.
+//
+// Error: will throw NewsOutletAlreadyExists if another news outlet already uses the provided name.
+//
+// Error: will throw NewsOutletNotUpdated if the database fails to update the row.
+func (no *SqlNewsOutletRepository) UpdateNewsOutlet(ctx context.Context, id int, newsOutlet models.NewsOutlet) error {
+	languageId, err := no.languageId(ctx, newsOutlet.Language)
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return nil, errors.New(server_errors.NewsOutletNotFound)
+		return err
 	}
 
-	language, err := no.languageRepository.GetLanguageById(languageId)
+	result, err := no.connection.ExecContext(ctx,
+		`UPDATE news_outlet SET name = $1, queryurl = $2, htmlselector = $3, languageid = $4, credibility = $5,
+		crawldelayms = $6 WHERE id = $7`,
+		strings.ToLower(newsOutlet.Name), newsOutlet.QueryUrl, newsOutlet.HtmlSelector, languageId,
+		newsOutlet.Credibility, newsOutlet.CrawlDelayMs, id,
+	)
 
 	if err != nil {
-		server_errors.Log(server_errors.LanguageNotFound, server_errors.ErrorLevel)
-		return &models.NewsOutlet{}, err
+		if no.dialect.isUniqueViolation(err) {
+			server_errors.LogContext(ctx, server_errors.NewsOutletAlreadyExists, server_errors.ErrorLevel)
+			return server_errors.ErrNewsOutletAlreadyExists
+		}
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrNewsOutletNotUpdated.Wrap(err)
 	}
 
-	newsOutletObj.Language = language.Name
-
-	return &newsOutletObj, nil
+	return checkAffectedRows(result, server_errors.ErrNewsOutletNotFound, server_errors.ErrNewsOutletNotUpdated)
 }
 
-// GetNewsOutletById :
-// Returns a "NewsOutlet" instance by id. Even though it may fail, it should not crash the application at any given
-// moment.
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// DeleteNewsOutlet :
+// Removes the news outlet with the provided id from the database.
 //
 // Error: will throw NewsOutletNotFound if a news outlet with the provided id is not found.
-func (no *NewsOutletRepository) GetNewsOutletById(id int) (*models.NewsOutlet, error) {
-	query, err := no.connection.Prepare("SELECT * FROM news_outlet WHERE id = $1")
+//
+// Error: will throw NewsOutletNotDeleted if the database fails to delete the row.
+func (no *SqlNewsOutletRepository) DeleteNewsOutlet(ctx context.Context, id int) error {
+	result, err := no.connection.ExecContext(ctx, "DELETE FROM news_outlet WHERE id = $1", id)
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return nil, err
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return server_errors.ErrNewsOutletNotDeleted.Wrap(err)
 	}
 
-	var newsOutletObj models.NewsOutlet
-	var languageId int
-	err = query.QueryRow(id).Scan(&newsOutletObj.Id, &newsOutletObj.Name, &newsOutletObj.QueryUrl, &newsOutletObj.HtmlSelector, &languageId, &newsOutletObj.Credibility)
+	return checkAffectedRows(result, server_errors.ErrNewsOutletNotFound, server_errors.ErrNewsOutletNotDeleted)
+}
+
+// languageId :
+// Returns the id of the language with the provided name.
+//
+// Error: will throw NewsOutletUnknownLanguage if the language is not maintained inside the database.
+func (no *SqlNewsOutletRepository) languageId(ctx context.Context, name string) (int, error) {
+	language, err := no.languageRepository.GetLanguageByName(ctx, name)
+
+	if errors.Is(err, server_errors.ErrLanguageNotFound) {
+		return -1, server_errors.ErrNewsOutletUnknownLanguage.With(name)
+	}
 
 	if err != nil {
-		server_errors.Log(err.Error(), server_errors.ErrorLevel)
-		return nil, errors.New(server_errors.NewsOutletNotFound)
+		return -1, err
 	}
 
-	language, err := no.languageRepository.GetLanguageById(languageId)
+	return language.Id, nil
+}
+
+// scanNewsOutlet :
+// Reads a row selected by newsOutletSelect.
+func scanNewsOutlet(row rowScanner) (*models.NewsOutlet, error) {
+	var newsOutlet models.NewsOutlet
+
+	err := row.Scan(
+		&newsOutlet.Id,
+		&newsOutlet.Name,
+		&newsOutlet.QueryUrl,
+		&newsOutlet.HtmlSelector,
+		&newsOutlet.Language,
+		&newsOutlet.Credibility,
+		&newsOutlet.CrawlDelayMs,
+	)
 
 	if err != nil {
-		server_errors.Log(server_errors.LanguageNotFound, server_errors.ErrorLevel)
-		return &models.NewsOutlet{}, err
+		return nil, err
 	}
 
-	newsOutletObj.Language = language.Name
-
-	return &newsOutletObj, nil
+	return &newsOutlet, nil
 }
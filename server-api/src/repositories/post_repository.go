@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// PostRepository :
+// Collects the content of the posts submitted by the users to be fact-checked. As their URL comes from the users, the
+// posts are only fetched over http or https from public addresses, so the server cannot be used to reach its own
+// network.
+type PostRepository struct {
+	client *HttpClient
+	// publicClient : copy of client only connecting to public addresses
+	publicClient *HttpClient
+	// AllowPrivateNetworks : lets the posts be fetched from loopback, private and link-local addresses, only meant for
+	// tests serving the posts locally
+	AllowPrivateNetworks bool
+}
+
+// NewPostRepository :
+// Creates a new PostRepository. A nil client cascades to a shared one.
+func NewPostRepository(client *HttpClient) PostRepository {
+	if client == nil {
+		client = defaultHttpClient
+	}
+
+	return PostRepository{
+		client:       client,
+		publicClient: client.WithPublicNetworksOnly(),
+	}
+}
+
+// GetPost :
+// Fetches the page at the provided url and extracts its title and main text. Why the post could not be fetched is
+// only logged, so the responses never tell what lies behind the url.
+//
+// Error: will throw FactCheckPostFetchError if the url is not a public http or https one, or if the page cannot be
+// fetched or parsed.
+//
+// Error: will throw FactCheckEmptyPost if the page contains no text.
+func (pr *PostRepository) GetPost(ctx context.Context, rawUrl string) (models.Post, error) {
+	target, err := url.Parse(rawUrl)
+
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		server_errors.LogContext(ctx, server_errors.HttpForbiddenScheme, server_errors.WarningLevel, "url", rawUrl)
+		return models.Post{}, server_errors.ErrFactCheckPostFetchError.Wrap(server_errors.ErrHttpForbiddenScheme)
+	}
+
+	client := pr.publicClient
+	if pr.AllowPrivateNetworks {
+		client = pr.client
+	}
+
+	resp, err := client.Get(ctx, rawUrl, nil)
+
+	if err != nil {
+		server_errors.LogContext(ctx, server_errors.HttpFetchError, server_errors.ErrorLevel, "url", rawUrl, "error", err)
+		return models.Post{}, server_errors.ErrFactCheckPostFetchError.Wrap(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		server_errors.LogContext(ctx, server_errors.HttpUnexpectedStatus, server_errors.WarningLevel,
+			"url", rawUrl, "status", resp.StatusCode)
+		return models.Post{}, server_errors.ErrFactCheckPostFetchError
+	}
+
+	article, err := ExtractArticle(string(resp.Body), rawUrl, false)
+
+	if err != nil {
+		return models.Post{}, server_errors.ErrFactCheckPostFetchError.Wrap(err)
+	}
+
+	post := models.Post{
+		Url:     rawUrl,
+		Title:   article.Title,
+		Content: article.Text,
+	}
+
+	if post.Content == "" {
+		return models.Post{}, server_errors.ErrFactCheckEmptyPost
+	}
+
+	return post, nil
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgreSQL error codes matched by the repositories.
+const (
+	uniqueViolation = "23505"
+	undefinedTable  = "42P01"
+)
+
+// postgresDialect :
+// dialect of PostgreSQL, which stores the lists as arrays and the times along with their offset.
+type postgresDialect struct{}
+
+func (postgresDialect) array(values []string) any {
+	return pq.Array(values)
+}
+
+func (postgresDialect) scanArray(values *[]string) any {
+	return pq.Array(values)
+}
+
+func (postgresDialect) time(t time.Time) time.Time {
+	return t
+}
+
+func (postgresDialect) forUpdate() string {
+	return " FOR UPDATE"
+}
+
+func (postgresDialect) isUniqueViolation(err error) bool {
+	return isPqError(err, uniqueViolation)
+}
+
+func (postgresDialect) isMissingTable(err error) bool {
+	return isPqError(err, undefinedTable)
+}
+
+// isPqError :
+// Reports whether err was raised by PostgreSQL with the provided code.
+func isPqError(err error, code string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && string(pqErr.Code) == code
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// QuotaRepository :
+// Counts the crawls started by each client on each day.
+type QuotaRepository interface {
+	ConsumeQuota(ctx context.Context, client string, day time.Time, limit int) (int, bool, error)
+	RefundQuota(ctx context.Context, client string, day time.Time) (int, error)
+}
+
+// SqlQuotaRepository :
+// QuotaRepository storing the counts in the "crawl_quotas" table. PostgreSQL and SQLite share the upsert syntax, so it
+// needs no dialect.
+type SqlQuotaRepository struct {
+	connection *sql.DB
+}
+
+func NewPostgresQuotaRepository(connection *sql.DB) *SqlQuotaRepository {
+	return &SqlQuotaRepository{
+		connection: connection,
+	}
+}
+
+func NewSqliteQuotaRepository(connection *sql.DB) *SqlQuotaRepository {
+	return &SqlQuotaRepository{
+		connection: connection,
+	}
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// ConsumeQuota :
+// Counts one more crawl for the client on the provided day, as long as it started fewer than limit crawls that day,
+// and returns how many it started. The check and the increment are a single statement, so concurrent requests can
+// never overshoot the limit. When the limit is reached, nothing is counted and false is returned.
+//
+// Error: will throw CrawlQuotaError if the count cannot be updated.
+func (qr *SqlQuotaRepository) ConsumeQuota(ctx context.Context, client string, day time.Time, limit int) (int, bool, error) {
+	var used int
+	err := qr.connection.QueryRowContext(ctx,
+		`INSERT INTO crawl_quotas (Client, Day, Used) VALUES ($1, $2, 1)
+		ON CONFLICT (Client, Day) DO UPDATE SET Used = crawl_quotas.Used + 1 WHERE crawl_quotas.Used < $3
+		RETURNING Used`,
+		client, day.Format(time.DateOnly), limit,
+	).Scan(&used)
+
+	// The conflicting row was left untouched, the client already used its whole quota
+	if errors.Is(err, sql.ErrNoRows) {
+		return limit, false, nil
+	}
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return 0, false, server_errors.ErrCrawlQuotaError
+	}
+
+	return used, true, nil
+}
+
+// RefundQuota :
+// Gives back a crawl counted for the client on the provided day, when the crawl could not be started after all, and
+// returns how many crawls remain counted. The count never goes below zero.
+//
+// Error: will throw CrawlQuotaError if the count cannot be updated.
+func (qr *SqlQuotaRepository) RefundQuota(ctx context.Context, client string, day time.Time) (int, error) {
+	var used int
+	err := qr.connection.QueryRowContext(ctx,
+		`UPDATE crawl_quotas SET Used = Used - 1 WHERE Client = $1 AND Day = $2 AND Used > 0 RETURNING Used`,
+		client, day.Format(time.DateOnly),
+	).Scan(&used)
+
+	// Nothing was counted for the client that day, so there is nothing to give back
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		server_errors.LogContext(ctx, err.Error(), server_errors.ErrorLevel)
+		return 0, server_errors.ErrCrawlQuotaError
+	}
+
+	return used, nil
+}
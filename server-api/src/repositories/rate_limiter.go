@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter :
+// Token buckets kept in memory, one for each client. Unlike the buckets of the Fetcher, which make the crawlers wait
+// their turn, a request finding its bucket empty is refused along with how long until a token is available. Buckets
+// that refilled completely are forgotten, so clients passing by do not pile up.
+type RateLimiter struct {
+	// interval : time needed to refill a single token
+	interval time.Duration
+	burst    float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket :
+// Token bucket of a single client.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter :
+// Creates a new RateLimiter allowing each client perMinute requests a minute, up to burst of them at once. A rate that
+// is not positive cascades to a single request a minute and a burst below one to a single request.
+func NewRateLimiter(perMinute float64, burst int) *RateLimiter {
+	if perMinute <= 0 {
+		perMinute = 1
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		interval:  time.Duration(float64(time.Minute) / perMinute),
+		burst:     float64(burst),
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow :
+// Takes a token from the bucket of the client. When the bucket is empty, no token is taken and false is returned
+// along with how long until the next one is available.
+func (rl *RateLimiter) Allow(client string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	clientBucket := rl.refill(client, time.Now())
+
+	if clientBucket.tokens < 1 {
+		return false, time.Duration((1 - clientBucket.tokens) * float64(rl.interval))
+	}
+
+	clientBucket.tokens--
+	return true, 0
+}
+
+// Peek :
+// Reports whether the bucket of the client holds a token, without taking it, along with how long until the next one
+// is available when it does not.
+func (rl *RateLimiter) Peek(client string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	clientBucket := rl.refill(client, time.Now())
+
+	if clientBucket.tokens < 1 {
+		return false, time.Duration((1 - clientBucket.tokens) * float64(rl.interval))
+	}
+
+	return true, 0
+}
+
+// refill :
+// Returns the bucket of the client, created full if it has none, after adding the tokens refilled since it was last
+// used. The caller must hold the lock.
+func (rl *RateLimiter) refill(client string, now time.Time) *bucket {
+	rl.sweep(now)
+
+	clientBucket, ok := rl.buckets[client]
+	if !ok {
+		clientBucket = &bucket{tokens: rl.burst, last: now}
+		rl.buckets[client] = clientBucket
+	}
+
+	clientBucket.tokens = math.Min(rl.burst, clientBucket.tokens+float64(now.Sub(clientBucket.last))/float64(rl.interval))
+	clientBucket.last = now
+
+	return clientBucket
+}
+
+// sweep :
+// Forgets the buckets that had the time to refill completely, at most once every time a full bucket takes to refill.
+func (rl *RateLimiter) sweep(now time.Time) {
+	refill := time.Duration(rl.burst * float64(rl.interval))
+
+	if now.Sub(rl.lastSweep) < refill {
+		return
+	}
+
+	for client, clientBucket := range rl.buckets {
+		if now.Sub(clientBucket.last) >= refill {
+			delete(rl.buckets, client)
+		}
+	}
+
+	rl.lastSweep = now
+}
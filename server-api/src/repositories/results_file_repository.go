@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ResultsFileRepository :
+// Optional sink that appends every crawl result, serialized as JSON, to a local file.
+type ResultsFileRepository struct {
+	path string
+}
+
+func NewResultsFileRepository(path string) *ResultsFileRepository {
+	return &ResultsFileRepository{
+		path: path,
+	}
+}
+
+// Save :
+// Appends the crawl result to the file, creating it if it does not exist yet.
+//
+// Error: will throw JSONSerializationFailed if the result cannot be serialized.
+//
+// Error: will throw FileOpenError or FileWriteError if the file cannot be opened or written.
+func (rf *ResultsFileRepository) Save(result models.CrawlResult) error {
+	// Serialize the result to JSON
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		server_errors.Log(fmt.Sprintf("%s: %v", server_errors.JSONSerializationFailed, err), server_errors.ErrorLevel)
+		return server_errors.ErrJSONSerializationFailed
+	}
+
+	// Open the file in append mode, create it if it doesn't exist, and set write permissions
+	file, err := os.OpenFile(rf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		server_errors.Log(fmt.Sprintf("%s: %v", server_errors.FileOpenError, err), server_errors.ErrorLevel)
+		return server_errors.ErrFileOpenError
+	}
+	defer file.Close()
+
+	// Write the JSON data to the file, followed by a newline for better readability
+	if _, err := file.Write(append(jsonData, '\n')); err != nil {
+		server_errors.Log(fmt.Sprintf("%s: %v", server_errors.FileWriteError, err), server_errors.ErrorLevel)
+		return server_errors.ErrFileWriteError
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"aletheia-server/src/errors"
+	"database/sql"
+	"time"
+)
+
+// dialect :
+// What differs between the databases the SQL repositories run on, so a single implementation of each repository
+// serves them all.
+type dialect interface {
+	// array returns the value stored for a list of strings
+	array(values []string) any
+	// scanArray returns the destination reading a list of strings stored by array
+	scanArray(values *[]string) any
+	// time returns the time as it must be stored to sort and compare correctly
+	time(t time.Time) time.Time
+	// forUpdate returns the clause locking the selected rows until the transaction ends
+	forUpdate() string
+	// isUniqueViolation reports whether err was raised because a unique constraint failed
+	isUniqueViolation(err error) bool
+	// isMissingTable reports whether err was raised because a table does not exist
+	isMissingTable(err error) bool
+}
+
+// rowScanner :
+// Either a *sql.Row or *sql.Rows, so a single function scans a row read either way.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// nullTime :
+// Returns the optional time as the dialect stores it, or nil so it is stored as NULL.
+func nullTime(d dialect, t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+
+	return d.time(*t)
+}
+
+// checkAffectedRows :
+// Returns notFound if the statement did not affect any row, or failed if the affected rows cannot be counted.
+func checkAffectedRows(result sql.Result, notFound *server_errors.Error, failed *server_errors.Error) error {
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		server_errors.Log(err.Error(), server_errors.ErrorLevel)
+		return failed.Wrap(err)
+	}
+
+	if affected == 0 {
+		server_errors.Log(notFound.Message, server_errors.ErrorLevel)
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteDialect :
+// dialect of SQLite, which has no array type and stores the times as text.
+type sqliteDialect struct{}
+
+// array :
+// Returns the list as JSON text. A list of strings always encodes.
+func (sqliteDialect) array(values []string) any {
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
+
+func (sqliteDialect) scanArray(values *[]string) any {
+	return jsonArray{values: values}
+}
+
+// time :
+// Returns the time in UTC. SQLite stores times as text, so they only sort and compare correctly when they share the
+// same offset.
+func (sqliteDialect) time(t time.Time) time.Time {
+	return t.UTC()
+}
+
+// forUpdate :
+// SQLite has no row locks, but the file has a single writer so a transaction is never interleaved with another one.
+func (sqliteDialect) forUpdate() string {
+	return ""
+}
+
+func (sqliteDialect) isUniqueViolation(err error) bool {
+	return isSqliteError(err, sqlite3.SQLITE_CONSTRAINT_UNIQUE)
+}
+
+// isMissingTable :
+// SQLite reports a missing table as a generic error, only told apart by its message.
+func (sqliteDialect) isMissingTable(err error) bool {
+	return isSqliteError(err, sqlite3.SQLITE_ERROR) && strings.Contains(err.Error(), "no such table")
+}
+
+// isSqliteError :
+// Reports whether err was raised by SQLite with the provided extended code.
+func isSqliteError(err error, code int) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
+
+// jsonArray :
+// sql.Scanner reading a list of strings stored as JSON text.
+type jsonArray struct {
+	values *[]string
+}
+
+func (ja jsonArray) Scan(src any) error {
+	switch src := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(src), ja.values)
+	case []byte:
+		return json.Unmarshal(src, ja.values)
+	default:
+		return fmt.Errorf("cannot read a list of strings from %T", src)
+	}
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"database/sql"
+)
+
+// Storage :
+// Every repository keeping the data of the server, all backed by the same database.
+type Storage struct {
+	Languages   LanguageRepository
+	NewsOutlets NewsOutletRepository
+	CrawlRuns   CrawlRunRepository
+	ApiKeys     ApiKeyRepository
+	Quotas      QuotaRepository
+}
+
+// NewPostgresStorage :
+// Returns the repositories storing the data in a PostgreSQL database.
+func NewPostgresStorage(connection *sql.DB) Storage {
+	languages := NewPostgresLanguageRepository(connection)
+
+	return Storage{
+		Languages:   languages,
+		NewsOutlets: NewPostgresNewsOutletRepository(connection, languages),
+		CrawlRuns:   NewPostgresCrawlRunRepository(connection),
+		ApiKeys:     NewPostgresApiKeyRepository(connection),
+		Quotas:      NewPostgresQuotaRepository(connection),
+	}
+}
+
+// NewSqliteStorage :
+// Returns the repositories storing the data in a SQLite file.
+func NewSqliteStorage(connection *sql.DB) Storage {
+	languages := NewSqliteLanguageRepository(connection)
+
+	return Storage{
+		Languages:   languages,
+		NewsOutlets: NewSqliteNewsOutletRepository(connection, languages),
+		CrawlRuns:   NewSqliteCrawlRunRepository(connection),
+		ApiKeys:     NewSqliteApiKeyRepository(connection),
+		Quotas:      NewSqliteQuotaRepository(connection),
+	}
+}
+
+// NewMemoryStorage :
+// Returns the repositories keeping the data in the provided MemoryStore, which is lost when the server stops.
+func NewMemoryStorage(store *MemoryStore) Storage {
+	return Storage{
+		Languages:   NewMemoryLanguageRepository(store),
+		NewsOutlets: NewMemoryNewsOutletRepository(store),
+		CrawlRuns:   NewMemoryCrawlRunRepository(store),
+		ApiKeys:     NewMemoryApiKeyRepository(store),
+		Quotas:      NewMemoryQuotaRepository(store),
+	}
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+)
+
+// WorkerPool :
+// Bounded set of worker slots. Every unit of work holds a slot while it runs, so no more than Size of them run at
+// once, whatever the number of goroutines waiting for their turn.
+type WorkerPool struct {
+	slots chan struct{}
+}
+
+// NewWorkerPool :
+// Creates a new WorkerPool with the provided number of slots, cascading to a single one when it is not positive.
+func NewWorkerPool(size int) *WorkerPool {
+	if size < 1 {
+		size = 1
+	}
+
+	return &WorkerPool{
+		slots: make(chan struct{}, size),
+	}
+}
+
+// Acquire :
+// Blocks until a slot is free and takes it. The wait is aborted once ctx is cancelled, in which case no slot is taken.
+func (wp *WorkerPool) Acquire(ctx context.Context) error {
+	// A cancelled context never takes a slot, even when one is free
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case wp.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release :
+// Frees a slot taken by Acquire.
+func (wp *WorkerPool) Release() {
+	<-wp.slots
+}
+
+// Do :
+// Runs fn while holding a slot.
+//
+// Error: will throw the context error if ctx is cancelled before a slot is free, in which case fn is not run.
+func (wp *WorkerPool) Do(ctx context.Context, fn func()) error {
+	if err := wp.Acquire(ctx); err != nil {
+		return err
+	}
+	defer wp.Release()
+
+	fn()
+	return nil
+}
+
+// Size :
+// Returns the number of slots of the pool.
+func (wp *WorkerPool) Size() int {
+	return cap(wp.slots)
+}
+
+// InUse :
+// Returns the number of slots currently taken.
+func (wp *WorkerPool) InUse() int {
+	return len(wp.slots)
+}
@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	// ApiKeyPrefix : start of every API key, so leaked keys can be spotted by secret scanners
+	ApiKeyPrefix = "aletheia_"
+	// apiKeyBytes : random bytes of an API key, hex encoded after ApiKeyPrefix
+	apiKeyBytes = 24
+	// apiKeyVisibleChars : characters of the random part stored in clear to recognize a key
+	apiKeyVisibleChars = 8
+)
+
+type ApiKeyUsecase struct {
+	apiKeyRepository repositories.ApiKeyRepository
+}
+
+func NewApiKeyUsecase(apiKeyRepository repositories.ApiKeyRepository) ApiKeyUsecase {
+	return ApiKeyUsecase{
+		apiKeyRepository: apiKeyRepository,
+	}
+}
+
+// Create --------------------------------------------------------------------------------------------------------------
+
+// IssueApiKey :
+// Generates a new API key with the provided name and role, and stores its hash. The key itself is only part of the
+// returned value and cannot be read back afterward.
+//
+// Error: will throw EmptyNameError if the name is empty.
+//
+// Error: will throw InvalidApiKeyRole if the role is not one of read, crawl or admin.
+//
+// Error: will throw ApiKeyGenerationError or ApiKeyNotSaved if the key cannot be generated or stored.
+func (au *ApiKeyUsecase) IssueApiKey(ctx context.Context, name string, role string) (*models.IssuedApiKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, server_errors.ErrEmptyNameError
+	}
+
+	if !models.ValidRole(role) {
+		return nil, server_errors.ErrInvalidApiKeyRole.With(role)
+	}
+
+	key, err := NewApiKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := au.apiKeyRepository.AddApiKey(ctx, name, role, key[:len(ApiKeyPrefix)+apiKeyVisibleChars], HashApiKey(key))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.IssuedApiKey{ApiKey: *apiKey, Key: key}, nil
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetApiKeys :
+// Returns every API key issued, revoked ones included, without the keys themselves.
+//
+// Error: will throw ApiKeysQueryError or ApiKeyParsingError if the API keys cannot be read from the database.
+func (au *ApiKeyUsecase) GetApiKeys(ctx context.Context) ([]models.ApiKey, error) {
+	return au.apiKeyRepository.GetApiKeys(ctx)
+}
+
+// Authenticate :
+// Returns the active API key matching the provided key.
+//
+// Error: will throw MissingApiKey if the key is empty.
+//
+// Error: will throw InvalidApiKey if the key is unknown or was revoked.
+func (au *ApiKeyUsecase) Authenticate(ctx context.Context, key string) (*models.ApiKey, error) {
+	if key == "" {
+		return nil, server_errors.ErrMissingApiKey
+	}
+
+	// Keys not shaped like the issued ones are rejected without querying the database
+	if !strings.HasPrefix(key, ApiKeyPrefix) || len(key) != len(ApiKeyPrefix)+2*apiKeyBytes {
+		return nil, server_errors.ErrInvalidApiKey
+	}
+
+	apiKey, err := au.apiKeyRepository.GetApiKeyByHash(ctx, HashApiKey(key))
+
+	if errors.Is(err, server_errors.ErrApiKeyNotFound) {
+		return nil, server_errors.ErrInvalidApiKey
+	}
+
+	return apiKey, err
+}
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// RevokeApiKey :
+// Revokes the API key with the provided id, which is rejected from then on.
+//
+// Error: will throw ApiKeyNotFound if an API key with the provided id is not found.
+//
+// Error: will throw ApiKeyNotRevoked if the database fails to update the row.
+func (au *ApiKeyUsecase) RevokeApiKey(ctx context.Context, id int) error {
+	return au.apiKeyRepository.RevokeApiKey(ctx, id)
+}
+
+// NewApiKey :
+// Generates a random API key made of ApiKeyPrefix followed by 48 hexadecimal characters.
+//
+// Error: will throw ApiKeyGenerationError if the system fails to provide random bytes.
+func NewApiKey() (string, error) {
+	random := make([]byte, apiKeyBytes)
+
+	if _, err := rand.Read(random); err != nil {
+		return "", server_errors.ErrApiKeyGenerationError.Wrap(err)
+	}
+
+	return ApiKeyPrefix + hex.EncodeToString(random), nil
+}
+
+// HashApiKey :
+// Returns the hexadecimal SHA-256 hash the provided key is stored under. The keys being long and random, a fast hash
+// is enough and keeps authenticating every request cheap.
+func HashApiKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
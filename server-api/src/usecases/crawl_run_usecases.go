@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"context"
+)
+
+const (
+	// DefaultCrawlRunsLimit : how many crawl runs are listed when no limit is requested
+	DefaultCrawlRunsLimit = 20
+	// maxCrawlRunsLimit : the most crawl runs that can be listed at once
+	maxCrawlRunsLimit = 100
+)
+
+type CrawlRunUsecase struct {
+	crawlRunRepository repositories.CrawlRunRepository
+}
+
+func NewCrawlRunUsecase(crawlRunRepository repositories.CrawlRunRepository) CrawlRunUsecase {
+	return CrawlRunUsecase{
+		crawlRunRepository: crawlRunRepository,
+	}
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// GetCrawlRuns :
+// Returns a page of the stored crawl runs, newest first. The limit is capped so a single request cannot load the whole
+// history.
+//
+// Error: will throw CrawlRunsQueryError or CrawlRunParsingError if the crawl runs cannot be read from the database.
+func (cu *CrawlRunUsecase) GetCrawlRuns(ctx context.Context, limit int, offset int) ([]models.CrawlRunSummary, error) {
+	if limit <= 0 {
+		limit = DefaultCrawlRunsLimit
+	}
+
+	if limit > maxCrawlRunsLimit {
+		limit = maxCrawlRunsLimit
+	}
+
+	return cu.crawlRunRepository.GetCrawlRuns(ctx, limit, offset)
+}
+
+// GetCrawlRunById :
+// Returns the crawl run with the provided id along with its crawlers, their articles and its verdict, if any.
+//
+// Error: will throw CrawlRunNotFound if a crawl run with the provided id is not found.
+func (cu *CrawlRunUsecase) GetCrawlRunById(ctx context.Context, id int) (*models.CrawlRun, error) {
+	return cu.crawlRunRepository.GetCrawlRunById(ctx, id)
+}
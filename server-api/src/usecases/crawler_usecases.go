@@ -4,20 +4,238 @@ import (
 	"aletheia-server/src/errors"
 	"aletheia-server/src/models"
 	"aletheia-server/src/repositories"
-	"encoding/json"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
-	"os"
+	"log/slog"
+	"runtime/debug"
 	"sync"
+	"time"
 )
 
 type CrawlerUsecase struct {
+	crawlJobRepository *repositories.CrawlJobRepository
+	crawlRunRepository repositories.CrawlRunRepository
+	resultsRepository  *repositories.ResultsFileRepository
+	fetcher            *repositories.Fetcher
+	analyzerRepository *repositories.AnalyzerRepository
+	// maxPagesToVisit : most article pages a crawl may ask to visit on each news outlet
+	maxPagesToVisit int
+	// running : crawl jobs still running in the background, shared by every copy of the usecase
+	running *sync.WaitGroup
 }
 
-func NewCrawlerUsecase() CrawlerUsecase {
-	return CrawlerUsecase{}
+// NewCrawlerUsecase :
+// Creates a new CrawlerUsecase. When crawlRunRepository is not nil, every crawl result is stored in the database; when
+// resultsRepository is not nil, it is also saved through it. Every crawler shares the provided fetcher, so the rate
+// limits of a host hold across crawl jobs, and asks the provided AI analyzer for the links its selector cannot find. A
+// crawl may visit at most maxPagesToVisit article pages on each news outlet.
+func NewCrawlerUsecase(crawlJobRepository *repositories.CrawlJobRepository, crawlRunRepository repositories.CrawlRunRepository, resultsRepository *repositories.ResultsFileRepository, fetcher *repositories.Fetcher, analyzerRepository *repositories.AnalyzerRepository, maxPagesToVisit int) CrawlerUsecase {
+	return CrawlerUsecase{
+		crawlJobRepository: crawlJobRepository,
+		crawlRunRepository: crawlRunRepository,
+		resultsRepository:  resultsRepository,
+		fetcher:            fetcher,
+		analyzerRepository: analyzerRepository,
+		maxPagesToVisit:    maxPagesToVisit,
+		running:            &sync.WaitGroup{},
+	}
+}
+
+// Create --------------------------------------------------------------------------------------------------------------
+
+// StartCrawlJob :
+// Initializes one crawler per news outlet and runs them in the background, returning the crawl job right away. The
+// progress and the result of the job can be collected through GetCrawlJob. The job outlives ctx, only keeping its
+// correlation ids so its logs can be traced back to the request that started it.
+//
+// Error: will throw NoCrawlersInitialized if no crawler could be generated from the news outlets and the query.
+//
+// Error: will throw CrawlJobIdError if it fails to generate an id for the job.
+//
+// Error: will throw CrawlJobsDraining if Drain was called.
+func (cu *CrawlerUsecase) StartCrawlJob(ctx context.Context, newsOutlets []models.NewsOutlet, initializer models.CrawlerInitializer) (models.CrawlJob, error) {
+	crawlersRepositories, err := cu.newCrawlers(ctx, newsOutlets, initializer)
+
+	if err != nil {
+		return models.CrawlJob{}, err
+	}
+
+	id, err := newCrawlJobId()
+
+	if err != nil {
+		return models.CrawlJob{}, server_errors.ErrCrawlJobIdError.Wrap(err)
+	}
+
+	job := models.CrawlJob{
+		Id:           id,
+		Status:       server_errors.CrawlJobQueued,
+		Query:        initializer.Query,
+		PagesToVisit: initializer.PagesToVisit,
+		CreatedAt:    time.Now(),
+		Crawlers:     make([]models.CrawlerProgress, 0, len(crawlersRepositories)),
+	}
+
+	for i := range crawlersRepositories {
+		job.Crawlers = append(job.Crawlers, models.CrawlerProgress{
+			CrawlerId:  crawlersRepositories[i].Crawler.Id,
+			NewsOutlet: crawlersRepositories[i].NewsOutlet,
+			Status:     crawlersRepositories[i].Crawler.Status,
+		})
+		crawlersRepositories[i].StatusListener = func(crawlerId int, status string) {
+			cu.crawlJobRepository.SetCrawlerStatus(id, crawlerId, status)
+		}
+	}
+
+	jobCtx := server_errors.WithLogAttrs(context.WithoutCancel(ctx), slog.String(server_errors.CrawlJobIdKey, id))
+	jobCtx, cancel := context.WithCancel(jobCtx)
+	run := func(ctx context.Context) {
+		defer cu.running.Done()
+		// A bug in a crawl job fails the job, it must not take the whole server down with it
+		defer func() {
+			if r := recover(); r != nil {
+				server_errors.LogContext(ctx, "crawl job panicked", server_errors.ErrorLevel,
+					"panic", r, "stack", string(debug.Stack()))
+				cu.crawlJobRepository.FinishCrawlJob(id, server_errors.CrawlJobFailed, models.CrawlResult{
+					Query:        initializer.Query,
+					PagesToVisit: initializer.PagesToVisit,
+				})
+			}
+		}()
+		server_errors.LogContext(ctx, "crawl job started", server_errors.InfoLevel, "query", initializer.Query)
+		cu.crawlJobRepository.SetCrawlJobStatus(id, server_errors.CrawlJobRunning)
+
+		result := cu.runCrawlers(ctx, crawlersRepositories, initializer)
+
+		status := server_errors.CrawlJobSucceeded
+		if ctx.Err() != nil {
+			status = server_errors.CrawlJobCancelled
+		}
+
+		cu.crawlJobRepository.FinishCrawlJob(id, status, result)
+		server_errors.LogContext(ctx, "crawl job halted", server_errors.InfoLevel, "status", status)
+	}
+
+	// The job is counted as running under the lock of the repository, so it cannot race with Drain
+	err = cu.crawlJobRepository.AddCrawlJob(job, cancel, func() {
+		cu.running.Add(1)
+		go run(jobCtx)
+	})
+
+	if err != nil {
+		cancel()
+		return models.CrawlJob{}, err
+	}
+
+	return job, nil
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// CheckCrawl :
+// Checks that the pages to visit are within bounds and that at least one crawler can be generated from the news outlets
+// and the query, without generating any, so a crawl bound to fail is refused before anything is counted against the
+// client.
+//
+// Error: will throw InvalidParameters if the pages to visit are not between 1 and the configured maximum.
+//
+// Error: will throw NoCrawlersInitialized if no crawler could be generated from the news outlets and the query.
+func (cu *CrawlerUsecase) CheckCrawl(newsOutlets []models.NewsOutlet, initializer models.CrawlerInitializer) error {
+	if initializer.PagesToVisit < 1 || initializer.PagesToVisit > cu.maxPagesToVisit {
+		return server_errors.ErrInvalidParameters.With(
+			fmt.Sprintf("pagesToVisit should be between 1 and %d", cu.maxPagesToVisit),
+		)
+	}
+
+	for _, newsOutlet := range newsOutlets {
+		queryParser := models.QueryParser{
+			NewsOutletName: newsOutlet.Name,
+			QueryParam:     initializer.Query,
+			QueryUrl:       newsOutlet.QueryUrl,
+		}
+
+		if queryParser.Valid() {
+			return nil
+		}
+	}
+
+	return server_errors.ErrNoCrawlersInitialized
+}
+
+// GetCrawlJob :
+// Returns the current state of the crawl job with the provided id.
+//
+// Error: will throw CrawlJobNotFound if there is no crawl job with the provided id.
+func (cu *CrawlerUsecase) GetCrawlJob(id string) (models.CrawlJob, error) {
+	return cu.crawlJobRepository.GetCrawlJob(id)
 }
 
-func (cu *CrawlerUsecase) Crawl(newsOutlets []models.NewsOutlet, pagesToVisit int, query string) {
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// CancelCrawlJob :
+// Cancels the crawl job with the provided id. Its crawlers abort their pending requests and halt as cancelled.
+//
+// Error: will throw CrawlJobNotFound if there is no crawl job with the provided id.
+//
+// Error: will throw CrawlJobAlreadyFinished if the crawl job already halted.
+func (cu *CrawlerUsecase) CancelCrawlJob(id string) (models.CrawlJob, error) {
+	return cu.crawlJobRepository.CancelCrawlJob(id)
+}
+
+// Drain :
+// Rejects every new crawl job, then waits for the ones running in the background to halt, so their results are stored
+// before the server stops.
+// Once ctx is done, the jobs still running are cancelled, which makes their crawlers abort their pending requests, and
+// are waited for as they halt as cancelled.
+//
+// Error: will throw the context error if some crawl jobs had to be cancelled.
+func (cu *CrawlerUsecase) Drain(ctx context.Context) error {
+	// A job started while waiting would race with it
+	cu.crawlJobRepository.StopAccepting()
+
+	drained := make(chan struct{})
+	go func() {
+		cu.running.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+	}
+
+	cancelled := cu.crawlJobRepository.CancelCrawlJobs()
+	server_errors.LogContext(ctx, "cancelling the crawl jobs still running", server_errors.WarningLevel,
+		"crawl_jobs", cancelled)
+	<-drained
+
+	return ctx.Err()
+}
+
+// Crawl ---------------------------------------------------------------------------------------------------------------
+
+// Crawl :
+// Initializes one crawler per news outlet and runs them concurrently, returning the outcome of each of them once all
+// of them halted.
+//
+// Error: will throw NoCrawlersInitialized if no crawler could be generated from the news outlets and the query.
+func (cu *CrawlerUsecase) Crawl(ctx context.Context, newsOutlets []models.NewsOutlet, initializer models.CrawlerInitializer) (models.CrawlResult, error) {
+	crawlersRepositories, err := cu.newCrawlers(ctx, newsOutlets, initializer)
+
+	if err != nil {
+		return models.CrawlResult{}, err
+	}
+
+	return cu.runCrawlers(ctx, crawlersRepositories, initializer), nil
+}
+
+// newCrawlers :
+// Generates one crawler for each news outlet whose query url could be parsed.
+func (cu *CrawlerUsecase) newCrawlers(ctx context.Context, newsOutlets []models.NewsOutlet, initializer models.CrawlerInitializer) ([]repositories.CrawlerRepository, error) {
+	query := initializer.Query
+
 	var crawlersRepositories []repositories.CrawlerRepository
 
 	// Generate the crawlers for each news outlet returned from the database
@@ -29,7 +247,8 @@ func (cu *CrawlerUsecase) Crawl(newsOutlets []models.NewsOutlet, pagesToVisit in
 		}
 		finalQuery := queryParser.Parse()
 
-		server_errors.Log(fmt.Sprintf("Parsed query '%s' into '%s'", query, finalQuery), server_errors.InfoLevel)
+		server_errors.LogContext(ctx, "parsed query", server_errors.DebugLevel,
+			"news_outlet", newsOutlet.Name, "query", query, "url", finalQuery)
 
 		if finalQuery == "" {
 			continue
@@ -37,72 +256,99 @@ func (cu *CrawlerUsecase) Crawl(newsOutlets []models.NewsOutlet, pagesToVisit in
 
 		newCrawler := models.Crawler{
 			Id:           i + 1,
-			PagesToVisit: pagesToVisit,
+			PagesToVisit: initializer.PagesToVisit,
 			Query:        finalQuery,
 			HtmlSelector: newsOutlet.HtmlSelector,
 			Status:       server_errors.CrawlerReady,
 			PagesBodies:  make([]string, 0),
 		}
-		crawlersRepositories = append(crawlersRepositories, repositories.NewCrawlerRepository(newCrawler))
+		crawlerRepository := repositories.NewCrawlerRepository(newCrawler, newsOutlet.Name)
+		crawlerRepository.KeepRawHtml = initializer.KeepRawHtml
+		crawlerRepository.Fetcher = cu.fetcher
+		crawlerRepository.Analyzer = cu.analyzerRepository
+		crawlerRepository.CrawlDelay = time.Duration(newsOutlet.CrawlDelayMs) * time.Millisecond
+		crawlersRepositories = append(crawlersRepositories, crawlerRepository)
 	}
 
 	// Check if at least one crawler was generated
 	if len(crawlersRepositories) == 0 {
-		server_errors.Log(server_errors.NoCrawlersInitialized, server_errors.ErrorLevel)
-		return
+		server_errors.LogContext(ctx, server_errors.NoCrawlersInitialized, server_errors.ErrorLevel)
+		return nil, server_errors.ErrNoCrawlersInitialized
+	}
+
+	return crawlersRepositories, nil
+}
+
+// runCrawlers :
+// Runs the crawlers concurrently and aggregates their outcome once all of them halted. Their requests share the worker
+// pool of the fetcher, so starting many crawlers at once never bursts past its size.
+func (cu *CrawlerUsecase) runCrawlers(ctx context.Context, crawlersRepositories []repositories.CrawlerRepository, initializer models.CrawlerInitializer) models.CrawlResult {
+	result := models.CrawlResult{
+		Query:        initializer.Query,
+		PagesToVisit: initializer.PagesToVisit,
+		StartedAt:    time.Now(),
+		Crawlers:     make([]models.CrawlerResult, 0, len(crawlersRepositories)),
 	}
 
 	// Initialize Crawlers concurrently
 	var wg sync.WaitGroup
-	for _, crawlerRepository := range crawlersRepositories {
+	for i := range crawlersRepositories {
 		wg.Add(1)
-		go func(cr repositories.CrawlerRepository) {
+		go func(cr *repositories.CrawlerRepository) {
 			defer wg.Done()
-			server_errors.Log(
-				fmt.Sprintf("Initializing crawler %d", cr.Crawler.Id),
-				server_errors.InfoLevel,
+			// Every log of the crawler, down to its requests, carries the crawler and its news outlet
+			ctx := server_errors.WithLogAttrs(ctx,
+				slog.Int(server_errors.CrawlerIdKey, cr.Crawler.Id),
+				slog.String("news_outlet", cr.NewsOutlet),
 			)
-			cr.Crawl()
-		}(crawlerRepository)
+			server_errors.LogContext(ctx, "crawler started", server_errors.InfoLevel)
+			cr.Crawl(ctx)
+			server_errors.LogContext(ctx, "crawler halted", server_errors.InfoLevel,
+				"status", cr.Result.Status, "articles", len(cr.Result.Articles), "duration_ms", cr.Result.DurationMs)
+		}(&crawlersRepositories[i])
 	}
 
 	// Wait for all crawlers to finish
 	wg.Wait()
 
 	// Collect results after all crawlers are done
-	haltedCrawlers := make([]models.Crawler, len(crawlersRepositories))
-	for i, cr := range crawlersRepositories {
-		haltedCrawlers[i] = cr.Crawler
+	for _, cr := range crawlersRepositories {
+		result.Crawlers = append(result.Crawlers, cr.Result)
 	}
+	result.FinishedAt = time.Now()
+	result.DurationMs = result.FinishedAt.Sub(result.StartedAt).Milliseconds()
 
-	// Saving the results
-	saveResults(haltedCrawlers)
-}
+	// Storing the results, which should never fail the crawl, even when its client is gone
+	if cu.crawlRunRepository != nil {
+		runId, err := cu.crawlRunRepository.SaveCrawlRun(context.WithoutCancel(ctx), result)
 
-func saveResults(crawlers []models.Crawler) {
-	// Serialize the slice to JSON
-	jsonData, err := json.MarshalIndent(crawlers, "", "  ")
-	if err != nil {
-		server_errors.Log(fmt.Sprintf("%s %s", server_errors.JSONSerializationFailed, err.Error()), server_errors.ErrorLevel)
-		return
+		if err != nil {
+			server_errors.LogContext(ctx, server_errors.CrawlRunNotSaved, server_errors.WarningLevel, "error", err)
+		} else {
+			result.RunId = runId
+		}
 	}
 
-	// Open the file in append mode, create it if it doesn't exist, and set write permissions
-	file, err := os.OpenFile("results", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		server_errors.Log(fmt.Sprintf("%s %s", server_errors.FileOpenError, err.Error()), server_errors.ErrorLevel)
-		return
-	}
-	defer file.Close()
+	// Saving the results, the file sink is optional and should never fail the crawl
+	if cu.resultsRepository != nil {
+		err := cu.resultsRepository.Save(result)
 
-	// Write the JSON data to the file
-	if _, err := file.Write(jsonData); err != nil {
-		server_errors.Log(fmt.Sprintf("%s %s", server_errors.FileWriteError, err.Error()), server_errors.ErrorLevel)
-		return
+		if err != nil {
+			server_errors.LogContext(ctx, server_errors.FileSaveError, server_errors.WarningLevel, "error", err)
+		}
 	}
 
-	// Add a newline after appending data for better readability
-	if _, err := file.WriteString("\n"); err != nil {
-		server_errors.Log(fmt.Sprintf("%s %s", server_errors.FileWriteError, err.Error()), server_errors.ErrorLevel)
+	return result
+}
+
+// newCrawlJobId :
+// Generates a random identifier for a crawl job.
+func newCrawlJobId() (string, error) {
+	id := make([]byte, 16)
+
+	if _, err := rand.Read(id); err != nil {
+		return "", err
 	}
+
+	return hex.EncodeToString(id), nil
 }
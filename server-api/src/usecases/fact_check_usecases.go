@@ -0,0 +1,260 @@
+package usecases
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"context"
+	"strings"
+	"time"
+)
+
+const (
+	// factCheckPagesToVisit : how many articles each news outlet contributes to a fact check
+	factCheckPagesToVisit = 3
+	// factCheckQueryWords : how many words of the post are used as the crawl query when the post has no title
+	factCheckQueryWords = 12
+	// verdictThreshold : how far from zero the weighted score must be to reach a conclusive verdict
+	verdictThreshold = 0.5
+)
+
+type FactCheckUsecase struct {
+	crawlerUsecase     CrawlerUsecase
+	postRepository     repositories.PostRepository
+	analyzerRepository repositories.AnalyzerRepository
+	crawlRunRepository repositories.CrawlRunRepository
+}
+
+// NewFactCheckUsecase :
+// Creates a new FactCheckUsecase. When crawlRunRepository is not nil, every verdict is stored in the database along
+// with the crawl run it is based on.
+func NewFactCheckUsecase(crawlerUsecase CrawlerUsecase, postRepository repositories.PostRepository, analyzerRepository repositories.AnalyzerRepository, crawlRunRepository repositories.CrawlRunRepository) FactCheckUsecase {
+	return FactCheckUsecase{
+		crawlerUsecase:     crawlerUsecase,
+		postRepository:     postRepository,
+		analyzerRepository: analyzerRepository,
+		crawlRunRepository: crawlRunRepository,
+	}
+}
+
+// CheckFactCheck :
+// Checks that the package carries the url of a post and that at least one crawler can be generated from the news
+// outlets, so a fact check bound to fail is refused before anything is counted against the client. The query being
+// taken from the post, which is not fetched yet, the news outlets are checked against a stand-in one.
+//
+// Error: will throw FactCheckEmptyUrl if the package does not contain the url of the post.
+//
+// Error: will throw NoCrawlersInitialized if no crawler could be generated from the news outlets.
+func (fc *FactCheckUsecase) CheckFactCheck(pkg models.PackageReceived, newsOutlets []models.NewsOutlet) error {
+	if strings.TrimSpace(pkg.Url) == "" {
+		return server_errors.ErrFactCheckEmptyUrl
+	}
+
+	return fc.crawlerUsecase.CheckCrawl(newsOutlets, models.CrawlerInitializer{
+		PagesToVisit: fc.pagesToVisit(),
+		Query:        pkg.Url,
+	})
+}
+
+// pagesToVisit :
+// Returns how many articles each news outlet contributes to a fact check, within the maximum allowed to any crawl.
+func (fc *FactCheckUsecase) pagesToVisit() int {
+	return min(factCheckPagesToVisit, fc.crawlerUsecase.maxPagesToVisit)
+}
+
+// FactCheck :
+// Fetches the post submitted by the user, crawls the news outlets looking for articles about it, asks the AI analyzer
+// to compare each article against the post and aggregates the analyses into a verdict weighted by the credibility of
+// each news outlet.
+//
+// Error: will throw FactCheckEmptyUrl if the package does not contain the url of the post.
+//
+// Error: will throw FactCheckPostFetchError or FactCheckEmptyPost if the post cannot be collected.
+//
+// Error: will throw NoCrawlersInitialized if no crawler could be generated from the news outlets.
+func (fc *FactCheckUsecase) FactCheck(ctx context.Context, pkg models.PackageReceived, newsOutlets []models.NewsOutlet) (models.FactCheckResult, error) {
+	result := models.FactCheckResult{
+		Prompt:    pkg.Prompt,
+		StartedAt: time.Now(),
+		Analyses:  make([]models.ArticleAnalysis, 0),
+	}
+
+	pkg.Url = strings.TrimSpace(pkg.Url)
+	if pkg.Url == "" {
+		return models.FactCheckResult{}, server_errors.ErrFactCheckEmptyUrl
+	}
+
+	if pkg.Image || pkg.Video {
+		server_errors.LogContext(ctx, server_errors.FactCheckMediaUnsupported, server_errors.WarningLevel)
+	}
+
+	// Collecting the post
+	post, err := fc.postRepository.GetPost(ctx, pkg.Url)
+
+	if err != nil {
+		return models.FactCheckResult{}, err
+	}
+
+	result.Post = post
+	result.Query = factCheckQuery(post)
+
+	// Crawling the news outlets
+	crawl, err := fc.crawlerUsecase.Crawl(ctx, newsOutlets, models.CrawlerInitializer{
+		PagesToVisit: fc.pagesToVisit(),
+		Query:        result.Query,
+	})
+
+	if err != nil {
+		return models.FactCheckResult{}, err
+	}
+
+	result.Crawl = crawl
+
+	// Analyzing every collected article
+	credibilities := make(map[string]int, len(newsOutlets))
+	for _, newsOutlet := range newsOutlets {
+		credibilities[newsOutlet.Name] = newsOutlet.Credibility
+	}
+
+	for _, crawler := range crawl.Crawlers {
+		for _, article := range crawler.Articles {
+			if ctx.Err() != nil {
+				return models.FactCheckResult{}, ctx.Err()
+			}
+
+			result.Analyses = append(
+				result.Analyses,
+				fc.analyzeArticle(ctx, post, pkg.Prompt, crawler.NewsOutlet, credibilities[crawler.NewsOutlet], article),
+			)
+		}
+	}
+
+	result.Score, result.Verdict = weightVerdict(result.Analyses)
+	result.FinishedAt = time.Now()
+	result.DurationMs = result.FinishedAt.Sub(result.StartedAt).Milliseconds()
+
+	// Storing the verdict next to its crawl run, which should never fail the fact check
+	if fc.crawlRunRepository != nil && crawl.RunId != 0 {
+		if err = fc.crawlRunRepository.SaveVerdict(context.WithoutCancel(ctx), crawl.RunId, result); err != nil {
+			server_errors.LogContext(ctx, server_errors.VerdictNotSaved, server_errors.WarningLevel, "error", err)
+		}
+	}
+
+	return result, nil
+}
+
+// analyzeArticle :
+// Sends a single article to the AI analyzer. Failures are kept inside the analysis instead of failing the fact check.
+func (fc *FactCheckUsecase) analyzeArticle(ctx context.Context, post models.Post, prompt string, newsOutlet string, credibility int, article models.Article) models.ArticleAnalysis {
+	analysis := models.ArticleAnalysis{
+		NewsOutlet:  newsOutlet,
+		Credibility: credibility,
+		Url:         article.Url,
+		Title:       article.Title,
+		Stance:      models.StanceInsufficient,
+	}
+
+	if article.Text == "" {
+		analysis.Error = server_errors.ArticleEmptyText
+		return analysis
+	}
+
+	text, err := fc.analyzerRepository.Analyze(ctx, post.Content, article.Text, prompt)
+
+	if err != nil {
+		server_errors.LogContext(ctx, "failed to analyze article", server_errors.ErrorLevel,
+			"news_outlet", newsOutlet, "url", article.Url, "error", err)
+		analysis.Error = err.Error()
+		return analysis
+	}
+
+	analysis.Analysis = text
+	analysis.Stance = classifyAnalysis(text)
+
+	return analysis
+}
+
+// factCheckQuery :
+// Builds the query used to crawl the news outlets, which is the title of the post or, when it has none, its first
+// words.
+func factCheckQuery(post models.Post) string {
+	if post.Title != "" {
+		return post.Title
+	}
+
+	words := strings.Fields(post.Content)
+	if len(words) > factCheckQueryWords {
+		words = words[:factCheckQueryWords]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// classifyAnalysis :
+// Infers the stance of an article from the free text analysis returned by the AI analyzer.
+func classifyAnalysis(analysis string) string {
+	analysis = strings.ToLower(analysis)
+
+	switch {
+	case strings.Contains(analysis, "insufficient"),
+		strings.Contains(analysis, "no relevant information"),
+		strings.Contains(analysis, "not relevant"):
+		return models.StanceInsufficient
+	case strings.Contains(analysis, "partially"), strings.Contains(analysis, "partial match"):
+		return models.StancePartial
+	case strings.Contains(analysis, "contradict"),
+		strings.Contains(analysis, "not align"),
+		strings.Contains(analysis, "not support"):
+		return models.StanceContradicts
+	case strings.Contains(analysis, "align"),
+		strings.Contains(analysis, "consistent with"),
+		strings.Contains(analysis, "supports"),
+		strings.Contains(analysis, "confirm"):
+		return models.StanceSupports
+	default:
+		return models.StanceInsufficient
+	}
+}
+
+// weightVerdict :
+// Averages the stances of the analyses weighted by the credibility of their news outlets. Analyses without a
+// conclusive stance, or from outlets without credibility, do not count towards the score.
+func weightVerdict(analyses []models.ArticleAnalysis) (float64, string) {
+	var weightedSum, totalWeight float64
+
+	for _, analysis := range analyses {
+		if analysis.Credibility <= 0 {
+			continue
+		}
+
+		var value float64
+		switch analysis.Stance {
+		case models.StanceSupports:
+			value = 1
+		case models.StanceContradicts:
+			value = -1
+		case models.StancePartial:
+			value = 0
+		default:
+			continue
+		}
+
+		weightedSum += value * float64(analysis.Credibility)
+		totalWeight += float64(analysis.Credibility)
+	}
+
+	if totalWeight == 0 {
+		return 0, models.VerdictUnverified
+	}
+
+	score := weightedSum / totalWeight
+
+	switch {
+	case score >= verdictThreshold:
+		return score, models.VerdictLikelyTrue
+	case score <= -verdictThreshold:
+		return score, models.VerdictLikelyFalse
+	default:
+		return score, models.VerdictMixed
+	}
+}
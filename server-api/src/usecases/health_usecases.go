@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultHealthCheckTimeout : how long a dependency may take to answer a readiness check
+const DefaultHealthCheckTimeout = 2 * time.Second
+
+// HealthCheck :
+// A dependency the server cannot serve requests without. Check returns an error when it is down.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type HealthUsecase struct {
+	timeout time.Duration
+	checks  []HealthCheck
+}
+
+// NewHealthUsecase :
+// Creates a new HealthUsecase checking the provided dependencies, each of them bounded by timeout. A non positive
+// timeout cascades to DefaultHealthCheckTimeout.
+func NewHealthUsecase(timeout time.Duration, checks ...HealthCheck) HealthUsecase {
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+
+	return HealthUsecase{
+		timeout: timeout,
+		checks:  checks,
+	}
+}
+
+// Read ----------------------------------------------------------------------------------------------------------------
+
+// Liveness :
+// Reports that the server is running. It checks no dependency, so a broken dependency never gets the server restarted.
+func (hu *HealthUsecase) Liveness() models.Health {
+	return models.Health{
+		Status:    server_errors.HealthUp,
+		CheckedAt: time.Now(),
+	}
+}
+
+// Readiness :
+// Checks every dependency concurrently and reports the status and latency of each of them. The server is up only when
+// all of them are.
+func (hu *HealthUsecase) Readiness(ctx context.Context) models.Health {
+	health := models.Health{
+		Status:       server_errors.HealthUp,
+		CheckedAt:    time.Now(),
+		Dependencies: make([]models.DependencyHealth, len(hu.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range hu.checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			// Each goroutine writes its own slot only
+			health.Dependencies[i] = hu.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, dependency := range health.Dependencies {
+		if dependency.Status != server_errors.HealthUp {
+			health.Status = server_errors.HealthDown
+		}
+	}
+
+	return health
+}
+
+// run :
+// Runs a single check, bounded by the timeout of the usecase.
+func (hu *HealthUsecase) run(ctx context.Context, check HealthCheck) models.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, hu.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+
+	dependency := models.DependencyHealth{
+		Name:      check.Name,
+		Status:    server_errors.HealthUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		dependency.Status = server_errors.HealthDown
+		dependency.Error = err.Error()
+		server_errors.LogContext(ctx, "dependency is down", server_errors.WarningLevel,
+			"dependency", check.Name, "error", err)
+	}
+
+	return dependency
+}
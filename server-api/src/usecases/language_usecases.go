@@ -1,15 +1,18 @@
 package usecases
 
 import (
+	"aletheia-server/src/errors"
 	"aletheia-server/src/models"
 	"aletheia-server/src/repositories"
+	"context"
+	"strings"
 )
 
 type LanguageUseCase struct {
-	languageRepository *repositories.LanguageRepository
+	languageRepository repositories.LanguageRepository
 }
 
-func NewLanguageUsecase(repo *repositories.LanguageRepository) LanguageUseCase {
+func NewLanguageUsecase(repo repositories.LanguageRepository) LanguageUseCase {
 	return LanguageUseCase{
 		languageRepository: repo,
 	}
@@ -26,8 +29,8 @@ func NewLanguageUsecase(repo *repositories.LanguageRepository) LanguageUseCase {
 // database.
 //
 // Error: will throw LanguageClosingTableError if it fails to close the database rows.
-func (lu *LanguageUseCase) AddLanguage(language models.Language) (models.Language, error) {
-	id, err := lu.languageRepository.AddLanguage(language)
+func (lu *LanguageUseCase) AddLanguage(ctx context.Context, language models.Language) (models.Language, error) {
+	id, err := lu.languageRepository.AddLanguage(ctx, language)
 
 	if err != nil && id < 0 {
 		return models.Language{}, err
@@ -48,8 +51,8 @@ func (lu *LanguageUseCase) AddLanguage(language models.Language) (models.Languag
 // database.
 //
 // Error: will throw LanguageClosingTableError if it fails to close the database rows.
-func (lu *LanguageUseCase) GetLanguages() ([]models.Language, error) {
-	return lu.languageRepository.GetLanguages()
+func (lu *LanguageUseCase) GetLanguages(ctx context.Context) ([]models.Language, error) {
+	return lu.languageRepository.GetLanguages(ctx)
 }
 
 // GetLanguageById :
@@ -57,8 +60,8 @@ func (lu *LanguageUseCase) GetLanguages() ([]models.Language, error) {
 // moment.
 //
 // Error: will throw LanguageNotFound if a language with the provided id is not found.
-func (lu *LanguageUseCase) GetLanguageById(id int) (*models.Language, error) {
-	language, err := lu.languageRepository.GetLanguageById(id)
+func (lu *LanguageUseCase) GetLanguageById(ctx context.Context, id int) (*models.Language, error) {
+	language, err := lu.languageRepository.GetLanguageById(ctx, id)
 
 	if err != nil {
 		return nil, err
@@ -72,8 +75,8 @@ func (lu *LanguageUseCase) GetLanguageById(id int) (*models.Language, error) {
 // moment.
 //
 // Error: will throw LanguageNotFound if a language with the provided name is not found.
-func (lu *LanguageUseCase) GetLanguageByName(name string) (*models.Language, error) {
-	language, err := lu.languageRepository.GetLanguageByName(name)
+func (lu *LanguageUseCase) GetLanguageByName(ctx context.Context, name string) (*models.Language, error) {
+	language, err := lu.languageRepository.GetLanguageByName(ctx, name)
 
 	if err != nil {
 		return nil, err
@@ -81,3 +84,39 @@ func (lu *LanguageUseCase) GetLanguageByName(name string) (*models.Language, err
 
 	return language, nil
 }
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// RenameLanguage :
+// Changes the name of the language with the provided id and returns the renamed language.
+//
+// Error: will throw LanguageEmptyName if the new name is empty.
+//
+// Error: will throw LanguageNotFound if a language with the provided id is not found.
+//
+// Error: will throw LanguageAlreadyExists if another language already uses the provided name.
+func (lu *LanguageUseCase) RenameLanguage(ctx context.Context, id int, language models.Language) (*models.Language, error) {
+	if strings.TrimSpace(language.Name) == "" {
+		return nil, server_errors.ErrLanguageEmptyName
+	}
+
+	err := lu.languageRepository.RenameLanguage(ctx, id, strings.TrimSpace(language.Name))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lu.languageRepository.GetLanguageById(ctx, id)
+}
+
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// DeleteLanguage :
+// Removes the language with the provided id. Its news outlets are removed as well only when cascade is set.
+//
+// Error: will throw LanguageNotFound if a language with the provided id is not found.
+//
+// Error: will throw LanguageInUse if news outlets still use the language and cascade is not set.
+func (lu *LanguageUseCase) DeleteLanguage(ctx context.Context, id int, cascade bool) error {
+	return lu.languageRepository.DeleteLanguage(ctx, id, cascade)
+}
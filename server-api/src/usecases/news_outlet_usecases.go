@@ -1,8 +1,11 @@
 package usecases
 
 import (
+	"aletheia-server/src/errors"
 	"aletheia-server/src/models"
 	"aletheia-server/src/repositories"
+	"context"
+	"strings"
 )
 
 type NewsOutletUseCase struct {
@@ -26,8 +29,14 @@ func NewNewsOutletUsecase(repo repositories.NewsOutletRepository) NewsOutletUseC
 // database.
 //
 // Error: will throw NewsOutletClosingTableError if it fails to close the database rows.
-func (no *NewsOutletUseCase) AddNewsOutlet(newsOutlet models.NewsOutlet) (models.NewsOutlet, error) {
-	id, err := no.newsOutletRepository.AddNewsOutlet(newsOutlet)
+//
+// Error: will throw NewsOutletEmptyName or NewsOutletMissingQueryHere if the news outlet is invalid.
+func (no *NewsOutletUseCase) AddNewsOutlet(ctx context.Context, newsOutlet models.NewsOutlet) (models.NewsOutlet, error) {
+	if err := validateNewsOutlet(newsOutlet); err != nil {
+		return models.NewsOutlet{}, err
+	}
+
+	id, err := no.newsOutletRepository.AddNewsOutlet(ctx, newsOutlet)
 
 	if err != nil && id < 0 {
 		return models.NewsOutlet{}, err
@@ -48,8 +57,8 @@ func (no *NewsOutletUseCase) AddNewsOutlet(newsOutlet models.NewsOutlet) (models
 // database.
 //
 // Error: will throw NewsOutletClosingTableError if it fails to close the database rows.
-func (no *NewsOutletUseCase) GetNewsOutlets() ([]models.NewsOutlet, error) {
-	return no.newsOutletRepository.GetNewsOutlets()
+func (no *NewsOutletUseCase) GetNewsOutlets(ctx context.Context) ([]models.NewsOutlet, error) {
+	return no.newsOutletRepository.GetNewsOutlets(ctx)
 }
 
 // GetNewsOutletByName :
@@ -57,8 +66,8 @@ func (no *NewsOutletUseCase) GetNewsOutlets() ([]models.NewsOutlet, error) {
 // moment.
 //
 // Error: will throw NewsOutletNotFound if a news outlet with the provided name is not found.
-func (no *NewsOutletUseCase) GetNewsOutletByName(name string) (*models.NewsOutlet, error) {
-	language, err := no.newsOutletRepository.GetNewsOutletByName(name)
+func (no *NewsOutletUseCase) GetNewsOutletByName(ctx context.Context, name string) (*models.NewsOutlet, error) {
+	language, err := no.newsOutletRepository.GetNewsOutletByName(ctx, name)
 
 	if err != nil {
 		return nil, err
@@ -72,8 +81,8 @@ func (no *NewsOutletUseCase) GetNewsOutletByName(name string) (*models.NewsOutle
 // moment.
 //
 // Error: will throw NewsOutletNotFound if a news outlet with the provided name is not found.
-func (no *NewsOutletUseCase) GetNewsOutletById(id int) (*models.NewsOutlet, error) {
-	language, err := no.newsOutletRepository.GetNewsOutletById(id)
+func (no *NewsOutletUseCase) GetNewsOutletById(ctx context.Context, id int) (*models.NewsOutlet, error) {
+	language, err := no.newsOutletRepository.GetNewsOutletById(ctx, id)
 
 	if err != nil {
 		return nil, err
@@ -81,3 +90,78 @@ func (no *NewsOutletUseCase) GetNewsOutletById(id int) (*models.NewsOutlet, erro
 
 	return language, nil
 }
+
+// Update --------------------------------------------------------------------------------------------------------------
+
+// UpdateNewsOutlet :
+// Replaces every field of the news outlet with the provided id, returning it as stored in the database.
+//
+// Error: will throw NewsOutletEmptyName or NewsOutletMissingQueryHere if the news outlet is invalid.
+//
+// Error: will throw NewsOutletUnknownLanguage if the provided language is not maintained inside the database.
+//
+// Error: will throw NewsOutletNotFound if a news outlet with the provided id is not found.
+//
+// Error: will throw NewsOutletAlreadyExists if another news outlet already uses the provided name.
+func (no *NewsOutletUseCase) UpdateNewsOutlet(ctx context.Context, id int, newsOutlet models.NewsOutlet) (*models.NewsOutlet, error) {
+	if err := validateNewsOutlet(newsOutlet); err != nil {
+		return nil, err
+	}
+
+	err := no.newsOutletRepository.UpdateNewsOutlet(ctx, id, newsOutlet)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return no.newsOutletRepository.GetNewsOutletById(ctx, id)
+}
+
+// PatchNewsOutlet :
+// Replaces only the fields present in the patch of the news outlet with the provided id, returning it as stored in
+// the database.
+//
+// Error: will throw NewsOutletEmptyPatch if the patch does not change any field.
+//
+// Error: will throw the same errors as UpdateNewsOutlet.
+func (no *NewsOutletUseCase) PatchNewsOutlet(ctx context.Context, id int, patch models.NewsOutletPatch) (*models.NewsOutlet, error) {
+	if patch.IsEmpty() {
+		return nil, server_errors.ErrNewsOutletEmptyPatch
+	}
+
+	current, err := no.newsOutletRepository.GetNewsOutletById(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return no.UpdateNewsOutlet(ctx, id, patch.Apply(*current))
+}
+
+// Delete --------------------------------------------------------------------------------------------------------------
+
+// DeleteNewsOutlet :
+// Removes the news outlet with the provided id from the database.
+//
+// Error: will throw NewsOutletNotFound if a news outlet with the provided id is not found.
+func (no *NewsOutletUseCase) DeleteNewsOutlet(ctx context.Context, id int) error {
+	return no.newsOutletRepository.DeleteNewsOutlet(ctx, id)
+}
+
+// validateNewsOutlet :
+// Checks the fields of a news outlet that cannot be validated by the database.
+func validateNewsOutlet(newsOutlet models.NewsOutlet) error {
+	if strings.TrimSpace(newsOutlet.Name) == "" {
+		return server_errors.ErrNewsOutletEmptyName
+	}
+
+	if !strings.Contains(newsOutlet.QueryUrl, models.QueryPlaceholder) {
+		return server_errors.ErrNewsOutletMissingQueryHere
+	}
+
+	if newsOutlet.CrawlDelayMs < 0 {
+		return server_errors.ErrNewsOutletNegativeCrawlDelay
+	}
+
+	return nil
+}
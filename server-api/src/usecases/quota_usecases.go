@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"context"
+	"time"
+)
+
+type QuotaUsecase struct {
+	quotaRepository repositories.QuotaRepository
+	dailyLimit      int
+}
+
+// NewQuotaUsecase :
+// Creates a new QuotaUsecase allowing each client dailyLimit crawls a day, or any number of them when it is 0.
+func NewQuotaUsecase(quotaRepository repositories.QuotaRepository, dailyLimit int) QuotaUsecase {
+	return QuotaUsecase{
+		quotaRepository: quotaRepository,
+		dailyLimit:      dailyLimit,
+	}
+}
+
+// ConsumeCrawlQuota :
+// Counts a crawl started by the client against its quota of the current day, in UTC, and returns what is left of it.
+//
+// Error: will throw CrawlQuotaExceeded, along with the quota, if the client already started every crawl of the day.
+//
+// Error: will throw CrawlQuotaError if the quota cannot be updated in the database.
+func (qu *QuotaUsecase) ConsumeCrawlQuota(ctx context.Context, client string) (models.Quota, error) {
+	now := time.Now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	quota := models.Quota{Limit: qu.dailyLimit, ResetsAt: day.AddDate(0, 0, 1)}
+
+	if qu.dailyLimit <= 0 {
+		return quota, nil
+	}
+
+	used, ok, err := qu.quotaRepository.ConsumeQuota(ctx, client, day, qu.dailyLimit)
+
+	if err != nil {
+		return quota, err
+	}
+
+	quota.Used = used
+	quota.Remaining = max(qu.dailyLimit-used, 0)
+
+	if !ok {
+		return quota, server_errors.ErrCrawlQuotaExceeded
+	}
+
+	return quota, nil
+}
+
+// RefundCrawlQuota :
+// Gives back to the client the crawl counted against the quota received from ConsumeCrawlQuota, when the crawl could
+// not be started after all, and returns what is left of that quota.
+//
+// Error: will throw CrawlQuotaError if the quota cannot be updated in the database.
+func (qu *QuotaUsecase) RefundCrawlQuota(ctx context.Context, client string, quota models.Quota) (models.Quota, error) {
+	if qu.dailyLimit <= 0 {
+		return quota, nil
+	}
+
+	// The day the crawl was counted on, even if it ended since
+	used, err := qu.quotaRepository.RefundQuota(ctx, client, quota.ResetsAt.AddDate(0, 0, -1))
+
+	if err != nil {
+		return quota, err
+	}
+
+	quota.Used = used
+	quota.Remaining = max(qu.dailyLimit-used, 0)
+
+	return quota, nil
+}
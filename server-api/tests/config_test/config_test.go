@@ -0,0 +1,292 @@
+package config_test
+
+import (
+	"aletheia-server/src/config"
+	"aletheia-server/src/errors"
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPassword : password provided to every test, as PostgreSQL, the default driver, requires one
+const testPassword = "secret"
+
+// clearEnv :
+// Unsets every environment variable read by the configuration for the duration of the test, but DB_PASSWORD which is
+// set to testPassword.
+func clearEnv(t *testing.T) {
+	t.Helper()
+
+	for _, key := range []string{
+		"CONFIG_FILE", "DEBUG", "SERVER_ADDRESS", "SHUTDOWN_TIMEOUT", "HEALTH_CHECK_TIMEOUT",
+		"DB_DRIVER", "DB_PATH", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS",
+		"DB_CONN_MAX_LIFETIME", "DB_CONN_MAX_IDLE_TIME", "DB_CONNECT_TIMEOUT", "AI_ANALYZER_URL", "AI_ANALYZER_TIMEOUT",
+		"CRAWLER_USER_AGENT", "CRAWLER_REQUESTS_PER_SECOND", "CRAWLER_BURST", "CRAWLER_WORKERS",
+		"CRAWLER_OUTLET_CONCURRENCY", "CRAWLER_MAX_PAGES_TO_VISIT", "CRAWLER_RESULTS_FILE", "HTTP_TIMEOUT", "HTTP_MAX_RETRIES", "HTTP_MAX_BODY_BYTES",
+		"LOG_LEVEL", "LOG_FORMAT", "AUTH_ENABLED", "RATE_LIMIT_ENABLED", "RATE_LIMIT_READ_PER_MINUTE",
+		"RATE_LIMIT_READ_BURST", "RATE_LIMIT_CRAWL_PER_MINUTE", "RATE_LIMIT_CRAWL_BURST", "RATE_LIMIT_ADMIN_PER_MINUTE",
+		"RATE_LIMIT_ADMIN_BURST", "DAILY_CRAWL_QUOTA",
+	} {
+		// Setenv restores the variable once the test is done
+		t.Setenv(key, "")
+		if err := os.Unsetenv(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("DB_PASSWORD", testPassword)
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, args, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := config.Default()
+	expected.Database.Password = testPassword
+	if cfg != expected || len(args) != 0 {
+		t.Errorf("Expected the defaults, got %+v %v", cfg, args)
+	}
+
+	if password := config.Default().Database.Password; password != "" {
+		t.Errorf("Expected no default password, got %q", password)
+	}
+}
+
+func TestLoad_MissingPassword(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DB_PASSWORD", "")
+
+	_, _, err := config.Load(nil)
+	if !errors.Is(err, server_errors.ErrConfigInvalid) || !strings.Contains(err.Error(), "database.password") {
+		t.Errorf("Expected the missing password to be reported, got %v", err)
+	}
+
+	// SQLite does not need any password
+	t.Setenv("DB_DRIVER", "sqlite")
+	if _, _, err = config.Load(nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestLoad_EmptyEnvironmentClearsFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("CONFIG_FILE", writeFile(t, "crawler:\n  results_file: results.jsonl\n"))
+	t.Setenv("CRAWLER_RESULTS_FILE", "")
+
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Crawler.ResultsFile != "" {
+		t.Errorf("Expected the empty variable to clear the file setting, got %q", cfg.Crawler.ResultsFile)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, `
+server:
+  address: ":9000"
+database:
+  host: file-host
+  port: 6543
+crawler:
+  workers: 3
+http:
+  timeout: 5s
+`)
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("CRAWLER_WORKERS", "4")
+	t.Setenv("AUTH_ENABLED", "false")
+
+	cfg, args, err := config.Load([]string{"-crawler.workers", "5", "migrate", "status"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Server.Address != ":9000" || cfg.Database.Port != 6543 || cfg.Http.Timeout != 5*time.Second {
+		t.Errorf("Expected the file to override the defaults, got %+v", cfg)
+	}
+
+	if cfg.Database.Host != "env-host" {
+		t.Errorf("Expected the environment to override the file, got %q", cfg.Database.Host)
+	}
+
+	if cfg.Auth.Enabled {
+		t.Errorf("Expected AUTH_ENABLED=false to disable the authentication")
+	}
+
+	if cfg.Crawler.Workers != 5 {
+		t.Errorf("Expected the flags to override the environment, got %d", cfg.Crawler.Workers)
+	}
+
+	if cfg.Crawler.OutletConcurrency != config.Default().Crawler.OutletConcurrency {
+		t.Errorf("Expected the settings missing everywhere to keep their default, got %+v", cfg.Crawler)
+	}
+
+	if strings.Join(args, " ") != "migrate status" {
+		t.Errorf("Expected the subcommand to be returned, got %v", args)
+	}
+}
+
+func TestLoad_Debug(t *testing.T) {
+	tests := []struct {
+		name     string
+		debug    string
+		logLevel string
+		file     string
+		expected string
+	}{
+		{"Debug", "true", "", "", server_errors.DebugLevel},
+		{"LogLevelWins", "true", "warning", "", server_errors.WarningLevel},
+		{"FileLogLevelWins", "true", "", "log:\n  level: warning\n", server_errors.WarningLevel},
+		{"FileWithoutLogLevel", "true", "", "log:\n  format: json\n", server_errors.DebugLevel},
+		{"NotDebug", "false", "", "", server_errors.InfoLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("DEBUG", tt.debug)
+			if tt.logLevel != "" {
+				t.Setenv("LOG_LEVEL", tt.logLevel)
+			}
+			if tt.file != "" {
+				t.Setenv("CONFIG_FILE", writeFile(t, tt.file))
+			}
+
+			cfg, _, err := config.Load(nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if cfg.Log.Level != tt.expected {
+				t.Errorf("Expected log level %q, got %q", tt.expected, cfg.Log.Level)
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DB_PORT", "not a port")
+	t.Setenv("HTTP_TIMEOUT", "15")
+
+	_, _, err := config.Load([]string{"-analyzer.url", "localhost:7654", "-crawler.workers", "0", "-log.format", "xml"})
+	if !errors.Is(err, server_errors.ErrConfigInvalid) {
+		t.Fatalf("Expected ConfigInvalid, got %v", err)
+	}
+
+	// Every problem is reported at once
+	for _, want := range []string{"DB_PORT", "HTTP_TIMEOUT", "analyzer.url", "crawler.workers", "log.format"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q to be reported, got %v", want, err)
+		}
+	}
+}
+
+func TestLoad_SqliteDriver(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DB_DRIVER", "sqlite")
+
+	// The PostgreSQL settings are not needed to store everything in a file
+	cfg, _, err := config.Load([]string{"-database.path", "/var/lib/aletheia/aletheia.db", "-database.host", ""})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if settings := cfg.DBConfig(); settings.Driver != "sqlite" || settings.Path != "/var/lib/aletheia/aletheia.db" {
+		t.Errorf("Expected the sqlite driver and its file, got %+v", settings)
+	}
+
+	_, _, err = config.Load([]string{"-database.path", ""})
+	if err == nil || !strings.Contains(err.Error(), "database.path") {
+		t.Errorf("Expected the missing file to be reported, got %v", err)
+	}
+
+	t.Setenv("DB_DRIVER", "mysql")
+	_, _, err = config.Load(nil)
+	if err == nil || !strings.Contains(err.Error(), "database.driver") {
+		t.Errorf("Expected the unknown driver to be reported, got %v", err)
+	}
+}
+
+func TestLoad_FileErrors(t *testing.T) {
+	clearEnv(t)
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"Missing", filepath.Join(t.TempDir(), "missing.yaml")},
+		{"UnknownSetting", writeFile(t, "database:\n  hots: typo\n")},
+		{"Malformed", writeFile(t, "database: [\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := config.Load([]string{"-config", tt.path}); !errors.Is(err, server_errors.ErrConfigFileError) {
+				t.Errorf("Expected ConfigFileError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_Help(t *testing.T) {
+	clearEnv(t)
+
+	if _, _, err := config.Load([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Expected flag.ErrHelp, got %v", err)
+	}
+}
+
+func TestPrint_RedactsSecrets(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database.Password = "hunter2"
+
+	var buf bytes.Buffer
+	if err := cfg.Print(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "hunter2") || !strings.Contains(output, "password: REDACTED") {
+		t.Errorf("Expected the password to be redacted, got:\n%s", output)
+	}
+
+	if !strings.Contains(output, "shutdown_timeout: 30s") || !strings.Contains(output, "address: :8000") {
+		t.Errorf("Expected the settings to be printed in the format of the file, got:\n%s", output)
+	}
+
+	if cfg.Database.Password != "hunter2" {
+		t.Errorf("Expected Print to leave the settings untouched, got %q", cfg.Database.Password)
+	}
+
+	// The printed configuration can be loaded back
+	clearEnv(t)
+	if _, _, err := config.Load([]string{"-config", writeFile(t, output)}); err != nil {
+		t.Errorf("Expected the printed configuration to be loadable, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package controllers_test
+
+import (
+	"aletheia-server/src/controllers"
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newAuthServer :
+// Serves GET /read, POST /crawl and DELETE /admin behind the authentication middlewares, the keys being resolved from
+// the provided roles by key.
+func newAuthServer(roles map[string]string) *gin.Engine {
+	authenticate := controllers.Authenticate(func(_ context.Context, key string) (*models.ApiKey, error) {
+		if key == "" {
+			return nil, server_errors.ErrMissingApiKey
+		}
+		role, ok := roles[key]
+		if !ok {
+			return nil, server_errors.ErrInvalidApiKey
+		}
+		return &models.ApiKey{Id: 1, Name: key, Role: role}, nil
+	})
+
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.Use(controllers.ErrorHandler())
+
+	handler := func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, controllers.CurrentApiKey(ctx))
+	}
+	server.GET("/read", authenticate, controllers.RequireRole(models.RoleRead), handler)
+	server.POST("/crawl", authenticate, controllers.RequireRole(models.RoleCrawl), handler)
+	server.DELETE("/admin", authenticate, controllers.RequireRole(models.RoleAdmin), handler)
+
+	return server
+}
+
+func TestAuthenticate(t *testing.T) {
+	server := newAuthServer(map[string]string{"reader": models.RoleRead, "crawler": models.RoleCrawl, "admin": models.RoleAdmin})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header string
+		value  string
+		status int
+		code   string
+	}{
+		{"MissingKey", http.MethodGet, "/read", "", "", http.StatusUnauthorized, "MISSING_API_KEY"},
+		{"UnknownKey", http.MethodGet, "/read", "Authorization", "Bearer unknown", http.StatusUnauthorized, "INVALID_API_KEY"},
+		{"BearerToken", http.MethodGet, "/read", "Authorization", "Bearer reader", http.StatusOK, ""},
+		{"ApiKeyHeader", http.MethodGet, "/read", controllers.ApiKeyHeader, "reader", http.StatusOK, ""},
+		{"HigherRoleAllowed", http.MethodGet, "/read", "Authorization", "bearer admin", http.StatusOK, ""},
+		{"ReaderCannotCrawl", http.MethodPost, "/crawl", "Authorization", "Bearer reader", http.StatusForbidden, "FORBIDDEN_API_KEY"},
+		{"CrawlerCrawls", http.MethodPost, "/crawl", "Authorization", "Bearer crawler", http.StatusOK, ""},
+		{"CrawlerIsNotAdmin", http.MethodDelete, "/admin", "Authorization", "Bearer crawler", http.StatusForbidden, "FORBIDDEN_API_KEY"},
+		{"Admin", http.MethodDelete, "/admin", controllers.ApiKeyHeader, "admin", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				request.Header.Set(tt.header, tt.value)
+			}
+
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, recorder.Code, recorder.Body.String())
+			}
+
+			if tt.code == "" {
+				var apiKey models.ApiKey
+				if err := json.Unmarshal(recorder.Body.Bytes(), &apiKey); err != nil || apiKey.Id != 1 {
+					t.Errorf("Expected the handler to see the API key, got %q", recorder.Body.String())
+				}
+				return
+			}
+
+			var response models.Response
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Code != tt.code {
+				t.Errorf("Expected code %q, got %q", tt.code, recorder.Body.String())
+			}
+
+			if tt.status == http.StatusUnauthorized && recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected a WWW-Authenticate challenge along with the 401")
+			}
+		})
+	}
+}
+
+func TestRequireRole_WithoutAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.Use(controllers.ErrorHandler())
+	server.GET("/read", controllers.RequireRole(models.RoleRead), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/read", nil))
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected requests without an authenticated key to be rejected, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,147 @@
+package controllers_test
+
+import (
+	"aletheia-server/src/controllers"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"aletheia-server/src/usecases"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newCrawlServer :
+// Serves POST /crawl and POST /factCheck over a single news outlet, each client being allowed five crawls a day. The
+// crawler usecase is returned too, so the server can be drained.
+func newCrawlServer(t *testing.T) (*gin.Engine, usecases.CrawlerUsecase) {
+	t.Helper()
+
+	outlet := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html><body></body></html>"))
+	}))
+	t.Cleanup(outlet.Close)
+
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	storage := repositories.NewMemoryStorage(store)
+
+	if _, err := storage.Languages.AddLanguage(ctx, models.Language{Name: "english"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.NewsOutlets.AddNewsOutlet(ctx, models.NewsOutlet{
+		Name: "outlet", QueryUrl: outlet.URL + "/search?q=" + models.QueryPlaceholder, HtmlSelector: "a.result",
+		Language: "english", Credibility: 5,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	crawlerUsecase := usecases.NewCrawlerUsecase(repositories.NewCrawlJobRepository(), nil, nil,
+		repositories.NewFetcher(nil, repositories.FetcherConfig{RequestsPerSecond: 1000, Burst: 100}), nil, 20)
+	t.Cleanup(func() {
+		drainCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = crawlerUsecase.Drain(drainCtx)
+	})
+
+	newsOutletUsecase := usecases.NewNewsOutletUsecase(storage.NewsOutlets)
+	quotaUsecase := usecases.NewQuotaUsecase(storage.Quotas, 5)
+	crawlerController := controllers.NewCrawlerController(crawlerUsecase, newsOutletUsecase, quotaUsecase)
+	factCheckController := controllers.NewFactCheckController(
+		usecases.NewFactCheckUsecase(crawlerUsecase, repositories.NewPostRepository(nil),
+			repositories.NewAnalyzerRepository(repositories.AnalyzerConfig{}, nil), nil),
+		newsOutletUsecase, quotaUsecase)
+
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.Use(controllers.ErrorHandler())
+	server.POST("/crawl", crawlerController.Crawl)
+	server.POST("/factCheck", factCheckController.FactCheck)
+
+	return server, crawlerUsecase
+}
+
+func TestCrawl_InvalidRequestsKeepTheQuota(t *testing.T) {
+	server, _ := newCrawlServer(t)
+
+	send := func(path string, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return recorder
+	}
+
+	recorder := send("/crawl", `{"query": "tax cut", "pagesToVisit": 1}`)
+	if recorder.Code != http.StatusAccepted || recorder.Header().Get(controllers.CrawlQuotaRemainingHeader) != "4" {
+		t.Fatalf("Expected the crawl to be counted, got %d with %q remaining", recorder.Code,
+			recorder.Header().Get(controllers.CrawlQuotaRemainingHeader))
+	}
+
+	invalid := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"MalformedCrawl", "/crawl", `{"query": `},
+		{"NoCrawlers", "/crawl", `{"query": "  ", "pagesToVisit": 1}`},
+		{"NegativePagesToVisit", "/crawl", `{"query": "tax cut", "pagesToVisit": -1}`},
+		{"NoPagesToVisit", "/crawl", `{"query": "tax cut"}`},
+		{"TooManyPagesToVisit", "/crawl", `{"query": "tax cut", "pagesToVisit": 21}`},
+		{"MalformedFactCheck", "/factCheck", `not json`},
+		{"FactCheckWithoutUrl", "/factCheck", `{"prompt": "Is it true?"}`},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := send(tt.path, tt.body)
+
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d", recorder.Code)
+			}
+
+			if remaining := recorder.Header().Get(controllers.CrawlQuotaRemainingHeader); remaining != "" {
+				t.Errorf("Expected the quota not to be consumed, got %q remaining", remaining)
+			}
+		})
+	}
+
+	recorder = send("/crawl", `{"query": "tax cut", "pagesToVisit": 1}`)
+	if remaining := recorder.Header().Get(controllers.CrawlQuotaRemainingHeader); remaining != "3" {
+		t.Errorf("Expected the invalid requests to leave the quota unchanged, got %q remaining", remaining)
+	}
+}
+
+func TestCrawl_DrainingServerKeepsTheQuota(t *testing.T) {
+	server, crawlerUsecase := newCrawlServer(t)
+
+	send := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/crawl",
+			strings.NewReader(`{"query": "tax cut", "pagesToVisit": 1}`)))
+		return recorder
+	}
+
+	if recorder := send(); recorder.Header().Get(controllers.CrawlQuotaRemainingHeader) != "4" {
+		t.Fatalf("Expected the crawl to be counted, got %d with %q remaining", recorder.Code,
+			recorder.Header().Get(controllers.CrawlQuotaRemainingHeader))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := crawlerUsecase.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := send()
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", recorder.Code)
+	}
+
+	// The crawl never started, so its unit was given back
+	if remaining := recorder.Header().Get(controllers.CrawlQuotaRemainingHeader); remaining != "4" {
+		t.Errorf("Expected the refused crawl to leave the quota unchanged, got %q remaining", remaining)
+	}
+}
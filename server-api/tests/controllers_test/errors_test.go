@@ -0,0 +1,100 @@
+package controllers_test
+
+import (
+	"aletheia-server/src/controllers"
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestServer(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	server := gin.New()
+	server.HandleMethodNotAllowed = true
+	server.Use(controllers.ErrorHandler())
+	server.NoRoute(controllers.NoRoute)
+	server.NoMethod(controllers.NoMethod)
+	server.GET("/test", handler)
+
+	return server
+}
+
+func serve(t *testing.T, server *gin.Engine, method string, path string) (int, models.Response) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+
+	var response models.Response
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Expected a JSON response, got %q: %v", recorder.Body.String(), err)
+	}
+
+	return recorder.Code, response
+}
+
+func TestErrorHandler_TypedError(t *testing.T) {
+	server := newTestServer(func(ctx *gin.Context) {
+		ctx.Error(server_errors.ErrLanguageNotFound.With("spanish").Wrap(errors.New("sql: no rows in result set")))
+	})
+
+	status, response := serve(t, server, http.MethodGet, "/test")
+
+	if status != http.StatusNotFound || response.Status != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d (%d in body)", status, response.Status)
+	}
+
+	if response.Code != "LANGUAGE_NOT_FOUND" || response.Message != server_errors.LanguageNotFound || response.Details != "spanish" {
+		t.Errorf("Unexpected response %+v", response)
+	}
+}
+
+func TestErrorHandler_UntypedError(t *testing.T) {
+	server := newTestServer(func(ctx *gin.Context) {
+		ctx.Error(errors.New("pq: relation \"news_outlet\" does not exist"))
+	})
+
+	status, response := serve(t, server, http.MethodGet, "/test")
+
+	if status != http.StatusInternalServerError || response.Code != server_errors.CodeInternal {
+		t.Errorf("Expected status 500 with code %s, got %d with %s", server_errors.CodeInternal, status, response.Code)
+	}
+
+	if response.Message != "internal server error" {
+		t.Errorf("Expected the message of untyped errors to be hidden, got %q", response.Message)
+	}
+}
+
+func TestErrorHandler_LeavesWrittenResponses(t *testing.T) {
+	server := newTestServer(func(ctx *gin.Context) {
+		ctx.Error(server_errors.ErrLanguageInUse)
+		ctx.JSON(http.StatusOK, models.Response{Status: http.StatusOK, Code: server_errors.CodeOK})
+	})
+
+	status, response := serve(t, server, http.MethodGet, "/test")
+
+	if status != http.StatusOK || response.Code != server_errors.CodeOK {
+		t.Errorf("Expected the written response to be kept, got %d with %s", status, response.Code)
+	}
+}
+
+func TestErrorHandler_UnknownRouteAndMethod(t *testing.T) {
+	server := newTestServer(func(ctx *gin.Context) {})
+
+	status, response := serve(t, server, http.MethodGet, "/missing")
+	if status != http.StatusNotFound || response.Code != "ROUTE_NOT_FOUND" {
+		t.Errorf("Expected 404 ROUTE_NOT_FOUND, got %d %s", status, response.Code)
+	}
+
+	status, response = serve(t, server, http.MethodDelete, "/test")
+	if status != http.StatusMethodNotAllowed || response.Code != "METHOD_NOT_ALLOWED" {
+		t.Errorf("Expected 405 METHOD_NOT_ALLOWED, got %d %s", status, response.Code)
+	}
+}
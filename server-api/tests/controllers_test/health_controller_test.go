@@ -0,0 +1,54 @@
+package controllers_test
+
+import (
+	"aletheia-server/src/controllers"
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/usecases"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHealthController(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		checkErr error
+		status   int
+		health   string
+	}{
+		{"LivenessIgnoresDependencies", "/healthz", server_errors.ErrDatabaseUnreachable, http.StatusOK, server_errors.HealthUp},
+		{"Ready", "/readyz", nil, http.StatusOK, server_errors.HealthUp},
+		{"NotReady", "/readyz", server_errors.ErrDatabaseUnreachable, http.StatusServiceUnavailable, server_errors.HealthDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := controllers.NewHealthController(usecases.NewHealthUsecase(0,
+				usecases.HealthCheck{Name: "database", Check: func(ctx context.Context) error { return tt.checkErr }},
+			))
+
+			gin.SetMode(gin.TestMode)
+			server := gin.New()
+			server.GET("/healthz", controller.Liveness)
+			server.GET("/readyz", controller.Readiness)
+
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			var health models.Health
+			if err := json.Unmarshal(recorder.Body.Bytes(), &health); err != nil {
+				t.Fatalf("Expected a JSON response, got %q: %v", recorder.Body.String(), err)
+			}
+
+			if recorder.Code != tt.status || health.Status != tt.health {
+				t.Errorf("Expected %d %q, got %d %+v", tt.status, tt.health, recorder.Code, health)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package controllers_test
+
+import (
+	"aletheia-server/src/controllers"
+	"aletheia-server/src/errors"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	server_errors.SetupLogger(server_errors.LogConfig{Level: server_errors.InfoLevel, Format: server_errors.TextFormat}, &buf)
+	defer server_errors.SetupLogger(server_errors.LogConfig{}, os.Stderr)
+
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.Use(controllers.RequestLogger())
+	server.GET("/test", func(ctx *gin.Context) {
+		server_errors.LogContext(ctx.Request.Context(), "handled", server_errors.InfoLevel)
+		ctx.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name     string
+		sent     string
+		expected string
+	}{
+		{"KeepsClientId", "client-id.42", "client-id.42"},
+		{"ReplacesInvalidId", "bad id\n", ""},
+		{"GeneratesMissingId", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+
+			request := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.sent != "" {
+				request.Header.Set(controllers.RequestIdHeader, tt.sent)
+			}
+
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+
+			id := recorder.Header().Get(controllers.RequestIdHeader)
+			if id == "" || (tt.expected != "" && id != tt.expected) || id == tt.sent && tt.expected == "" {
+				t.Fatalf("Unexpected request id %q", id)
+			}
+
+			output := buf.String()
+			if strings.Count(output, "request_id="+id) != 2 {
+				t.Errorf("Expected both logs to carry request_id=%s, got %q", id, output)
+			}
+
+			if !strings.Contains(output, "msg=\"request served\"") || !strings.Contains(output, "status=204") {
+				t.Errorf("Expected the request to be logged, got %q", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package controllers_test
+
+import (
+	"aletheia-server/src/controllers"
+	"aletheia-server/src/metrics"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.Use(controllers.RequestMetrics(), controllers.ErrorHandler())
+	server.NoRoute(controllers.NoRoute)
+	server.GET("/metrics", controllers.Metrics)
+	server.GET("/runs/:crawlRunId", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	matched := metrics.HttpRequests.WithLabelValues(http.MethodGet, "/runs/:crawlRunId", "204")
+	unmatched := metrics.HttpRequests.WithLabelValues(http.MethodGet, "unmatched", "404")
+	beforeMatched, beforeUnmatched := testutil.ToFloat64(matched), testutil.ToFloat64(unmatched)
+
+	for _, path := range []string{"/runs/1", "/runs/2", "/unknown/path"} {
+		server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := testutil.ToFloat64(matched); got != beforeMatched+2 {
+		t.Errorf("Expected the requests to be counted under their route, got %v instead of %v", got, beforeMatched+2)
+	}
+
+	if got := testutil.ToFloat64(unmatched); got != beforeUnmatched+1 {
+		t.Errorf("Expected the unknown path to be counted as unmatched, got %v instead of %v", got, beforeUnmatched+1)
+	}
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected /metrics to answer 200, got %d", recorder.Code)
+	}
+
+	for _, want := range []string{
+		`aletheia_http_requests_total{method="GET",route="/runs/:crawlRunId",status="204"}`,
+		"aletheia_http_request_duration_seconds_bucket",
+		"go_goroutines",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected /metrics to expose %q", want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package controllers_test
+
+import (
+	"aletheia-server/src/controllers"
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimit(t *testing.T) {
+	authenticate := controllers.Authenticate(func(_ context.Context, key string) (*models.ApiKey, error) {
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, server_errors.ErrInvalidApiKey
+		}
+		return &models.ApiKey{Id: id, Role: models.RoleRead}, nil
+	})
+
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.Use(controllers.ErrorHandler())
+	server.GET("/read", authenticate, controllers.RateLimit(repositories.NewRateLimiter(1, 1)), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	send := func(key string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/read", nil)
+		request.Header.Set(controllers.ApiKeyHeader, key)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	if recorder := send("1"); recorder.Code != http.StatusOK {
+		t.Fatalf("Expected the first request to be served, got %d", recorder.Code)
+	}
+
+	recorder := send("1")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429 once the bucket is empty, got %d", recorder.Code)
+	}
+
+	// A token takes a minute to refill
+	if retryAfter, err := strconv.Atoi(recorder.Header().Get(controllers.RetryAfterHeader)); err != nil || retryAfter < 1 || retryAfter > 60 {
+		t.Errorf("Expected a Retry-After of at most a minute, got %q", recorder.Header().Get(controllers.RetryAfterHeader))
+	}
+
+	var response models.Response
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Code != "RATE_LIMITED" {
+		t.Errorf("Expected the RATE_LIMITED code, got %q", recorder.Body.String())
+	}
+
+	// Clients sharing an address are still told apart by their API key
+	if recorder = send("2"); recorder.Code != http.StatusOK {
+		t.Errorf("Expected another API key to have its own bucket, got %d", recorder.Code)
+	}
+}
+
+func TestCrawlQuota(t *testing.T) {
+	resetsAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		quota     models.Quota
+		err       error
+		status    int
+		remaining string
+	}{
+		{"Unlimited", models.Quota{}, nil, http.StatusOK, ""},
+		{"Remaining", models.Quota{Limit: 5, Used: 2, Remaining: 3, ResetsAt: resetsAt}, nil, http.StatusOK, "3"},
+		{"Exceeded", models.Quota{Limit: 5, Used: 5, ResetsAt: resetsAt}, server_errors.ErrCrawlQuotaExceeded, http.StatusTooManyRequests, "0"},
+		{"DatabaseError", models.Quota{}, server_errors.ErrCrawlQuotaError, http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var client string
+			gin.SetMode(gin.TestMode)
+			server := gin.New()
+			server.Use(controllers.ErrorHandler())
+			server.POST("/crawl", func(ctx *gin.Context) {
+				consume := func(_ context.Context, c string) (models.Quota, error) {
+					client = c
+					return tt.quota, tt.err
+				}
+				if _, ok := controllers.ConsumeCrawlQuota(ctx, consume); ok {
+					ctx.Status(http.StatusOK)
+				}
+			})
+
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/crawl", nil))
+
+			if recorder.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, recorder.Code)
+			}
+
+			if client != "ip:192.0.2.1" {
+				t.Errorf("Expected unauthenticated clients to be told apart by their address, got %q", client)
+			}
+
+			if got := recorder.Header().Get(controllers.CrawlQuotaRemainingHeader); got != tt.remaining {
+				t.Errorf("Expected %q crawls remaining, got %q", tt.remaining, got)
+			}
+
+			if tt.status == http.StatusTooManyRequests {
+				retryAfter, err := strconv.Atoi(recorder.Header().Get(controllers.RetryAfterHeader))
+				if err != nil || retryAfter < 3500 || retryAfter > 3600 {
+					t.Errorf("Expected to retry once the quota resets in an hour, got %q", recorder.Header().Get(controllers.RetryAfterHeader))
+				}
+			}
+		})
+	}
+}
+
+func TestAuthFailureLimit(t *testing.T) {
+	var lookups int
+	authenticate := controllers.Authenticate(func(_ context.Context, key string) (*models.ApiKey, error) {
+		lookups++
+		if key != "valid" {
+			return nil, server_errors.ErrInvalidApiKey
+		}
+		return &models.ApiKey{Id: 1, Role: models.RoleRead}, nil
+	})
+
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.Use(controllers.ErrorHandler())
+	server.GET("/read", controllers.AuthFailureLimit(repositories.NewRateLimiter(1, 3)), authenticate,
+		func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	send := func(key string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/read", nil)
+		request.Header.Set(controllers.ApiKeyHeader, key)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Accepted keys take no token
+	for i := 0; i < 5; i++ {
+		if recorder := send("valid"); recorder.Code != http.StatusOK {
+			t.Fatalf("Expected request %d with a valid key to be served, got %d", i+1, recorder.Code)
+		}
+	}
+
+	statuses := make(map[int]int)
+	for i := 0; i < 20; i++ {
+		statuses[send("guess-"+strconv.Itoa(i)).Code]++
+	}
+
+	if statuses[http.StatusUnauthorized] != 3 || statuses[http.StatusTooManyRequests] != 17 {
+		t.Errorf("Expected the burst of invalid keys to be rejected, then the address to be limited, got %v", statuses)
+	}
+
+	if lookups != 5+3 {
+		t.Errorf("Expected the limited requests never to be looked up, got %d lookups", lookups)
+	}
+
+	recorder := send("valid")
+	if recorder.Code != http.StatusTooManyRequests || recorder.Header().Get(controllers.RetryAfterHeader) == "" {
+		t.Errorf("Expected the limited address to be refused with a Retry-After, got %d", recorder.Code)
+	}
+}
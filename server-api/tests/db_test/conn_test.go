@@ -0,0 +1,64 @@
+package db_test
+
+import (
+	"aletheia-server/src/db"
+	"aletheia-server/src/errors"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// closedPort :
+// Returns a local port nothing listens on.
+func closedPort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+
+	return port
+}
+
+func TestConnect_RetriesUntilTimeout(t *testing.T) {
+	config := db.Config{Host: "127.0.0.1", Port: closedPort(t), User: "postgres", DBName: "postgres"}
+	config.ConnectTimeout = 1200 * time.Millisecond
+
+	start := time.Now()
+	connection, err := db.Connect(context.Background(), config)
+
+	if connection != nil || !errors.Is(err, server_errors.ErrDatabaseUnreachable) {
+		t.Fatalf("Expected DatabaseUnreachable, got %v", err)
+	}
+
+	if strings.Contains(err.Error(), "after 1 attempts") {
+		t.Errorf("Expected the connection to be retried, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 3*time.Second {
+		t.Errorf("Expected the retries to last about the connect timeout, took %s", elapsed)
+	}
+}
+
+func TestConnect_AbortedByContext(t *testing.T) {
+	config := db.Config{Host: "127.0.0.1", Port: closedPort(t), User: "postgres", DBName: "postgres"}
+	config.ConnectTimeout = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := db.Connect(ctx, config); !errors.Is(err, server_errors.ErrDatabaseUnreachable) {
+		t.Fatalf("Expected DatabaseUnreachable, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the cancellation to stop the retries, took %s", elapsed)
+	}
+}
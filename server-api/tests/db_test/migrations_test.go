@@ -0,0 +1,153 @@
+package db_test
+
+import (
+	"aletheia-server/src/db"
+	"aletheia-server/src/errors"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMigrations_Embedded(t *testing.T) {
+	postgres, err := db.Migrations(db.PostgresDriver)
+	if err != nil {
+		t.Fatalf("Failed to load the embedded migrations: %v", err)
+	}
+
+	if len(postgres) == 0 || postgres[0].Version != 1 {
+		t.Fatalf("Expected the embedded migrations to start at version 1, got %+v", postgres)
+	}
+
+	for i, migration := range postgres {
+		if migration.Version != i+1 {
+			t.Errorf("Expected migration versions to be consecutive, got %04d at position %d", migration.Version, i)
+		}
+	}
+
+	// Both drivers ship the same versions, so "migrate status" reads the same whatever the storage
+	sqlite, err := db.Migrations(db.SqliteDriver)
+	if err != nil {
+		t.Fatalf("Failed to load the embedded SQLite migrations: %v", err)
+	}
+
+	if len(sqlite) != len(postgres) {
+		t.Fatalf("Expected %d SQLite migrations, got %d", len(postgres), len(sqlite))
+	}
+
+	for i := range sqlite {
+		if sqlite[i].Version != postgres[i].Version || sqlite[i].Name != postgres[i].Name {
+			t.Errorf("Expected SQLite migration %04d_%s, got %04d_%s",
+				postgres[i].Version, postgres[i].Name, sqlite[i].Version, sqlite[i].Name)
+		}
+	}
+}
+
+func TestLoadMigrations_SortsByVersion(t *testing.T) {
+	files := fstest.MapFS{
+		"0002_articles.up.sql":         {Data: []byte("CREATE TABLE articles ();")},
+		"0002_articles.down.sql":       {Data: []byte("DROP TABLE articles;")},
+		"0001_initial_schema.up.sql":   {Data: []byte("CREATE TABLE languages ();")},
+		"0001_initial_schema.down.sql": {Data: []byte("DROP TABLE languages;")},
+	}
+
+	migrations, err := db.LoadMigrations(files)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	if len(migrations) != 2 {
+		t.Fatalf("Expected 2 migrations, got %d", len(migrations))
+	}
+
+	if migrations[0].Version != 1 || migrations[0].Name != "initial_schema" || migrations[1].Version != 2 {
+		t.Errorf("Expected migrations sorted by version, got %+v", migrations)
+	}
+
+	if migrations[1].Up != "CREATE TABLE articles ();" || migrations[1].Down != "DROP TABLE articles;" {
+		t.Errorf("Expected up and down scripts to be read, got %+v", migrations[1])
+	}
+}
+
+func TestLoadMigrations_Errors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files fstest.MapFS
+		want  string
+	}{
+		{
+			name: "invalid name",
+			files: fstest.MapFS{
+				"initial_schema.sql": {Data: []byte("SELECT 1;")},
+			},
+			want: server_errors.MigrationInvalidName,
+		},
+		{
+			name: "missing down",
+			files: fstest.MapFS{
+				"0001_initial_schema.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			want: server_errors.MigrationMissingDirection,
+		},
+		{
+			name: "duplicate version",
+			files: fstest.MapFS{
+				"0001_initial_schema.up.sql":   {Data: []byte("SELECT 1;")},
+				"0001_initial_schema.down.sql": {Data: []byte("SELECT 1;")},
+				"0001_articles.up.sql":         {Data: []byte("SELECT 1;")},
+			},
+			want: server_errors.MigrationDuplicateVersion,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := db.LoadMigrations(tc.files)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+				t.Errorf("Expected error starting with %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestMigrator_Sqlite(t *testing.T) {
+	ctx := context.Background()
+	connection, err := db.Connect(ctx, db.Config{Driver: db.SqliteDriver, Path: filepath.Join(t.TempDir(), "aletheia.db")})
+	if err != nil {
+		t.Fatalf("Failed to open the SQLite file: %v", err)
+	}
+	defer connection.Close()
+
+	migrations, err := db.Migrations(db.SqliteDriver)
+	if err != nil {
+		t.Fatalf("Failed to load the embedded SQLite migrations: %v", err)
+	}
+	migrator := db.NewMigrator(connection, db.SqliteDriver, migrations)
+
+	applied, err := migrator.Up(ctx)
+	if err != nil || len(applied) != len(migrations) {
+		t.Fatalf("Expected every migration to be applied, got %d: %v", len(applied), err)
+	}
+
+	if applied, err = migrator.Up(ctx); err != nil || len(applied) != 0 {
+		t.Fatalf("Expected nothing left to apply, got %d: %v", len(applied), err)
+	}
+
+	// Every migration can be reverted, which leaves an empty file behind
+	reverted, err := migrator.Down(ctx, len(migrations))
+	if err != nil || len(reverted) != len(migrations) {
+		t.Fatalf("Expected every migration to be reverted, got %d: %v", len(reverted), err)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			t.Errorf("Expected %04d_%s to be pending, got applied at %s", status.Version, status.Name, status.AppliedAt)
+		}
+	}
+}
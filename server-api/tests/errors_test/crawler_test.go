@@ -26,6 +26,11 @@ func TestCrawlerStatusConstants(t *testing.T) {
 			constant: server_errors.CrawlerSucceeded,
 			want:     "crawler successfully crawled",
 		},
+		{
+			name:     "CrawlerFailed",
+			constant: server_errors.CrawlerFailed,
+			want:     "crawler failed",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,54 @@
+package server_errors
+
+import (
+	server_errors "aletheia-server/src/errors"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestError_IsMatchesByCode(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("saving: %w", server_errors.ErrNewsOutletNotFound.With("id 4").Wrap(cause))
+
+	if !errors.Is(err, server_errors.ErrNewsOutletNotFound) {
+		t.Error("Expected the specialized error to match its sentinel")
+	}
+
+	if errors.Is(err, server_errors.ErrLanguageNotFound) {
+		t.Error("Expected the error not to match a sentinel with another code")
+	}
+
+	if !errors.Is(err, cause) {
+		t.Error("Expected the cause to be reachable through errors.Is")
+	}
+}
+
+func TestError_WithAndWrapCopy(t *testing.T) {
+	specialized := server_errors.ErrInvalidIdError.With("abc").Wrap(errors.New("strconv"))
+
+	if server_errors.ErrInvalidIdError.Details != "" || server_errors.ErrInvalidIdError.Unwrap() != nil {
+		t.Error("Expected the sentinel to be left untouched")
+	}
+
+	if want := "id should be an integer: abc: strconv"; specialized.Error() != want {
+		t.Errorf("Expected %q, got %q", want, specialized.Error())
+	}
+}
+
+func TestAs(t *testing.T) {
+	typed := server_errors.As(fmt.Errorf("wrapped: %w", server_errors.ErrLanguageInUse))
+	if typed.Code != "LANGUAGE_IN_USE" || typed.Status != http.StatusConflict {
+		t.Errorf("Expected LANGUAGE_IN_USE with status 409, got %s with %d", typed.Code, typed.Status)
+	}
+
+	untyped := server_errors.As(errors.New("pq: password authentication failed"))
+	if untyped.Code != server_errors.CodeInternal || untyped.Status != http.StatusInternalServerError {
+		t.Errorf("Expected %s with status 500, got %s with %d", server_errors.CodeInternal, untyped.Code, untyped.Status)
+	}
+
+	if untyped.Message != "internal server error" || untyped.Details != "" {
+		t.Errorf("Expected the untyped message to be hidden, got %q / %q", untyped.Message, untyped.Details)
+	}
+}
@@ -31,6 +31,11 @@ func TestServerErrorConstants(t *testing.T) {
 			constant: server_errors.InvalidParameters,
 			want:     "invalid parameters",
 		},
+		{
+			name:     "InvalidCascadeError",
+			constant: server_errors.InvalidCascadeError,
+			want:     "cascade should be a boolean",
+		},
 	}
 
 	for _, tt := range tests {
@@ -41,6 +41,16 @@ func TestLanguageErrorConstants(t *testing.T) {
 			got:      server_errors.LanguageNotAdded,
 			expected: "language was not properly added to the database",
 		},
+		{
+			name:     "LanguageInUse",
+			got:      server_errors.LanguageInUse,
+			expected: "language is still used by news outlets, pass cascade=true to delete them as well",
+		},
+		{
+			name:     "LanguageEmptyName",
+			got:      server_errors.LanguageEmptyName,
+			expected: "language name cannot be empty",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -3,7 +3,9 @@ package server_errors
 import (
 	"aletheia-server/src/errors"
 	"bytes"
-	"log"
+	"context"
+	"encoding/json"
+	"log/slog"
 	"os"
 	"strings"
 	"testing"
@@ -15,6 +17,11 @@ func TestLoggingConstants(t *testing.T) {
 		constant string
 		want     string
 	}{
+		{
+			name:     "DebugLevel",
+			constant: server_errors.DebugLevel,
+			want:     "debug",
+		},
 		{
 			name:     "InfoLevel",
 			constant: server_errors.InfoLevel,
@@ -42,12 +49,9 @@ func TestLoggingConstants(t *testing.T) {
 }
 
 func TestLogFunction(t *testing.T) {
-	// Redirect log output to buffer
 	var buf bytes.Buffer
-	log.SetOutput(&buf)
-	defer func() {
-		log.SetOutput(os.Stderr)
-	}()
+	server_errors.SetupLogger(server_errors.LogConfig{Level: server_errors.DebugLevel, Format: server_errors.JSONFormat}, &buf)
+	defer server_errors.SetupLogger(server_errors.LogConfig{}, os.Stderr)
 
 	tests := []struct {
 		name     string
@@ -55,29 +59,35 @@ func TestLogFunction(t *testing.T) {
 		level    string
 		expected string
 	}{
+		{
+			name:     "DebugLog",
+			message:  "test debug message",
+			level:    server_errors.DebugLevel,
+			expected: "DEBUG",
+		},
 		{
 			name:     "InfoLog",
 			message:  "test info message",
 			level:    server_errors.InfoLevel,
-			expected: "\033[96minfo: test info message \033[0m",
+			expected: "INFO",
 		},
 		{
 			name:     "WarningLog",
 			message:  "test warning message",
 			level:    server_errors.WarningLevel,
-			expected: "\033[93mwarning: test warning message \033[0m",
+			expected: "WARN",
 		},
 		{
 			name:     "ErrorLog",
 			message:  "test error message",
 			level:    server_errors.ErrorLevel,
-			expected: "\033[91merror: test error message \033[0m",
+			expected: "ERROR",
 		},
 		{
 			name:     "UnknownLevel",
 			message:  "test unknown message",
 			level:    "unknown",
-			expected: "\033[96minfo: test unknown message \033[0m",
+			expected: "INFO",
 		},
 	}
 
@@ -86,10 +96,41 @@ func TestLogFunction(t *testing.T) {
 			buf.Reset() // Clear buffer before each test
 			server_errors.Log(tt.message, tt.level)
 
-			output := buf.String()
-			if !strings.Contains(output, tt.expected) {
-				t.Errorf("Log() output = %q, want it to contain %q", output, tt.expected)
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("Log() output = %q is not JSON: %v", buf.String(), err)
+			}
+
+			if record["msg"] != tt.message || record["level"] != tt.expected {
+				t.Errorf("Log() output = %v, want msg %q at level %q", record, tt.message, tt.expected)
 			}
 		})
 	}
 }
+
+func TestLogContext_CarriesCorrelationIds(t *testing.T) {
+	var buf bytes.Buffer
+	server_errors.SetupLogger(server_errors.LogConfig{Level: server_errors.InfoLevel, Format: server_errors.TextFormat}, &buf)
+	defer server_errors.SetupLogger(server_errors.LogConfig{}, os.Stderr)
+
+	ctx := server_errors.WithLogAttrs(context.Background(), slog.String(server_errors.RequestIdKey, "abc123"))
+	ctx = server_errors.WithLogAttrs(ctx, slog.Int(server_errors.CrawlerIdKey, 2))
+
+	server_errors.LogContext(ctx, "crawler started", server_errors.InfoLevel, "news_outlet", "bbc")
+	server_errors.LogContext(ctx, "filtered out", server_errors.DebugLevel)
+
+	output := buf.String()
+	for _, want := range []string{"msg=\"crawler started\"", "news_outlet=bbc", "request_id=abc123", "crawler_id=2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("LogContext() output = %q, want it to contain %q", output, want)
+		}
+	}
+
+	if strings.Contains(output, "filtered out") {
+		t.Errorf("Expected debug logs to be filtered out at info level, got %q", output)
+	}
+
+	if strings.Contains(output, "\033[") {
+		t.Errorf("Expected no ANSI colors, got %q", output)
+	}
+}
@@ -0,0 +1,176 @@
+package harness
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// InsufficientAnalysis :
+// Analysis answered by the Analyzer when none of its answers matches the article.
+const InsufficientAnalysis = "The article contains insufficient information to verify the post."
+
+// AnalyzeRequest :
+// Body of a request sent to the "/analyze" endpoint.
+type AnalyzeRequest struct {
+	PostContent string `json:"post_content"`
+	NewsContent string `json:"news_content"`
+	UserContext string `json:"user_context"`
+}
+
+// answer :
+// Analysis returned for the articles containing a phrase.
+type answer struct {
+	phrase   string
+	analysis string
+}
+
+// Analyzer :
+// AI analyzer implementing the "/getLinks" and "/analyze" endpoints without any model. The links of a search page are
+// its anchors pointing at least two directories deep, which skips the navigation of the fixtures. The analysis of an
+// article is the one of the first answer whose phrase it contains, or InsufficientAnalysis.
+type Analyzer struct {
+	Url string
+
+	mu       sync.Mutex
+	answers  []answer
+	statuses map[string]int
+	requests map[string]int
+	analyzed []AnalyzeRequest
+}
+
+// NewAnalyzer :
+// Starts the analyzer until the test is done.
+func NewAnalyzer(tb testing.TB) *Analyzer {
+	tb.Helper()
+
+	analyzer := &Analyzer{
+		statuses: make(map[string]int),
+		requests: make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /getLinks", analyzer.getLinks)
+	mux.HandleFunc("POST /analyze", analyzer.analyze)
+
+	server := httptest.NewServer(analyzer.count(mux))
+	tb.Cleanup(server.Close)
+	analyzer.Url = server.URL
+
+	return analyzer
+}
+
+// Answer :
+// Makes the analyzer answer with analysis for every article containing phrase. Answers are tried in the order they
+// were added.
+func (a *Analyzer) Answer(phrase string, analysis string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.answers = append(a.answers, answer{phrase: phrase, analysis: analysis})
+}
+
+// Fail :
+// Makes the analyzer answer every request to the provided path with status, e.g. to emulate an overloaded model.
+func (a *Analyzer) Fail(path string, status int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.statuses[path] = status
+}
+
+// Requests :
+// Returns how many times the provided path was requested, whatever the answer.
+func (a *Analyzer) Requests(path string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.requests[path]
+}
+
+// Analyzed :
+// Returns every request answered by "/analyze", in the order they were received.
+func (a *Analyzer) Analyzed() []AnalyzeRequest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return append([]AnalyzeRequest(nil), a.analyzed...)
+}
+
+// count :
+// Counts every request, then answers it with the status set by Fail, if any.
+func (a *Analyzer) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		a.requests[r.URL.Path]++
+		status := a.statuses[r.URL.Path]
+		a.mu.Unlock()
+
+		if status != 0 {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *Analyzer) getLinks(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		HtmlContent string `json:"html_content"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || strings.TrimSpace(request.HtmlContent) == "" {
+		http.Error(w, "HTML content cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(request.HtmlContent))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	links := make([]map[string]string, 0)
+	document.Find("a[href]").Each(func(_ int, anchor *goquery.Selection) {
+		href, _ := anchor.Attr("href")
+
+		if strings.Count(strings.Trim(href, "/"), "/") > 0 {
+			links = append(links, map[string]string{"title": strings.TrimSpace(anchor.Text()), "url": href})
+		}
+	})
+
+	writeJson(w, links)
+}
+
+func (a *Analyzer) analyze(w http.ResponseWriter, r *http.Request) {
+	var request AnalyzeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	a.mu.Lock()
+	a.analyzed = append(a.analyzed, request)
+	analysis := InsufficientAnalysis
+	for _, answer := range a.answers {
+		if strings.Contains(request.NewsContent, answer.phrase) {
+			analysis = answer.analysis
+			break
+		}
+	}
+	a.mu.Unlock()
+
+	writeJson(w, map[string]any{"success": true, "analysis": analysis})
+}
+
+func writeJson(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
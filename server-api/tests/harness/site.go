@@ -0,0 +1,113 @@
+// Package harness emulates the websites the server talks to, so the crawls and the fact checks can be run end to end
+// without reaching the internet or running the AI analyzer. Every server is an httptest.Server closed once the test
+// is done, and everything it serves comes from the fixtures directory, so the outcome of a run never varies.
+package harness
+
+import (
+	"aletheia-server/src/models"
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ResultSelector :
+// HTML selector of the search results of the outlets whose fixtures mark them with the "result" class. The search
+// pages of the other outlets can only be read by the AI analyzer.
+const ResultSelector = "a.result"
+
+// fixtures :
+// Pages of every emulated site, one directory per site.
+//
+//go:embed fixtures
+var fixtures embed.FS
+
+// Site :
+// Website serving the fixtures of a single directory, either a news outlet or the site a post to fact check is
+// published on. A request to /some/page is answered with the file some/page.html and /robots.txt with the file of
+// the same name, while anything missing is answered with 404.
+type Site struct {
+	Name string
+	Url  string
+
+	files    fs.FS
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+// NewSite :
+// Starts serving the fixtures of the provided directory until the test is done.
+func NewSite(tb testing.TB, name string) *Site {
+	tb.Helper()
+
+	files, err := fs.Sub(fixtures, path.Join("fixtures", name))
+	if err != nil {
+		tb.Fatalf("No fixtures for the site %q: %v", name, err)
+	}
+
+	site := &Site{
+		Name:     name,
+		files:    files,
+		requests: make(map[string]int),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(site.serve))
+	tb.Cleanup(server.Close)
+	site.Url = server.URL
+
+	return site
+}
+
+// NewsOutlet :
+// Returns the news outlet searched through the search page of the site, whose results are read with selector.
+func (s *Site) NewsOutlet(language string, credibility int, selector string) models.NewsOutlet {
+	return models.NewsOutlet{
+		Name:         s.Name,
+		QueryUrl:     s.Url + "/search?q=" + models.QueryPlaceholder,
+		HtmlSelector: selector,
+		Language:     language,
+		Credibility:  credibility,
+	}
+}
+
+// Page :
+// Returns the absolute url of the provided path of the site.
+func (s *Site) Page(path string) string {
+	return s.Url + path
+}
+
+// Requests :
+// Returns how many times the provided path was requested, whatever the answer.
+func (s *Site) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[path]
+}
+
+func (s *Site) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests[r.URL.Path]++
+	s.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	contentType := "text/plain; charset=utf-8"
+
+	if name != "robots.txt" {
+		name += ".html"
+		contentType = "text/html; charset=utf-8"
+	}
+
+	content, err := fs.ReadFile(s.files, name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(content)
+}
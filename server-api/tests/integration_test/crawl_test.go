@@ -0,0 +1,138 @@
+package integration_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCrawl_CollectsArticlesFromEveryOutlet(t *testing.T) {
+	s := newStack(t)
+	ctx := context.Background()
+
+	result, err := s.crawler.Crawl(ctx, s.newsOutlets(), models.CrawlerInitializer{Query: "tax cut", PagesToVisit: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.Crawlers) != 2 {
+		t.Fatalf("Expected one crawler per outlet, got %+v", result.Crawlers)
+	}
+	herald, ledger := result.Crawlers[0], result.Crawlers[1]
+
+	// The herald is read with its selector, and its missing article is recorded as a failure
+	if herald.Status != server_errors.CrawlerSucceeded || herald.LinkExtractor != models.LinkExtractorSelector {
+		t.Errorf("Expected the herald to be crawled with its selector, got %+v", herald)
+	}
+
+	if len(herald.Articles) != 2 || herald.Articles[0].Title != "Parliament approves the income tax cut" {
+		t.Fatalf("Expected the two articles of the herald, got %+v", herald.Articles)
+	}
+
+	article := herald.Articles[0]
+	if article.Byline != "Maria Lopes" || article.PublishedAt == nil ||
+		!article.PublishedAt.Equal(time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)) ||
+		article.CanonicalUrl != "https://dailyherald.example/articles/parliament-approves-tax-cut" {
+		t.Errorf("Expected the metadata of the article to be extracted, got %+v", article)
+	}
+
+	if !strings.Contains(article.Text, "voted 212 to 180") || strings.Contains(article.Text, "Economists weigh") {
+		t.Errorf("Expected only the body of the article, got %q", article.Text)
+	}
+
+	if len(herald.Failures) != 1 || !strings.HasSuffix(herald.Failures[0].Url, "/articles/tax-cut-live-updates") {
+		t.Errorf("Expected the missing article to be a failure, got %+v", herald.Failures)
+	}
+
+	// The ledger is read by the AI analyzer, and its robots.txt is honoured
+	if ledger.Status != server_errors.CrawlerSucceeded || ledger.LinkExtractor != models.LinkExtractorAI {
+		t.Errorf("Expected the ledger to be crawled with the AI analyzer, got %+v", ledger)
+	}
+
+	if len(ledger.Articles) != 2 || ledger.Articles[0].Title != "Claims of a tax cut vote are premature" {
+		t.Errorf("Expected the two allowed articles of the ledger, got %+v", ledger.Articles)
+	}
+
+	if len(ledger.Failures) != 1 || !strings.Contains(ledger.Failures[0].Error, server_errors.CrawlerRobotsDisallowed) {
+		t.Errorf("Expected the disallowed article to be a failure, got %+v", ledger.Failures)
+	}
+
+	if s.ledger.Requests("/private/tax-cut-insider-memo") != 0 {
+		t.Errorf("Expected the page disallowed by robots.txt never to be requested")
+	}
+
+	if s.analyzer.Requests("/getLinks") != 1 {
+		t.Errorf("Expected the AI analyzer to be asked for the links of the ledger only, got %d requests",
+			s.analyzer.Requests("/getLinks"))
+	}
+
+	// The crawl run is stored along with every article
+	if result.RunId == 0 {
+		t.Fatalf("Expected the crawl run to be stored")
+	}
+
+	run, err := s.crawlRuns.GetCrawlRunById(ctx, result.RunId)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if run.Query != "tax cut" || len(run.Crawlers) != 2 || len(run.Crawlers[0].Articles) != 2 ||
+		len(run.Crawlers[1].Articles) != 2 || run.Crawlers[1].LinkExtractor != models.LinkExtractorAI {
+		t.Errorf("Expected the stored crawl run to match the result, got %+v", run)
+	}
+}
+
+func TestCrawl_AnalyzerDown(t *testing.T) {
+	s := newStack(t)
+	s.analyzer.Fail("/getLinks", 503)
+
+	result, err := s.crawler.Crawl(context.Background(), s.newsOutlets(), models.CrawlerInitializer{Query: "tax cut", PagesToVisit: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Only the outlet needing the AI analyzer fails
+	if result.Crawlers[0].Status != server_errors.CrawlerSucceeded || len(result.Crawlers[0].Articles) != 2 {
+		t.Errorf("Expected the herald to be crawled anyway, got %+v", result.Crawlers[0])
+	}
+
+	if ledger := result.Crawlers[1]; ledger.Status != server_errors.CrawlerFailed || len(ledger.Articles) != 0 {
+		t.Errorf("Expected the ledger to fail without its links, got %+v", ledger)
+	}
+}
+
+func TestCrawlJob_RunsInTheBackground(t *testing.T) {
+	s := newStack(t)
+
+	job, err := s.crawler.StartCrawlJob(context.Background(), s.newsOutlets(),
+		models.CrawlerInitializer{Query: "tax cut", PagesToVisit: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err = s.crawler.Drain(ctx); err != nil {
+		t.Fatalf("Expected the crawl job to finish, got %v", err)
+	}
+
+	finished, err := s.crawler.GetCrawlJob(job.Id)
+	if err != nil || finished.Status != server_errors.CrawlJobSucceeded || finished.Result == nil {
+		t.Fatalf("Expected the crawl job to succeed, got %+v: %v", finished, err)
+	}
+
+	for _, crawler := range finished.Result.Crawlers {
+		if len(crawler.Articles) != 1 {
+			t.Errorf("Expected a single article from %s, got %d", crawler.NewsOutlet, len(crawler.Articles))
+		}
+	}
+
+	runs, err := s.crawlRuns.GetCrawlRuns(context.Background(), 10, 0)
+	if err != nil || len(runs) != 1 || runs[0].Articles != 2 {
+		t.Errorf("Expected the crawl run of the job to be stored, got %+v: %v", runs, err)
+	}
+}
@@ -0,0 +1,105 @@
+package integration_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/tests/harness"
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFactCheck_WeighsTheOutlets(t *testing.T) {
+	s := newStack(t)
+	s.analyzer.Answer("voted 212 to 180", "The article supports the post: the vote took place.")
+	s.analyzer.Answer("no such vote", "The article contradicts the post: the vote was postponed.")
+	ctx := context.Background()
+
+	result, err := s.factCheck.FactCheck(ctx, models.PackageReceived{
+		Url:    s.social.Page("/posts/tax-cut-approved"),
+		Prompt: "Was the tax cut approved?",
+	}, s.newsOutlets())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Query != "Parliament approved the income tax cut" {
+		t.Errorf("Expected the title of the post to be the query, got %q", result.Query)
+	}
+
+	// Both herald articles support the post, one ledger article contradicts it and the other is off topic
+	stances := make(map[string]int)
+	for _, analysis := range result.Analyses {
+		stances[analysis.NewsOutlet+" "+analysis.Stance]++
+	}
+
+	expected := map[string]int{
+		"daily-herald " + models.StanceSupports:   2,
+		"the-ledger " + models.StanceContradicts:  1,
+		"the-ledger " + models.StanceInsufficient: 1,
+	}
+	for key, count := range expected {
+		if stances[key] != count {
+			t.Errorf("Expected %d analyses %q, got %v", count, key, stances)
+		}
+	}
+
+	// (8 + 8 - 3) / (8 + 8 + 3)
+	if math.Abs(result.Score-13.0/19.0) > 1e-9 || result.Verdict != models.VerdictLikelyTrue {
+		t.Errorf("Expected a likely true verdict scored 13/19, got %s %f", result.Verdict, result.Score)
+	}
+
+	for _, request := range s.analyzer.Analyzed() {
+		if request.UserContext != "Was the tax cut approved?" || request.PostContent == "" {
+			t.Errorf("Expected the post and the prompt to be sent along every article, got %+v", request)
+		}
+	}
+
+	// The verdict is stored next to the crawl run it is based on
+	run, err := s.crawlRuns.GetCrawlRunById(ctx, result.Crawl.RunId)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if run.Verdict == nil || run.Verdict.Verdict != models.VerdictLikelyTrue || len(run.Verdict.Analyses) != 4 {
+		t.Errorf("Expected the verdict to be stored, got %+v", run.Verdict)
+	}
+}
+
+func TestFactCheck_NothingMatches(t *testing.T) {
+	s := newStack(t)
+
+	result, err := s.factCheck.FactCheck(context.Background(), models.PackageReceived{
+		Url: s.social.Page("/posts/tax-cut-approved"),
+	}, s.newsOutlets())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Verdict != models.VerdictUnverified || len(result.Analyses) != 4 {
+		t.Errorf("Expected an unverified verdict, got %s from %d analyses", result.Verdict, len(result.Analyses))
+	}
+
+	for _, analysis := range result.Analyses {
+		if analysis.Analysis != harness.InsufficientAnalysis {
+			t.Errorf("Expected every article to be insufficient, got %+v", analysis)
+		}
+	}
+}
+
+func TestFactCheck_MissingPost(t *testing.T) {
+	s := newStack(t)
+
+	_, err := s.factCheck.FactCheck(context.Background(), models.PackageReceived{
+		Url: s.social.Page("/posts/deleted"),
+	}, s.newsOutlets())
+
+	if !errors.Is(err, server_errors.ErrFactCheckPostFetchError) {
+		t.Fatalf("Expected FactCheckPostFetchError, got %v", err)
+	}
+
+	if s.herald.Requests("/search") != 0 || s.ledger.Requests("/search") != 0 {
+		t.Errorf("Expected no outlet to be crawled without a post")
+	}
+}
@@ -0,0 +1,49 @@
+package integration_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/mockanalyzer"
+	"aletheia-server/src/models"
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMockAnalyzer_DropIn(t *testing.T) {
+	s := newStack(t)
+
+	mock := httptest.NewServer(mockanalyzer.NewHandler())
+	t.Cleanup(mock.Close)
+	s.wire(t, mock.URL)
+
+	result, err := s.factCheck.FactCheck(context.Background(), models.PackageReceived{
+		Url: s.social.Page("/posts/tax-cut-approved"),
+	}, s.newsOutlets())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The mock finds the links of the ledger on its own
+	ledger := result.Crawl.Crawlers[1]
+	if ledger.Status != server_errors.CrawlerSucceeded || ledger.LinkExtractor != models.LinkExtractorAI ||
+		len(ledger.Articles) != 2 {
+		t.Fatalf("Expected the mock to find the links of the ledger, got %+v", ledger)
+	}
+
+	stances := make(map[string]string)
+	for _, analysis := range result.Analyses {
+		stances[analysis.Title] = analysis.Stance
+	}
+
+	if stance := stances["Parliament approves the income tax cut"]; stance != models.StanceSupports {
+		t.Errorf("Expected the article reporting the vote to support the post, got %q", stance)
+	}
+
+	if stance := stances["Sunny weekend ahead for most of the country"]; stance != models.StanceInsufficient {
+		t.Errorf("Expected the weather forecast to be insufficient, got %q", stance)
+	}
+
+	if result.Verdict == models.VerdictUnverified || result.Verdict == "" {
+		t.Errorf("Expected the mock analyses to lead to a verdict, got %s %f", result.Verdict, result.Score)
+	}
+}
@@ -0,0 +1,86 @@
+package integration_test
+
+import (
+	"aletheia-server/src/db"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"aletheia-server/src/usecases"
+	"aletheia-server/tests/harness"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// stack :
+// The use cases of the server wired as in main, storing their runs in a SQLite file and talking to the emulated news
+// outlets and AI analyzer of the harness. "daily-herald" marks its results for its selector, while the AI analyzer has
+// to find the links of "the-ledger", whose robots.txt disallows one of them.
+type stack struct {
+	herald   *harness.Site
+	ledger   *harness.Site
+	social   *harness.Site
+	analyzer *harness.Analyzer
+
+	crawler   usecases.CrawlerUsecase
+	factCheck usecases.FactCheckUsecase
+	crawlRuns usecases.CrawlRunUsecase
+}
+
+func newStack(t *testing.T) *stack {
+	t.Helper()
+
+	s := &stack{
+		herald:   harness.NewSite(t, "daily-herald"),
+		ledger:   harness.NewSite(t, "the-ledger"),
+		social:   harness.NewSite(t, "social"),
+		analyzer: harness.NewAnalyzer(t),
+	}
+	s.wire(t, s.analyzer.Url)
+
+	return s
+}
+
+// wire :
+// Builds the use cases of the stack on a new SQLite file, sending the requests meant for the AI analyzer to
+// analyzerUrl.
+func (s *stack) wire(t *testing.T, analyzerUrl string) {
+	t.Helper()
+
+	ctx := context.Background()
+	connection, err := db.Connect(ctx, db.Config{Driver: db.SqliteDriver, Path: filepath.Join(t.TempDir(), "aletheia.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = connection.Close() })
+
+	migrations, err := db.Migrations(db.SqliteDriver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.NewMigrator(connection, db.SqliteDriver, migrations).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	storage := repositories.NewSqliteStorage(connection)
+
+	// Failed requests are not retried, so the failures show up right away
+	httpClient := repositories.NewHttpClient(repositories.HttpClientConfig{Timeout: 5 * time.Second, MaxRetries: 0})
+	fetcher := repositories.NewFetcher(httpClient, repositories.FetcherConfig{RequestsPerSecond: 1000, Burst: 100})
+	analyzer := repositories.NewAnalyzerRepository(repositories.AnalyzerConfig{Url: analyzerUrl}, httpClient)
+
+	s.crawler = usecases.NewCrawlerUsecase(repositories.NewCrawlJobRepository(), storage.CrawlRuns, nil, fetcher, &analyzer, 20)
+	// The posts are served by the local fake sites
+	postRepository := repositories.NewPostRepository(httpClient)
+	postRepository.AllowPrivateNetworks = true
+	s.factCheck = usecases.NewFactCheckUsecase(s.crawler, postRepository, analyzer, storage.CrawlRuns)
+	s.crawlRuns = usecases.NewCrawlRunUsecase(storage.CrawlRuns)
+}
+
+// newsOutlets :
+// Returns both emulated news outlets, the herald being far more credible than the ledger.
+func (s *stack) newsOutlets() []models.NewsOutlet {
+	return []models.NewsOutlet{
+		s.herald.NewsOutlet("english", 8, harness.ResultSelector),
+		s.ledger.NewsOutlet("english", 3, harness.ResultSelector),
+	}
+}
@@ -0,0 +1,151 @@
+package metrics_test
+
+import (
+	"aletheia-server/src/metrics"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// filterOutlet :
+// Returns a gatherer exposing only the series of the provided news outlet, so tests sharing the registry do not see
+// each other.
+func filterOutlet(t *testing.T, newsOutlet string) prometheus.Gatherer {
+	t.Helper()
+
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := metrics.Registry.Gather()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, family := range families {
+			kept := family.Metric[:0]
+			for _, metric := range family.Metric {
+				if labelValue(metric, "news_outlet") == newsOutlet {
+					kept = append(kept, metric)
+				}
+			}
+			family.Metric = kept
+		}
+
+		return families, nil
+	})
+}
+
+// histogramCount :
+// Returns the number of observations of the histogram series matching the provided news outlet and result.
+func histogramCount(t *testing.T, name string, newsOutlet string, result string) uint64 {
+	t.Helper()
+
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.Metric {
+			if labelValue(metric, "news_outlet") == newsOutlet && labelValue(metric, "result") == result {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	return 0
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+
+	return ""
+}
+
+func TestObserveCrawl(t *testing.T) {
+	before := testutil.ToFloat64(metrics.Crawls.WithLabelValues("observe-crawl", "succeeded"))
+
+	metrics.ObserveCrawl("observe-crawl", "succeeded", 2*time.Second)
+	metrics.ObserveCrawl("observe-crawl", "failed", time.Second)
+
+	if got := testutil.ToFloat64(metrics.Crawls.WithLabelValues("observe-crawl", "succeeded")); got != before+1 {
+		t.Errorf("Expected %v succeeded crawls, got %v", before+1, got)
+	}
+
+	if got := testutil.ToFloat64(metrics.Crawls.WithLabelValues("observe-crawl", "failed")); got != 1 {
+		t.Errorf("Expected 1 failed crawl, got %v", got)
+	}
+}
+
+func TestObserveArticleFetch(t *testing.T) {
+	metrics.ObserveArticleFetch("observe-fetch", 100*time.Millisecond, 2048, nil)
+	metrics.ObserveArticleFetch("observe-fetch", time.Second, 0, errors.New("timeout"))
+
+	expected := `
+		# HELP aletheia_article_fetch_bytes Size of the bodies of the article pages fetched.
+		# TYPE aletheia_article_fetch_bytes histogram
+		aletheia_article_fetch_bytes_bucket{news_outlet="observe-fetch",le="1024"} 0
+		aletheia_article_fetch_bytes_bucket{news_outlet="observe-fetch",le="4096"} 1
+		aletheia_article_fetch_bytes_bucket{news_outlet="observe-fetch",le="16384"} 1
+		aletheia_article_fetch_bytes_bucket{news_outlet="observe-fetch",le="65536"} 1
+		aletheia_article_fetch_bytes_bucket{news_outlet="observe-fetch",le="262144"} 1
+		aletheia_article_fetch_bytes_bucket{news_outlet="observe-fetch",le="1.048576e+06"} 1
+		aletheia_article_fetch_bytes_bucket{news_outlet="observe-fetch",le="4.194304e+06"} 1
+		aletheia_article_fetch_bytes_bucket{news_outlet="observe-fetch",le="1.6777216e+07"} 1
+		aletheia_article_fetch_bytes_bucket{news_outlet="observe-fetch",le="+Inf"} 1
+		aletheia_article_fetch_bytes_sum{news_outlet="observe-fetch"} 2048
+		aletheia_article_fetch_bytes_count{news_outlet="observe-fetch"} 1
+	`
+
+	if err := testutil.GatherAndCompare(filterOutlet(t, "observe-fetch"), strings.NewReader(expected), "aletheia_article_fetch_bytes"); err != nil {
+		t.Error(err)
+	}
+
+	for _, result := range []string{metrics.ResultSuccess, metrics.ResultError} {
+		if count := histogramCount(t, "aletheia_article_fetch_duration_seconds", "observe-fetch", result); count != 1 {
+			t.Errorf("Expected 1 %s fetch, got %d", result, count)
+		}
+	}
+}
+
+func TestObserveAnalyzerRequest(t *testing.T) {
+	success := metrics.AnalyzerRequests.WithLabelValues(metrics.AnalyzerAnalyze, metrics.ResultSuccess)
+	failure := metrics.AnalyzerRequests.WithLabelValues(metrics.AnalyzerAnalyze, metrics.ResultError)
+	beforeSuccess, beforeFailure := testutil.ToFloat64(success), testutil.ToFloat64(failure)
+
+	metrics.ObserveAnalyzerRequest(metrics.AnalyzerAnalyze, time.Second, nil)
+	metrics.ObserveAnalyzerRequest(metrics.AnalyzerAnalyze, time.Second, errors.New("status 500"))
+	metrics.ObserveAnalyzerRequest(metrics.AnalyzerAnalyze, time.Second, errors.New("status 500"))
+
+	if got := testutil.ToFloat64(success); got != beforeSuccess+1 {
+		t.Errorf("Expected %v successful requests, got %v", beforeSuccess+1, got)
+	}
+
+	if got := testutil.ToFloat64(failure); got != beforeFailure+2 {
+		t.Errorf("Expected %v failed requests, got %v", beforeFailure+2, got)
+	}
+}
+
+func TestRegistry_Lints(t *testing.T) {
+	metrics.ObserveHttpRequest("GET", "/ping", 200, time.Millisecond)
+
+	problems, err := testutil.GatherAndLint(metrics.Registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, problem := range problems {
+		t.Errorf("%s: %s", problem.Metric, problem.Text)
+	}
+}
@@ -0,0 +1,135 @@
+package mockanalyzer_test
+
+import (
+	"aletheia-server/src/mockanalyzer"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const searchPage = `<html><head><base href="https://news.example/"></head><body>
+<nav><a href="/politics/parliament-news">Everything about the parliament today</a></nav>
+<a href="/">Home</a>
+<a href="#top">Back to the top of the page</a>
+<a href="mailto:desk@news.example">Write to the news desk today</a>
+<a href="/politics/2026/tax-cut-approved">Parliament approves   the income tax cut</a>
+<a href="/politics/2026/tax-cut-approved#comments">Parliament approves the income tax cut</a>
+<a href="/economists-weigh-the-cost">Economists weigh the cost</a>
+<a href="/subscribe">Subscribe to our newsletter now</a>
+<a href="https://other.example/world/storm"><img alt=""></a>
+<a href="https://other.example/world/storm-hits-coast" title="Storm hits the coast overnight"></a>
+<footer><a href="/about/the-team">Meet the people behind the news</a></footer>
+</body></html>`
+
+func TestExtractLinks(t *testing.T) {
+	links, err := mockanalyzer.ExtractLinks(searchPage)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []mockanalyzer.Link{
+		{Title: "Parliament approves the income tax cut", Url: "https://news.example/politics/2026/tax-cut-approved"},
+		{Title: "Economists weigh the cost", Url: "https://news.example/economists-weigh-the-cost"},
+		{Title: "Storm hits the coast overnight", Url: "https://other.example/world/storm-hits-coast"},
+	}
+
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, links)
+	}
+}
+
+func TestExtractLinks_NoArticles(t *testing.T) {
+	links, err := mockanalyzer.ExtractLinks(`<p>No results</p>`)
+	if err != nil || links == nil || len(links) != 0 {
+		t.Errorf("Expected an empty list, got %+v: %v", links, err)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	post := "Parliament approved the income tax cut, the rate drops in January"
+
+	tests := []struct {
+		name     string
+		news     string
+		expected string
+	}{
+		{
+			name:     "supports",
+			news:     "The parliament approved an income tax cut: the basic rate drops to 18 percent in January.",
+			expected: "The article supports the post",
+		},
+		{
+			name:     "partially",
+			news:     "Economists warn that the income tax cut will be costly.",
+			expected: "The article partially matches the post",
+		},
+		{
+			name:     "insufficient",
+			news:     "A sunny weekend is ahead for most of the country.",
+			expected: "The article contains insufficient information",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if analysis := mockanalyzer.Analyze(post, test.news); !strings.HasPrefix(analysis, test.expected) {
+				t.Errorf("Expected %q, got %q", test.expected, analysis)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(mockanalyzer.NewHandler())
+	defer server.Close()
+
+	post := func(path string, body string) (*http.Response, map[string]any) {
+		resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var decoded any
+		_ = json.NewDecoder(resp.Body).Decode(&decoded)
+
+		if object, ok := decoded.(map[string]any); ok {
+			return resp, object
+		}
+		return resp, map[string]any{"list": decoded}
+	}
+
+	resp, body := post("/getLinks", `{"html_content": "<a href='/world/2026/storm-hits-coast'>Storm hits the coast</a>"}`)
+	if links, _ := body["list"].([]any); resp.StatusCode != http.StatusOK || len(links) != 1 {
+		t.Errorf("Expected a single link, got %d %+v", resp.StatusCode, body)
+	}
+
+	resp, body = post("/getLinks", `{"html_content": "  "}`)
+	if resp.StatusCode != http.StatusBadRequest || body["detail"] != "HTML content cannot be empty" {
+		t.Errorf("Expected empty HTML to be rejected, got %d %+v", resp.StatusCode, body)
+	}
+
+	resp, body = post("/analyze", `{"post_content": "Storm hits the coast", "news_content": "A storm hits the coast"}`)
+	if resp.StatusCode != http.StatusOK || body["success"] != true ||
+		!strings.HasPrefix(body["analysis"].(string), "The article supports the post") {
+		t.Errorf("Expected a successful analysis, got %d %+v", resp.StatusCode, body)
+	}
+
+	resp, _ = post("/analyze", `{"post_content": "Storm hits the coast"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a missing article to be rejected, got %d", resp.StatusCode)
+	}
+
+	resp, _ = post("/analyze", `not json`)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected a malformed body to be rejected, got %d", resp.StatusCode)
+	}
+
+	// Like the Python analyzer, nothing is served on the base URL, which still counts as up for the health checks
+	if resp, err := http.Get(server.URL); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 on the base URL, got %v %v", resp, err)
+	}
+}
@@ -0,0 +1,43 @@
+package models_test
+
+import (
+	"aletheia-server/src/models"
+	"testing"
+)
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     string
+		required string
+		expected bool
+	}{
+		{models.RoleRead, models.RoleRead, true},
+		{models.RoleRead, models.RoleCrawl, false},
+		{models.RoleRead, models.RoleAdmin, false},
+		{models.RoleCrawl, models.RoleRead, true},
+		{models.RoleCrawl, models.RoleCrawl, true},
+		{models.RoleCrawl, models.RoleAdmin, false},
+		{models.RoleAdmin, models.RoleRead, true},
+		{models.RoleAdmin, models.RoleAdmin, true},
+		{"superuser", models.RoleRead, false},
+		{"", models.RoleRead, false},
+	}
+
+	for _, tt := range tests {
+		if got := models.RoleAllows(tt.role, tt.required); got != tt.expected {
+			t.Errorf("RoleAllows(%q, %q) = %v, expected %v", tt.role, tt.required, got, tt.expected)
+		}
+	}
+}
+
+func TestValidRole(t *testing.T) {
+	for _, role := range []string{models.RoleRead, models.RoleCrawl, models.RoleAdmin} {
+		if !models.ValidRole(role) {
+			t.Errorf("Expected %q to be valid", role)
+		}
+	}
+
+	if models.ValidRole("Admin") || models.ValidRole("") {
+		t.Error("Expected roles to be matched exactly")
+	}
+}
@@ -0,0 +1,115 @@
+package models_test
+
+import (
+	"aletheia-server/src/models"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCrawlerResult_JSONFieldNames(t *testing.T) {
+	result := models.CrawlerResult{
+		CrawlerId:   1,
+		NewsOutlet:  "reuters",
+		Query:       "https://reuters.com/search?q=test",
+		Status:      "crawler successfully crawled",
+		VisitedUrls: []string{"https://reuters.com/article"},
+		Articles:    []models.Article{{Url: "https://reuters.com/article", Text: "article body"}},
+	}
+
+	expectedFields := []string{
+		"crawlerId", "newsOutlet", "query", "status", "visitedUrls", "articles", "startedAt", "finishedAt", "durationMs",
+	}
+	testExactJSONFields(t, result, expectedFields)
+}
+
+func TestCrawlerResult_ErrorOmittedWhenEmpty(t *testing.T) {
+	jsonData, err := json.Marshal(models.CrawlerResult{})
+	if err != nil {
+		t.Fatalf("Failed to marshal CrawlerResult to JSON: %v", err)
+	}
+
+	var unmarshaled map[string]interface{}
+	if err := json.Unmarshal(jsonData, &unmarshaled); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	if _, ok := unmarshaled["error"]; ok {
+		t.Errorf("Expected 'error' to be omitted when empty")
+	}
+
+	jsonData, err = json.Marshal(models.CrawlerResult{Error: "timeout"})
+	if err != nil {
+		t.Fatalf("Failed to marshal CrawlerResult to JSON: %v", err)
+	}
+
+	if err := json.Unmarshal(jsonData, &unmarshaled); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	if unmarshaled["error"] != "timeout" {
+		t.Errorf("For key 'error': expected %q, got %v", "timeout", unmarshaled["error"])
+	}
+}
+
+func TestCrawlResult_RoundTrip(t *testing.T) {
+	startedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	result := models.CrawlResult{
+		Query:        "test query",
+		PagesToVisit: 3,
+		StartedAt:    startedAt,
+		FinishedAt:   startedAt.Add(2 * time.Second),
+		DurationMs:   2000,
+		Crawlers: []models.CrawlerResult{
+			{CrawlerId: 1, NewsOutlet: "reuters", Status: "crawler failed", Error: "timeout"},
+		},
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Failed to marshal CrawlResult to JSON: %v", err)
+	}
+
+	var decoded models.CrawlResult
+	if err := json.Unmarshal(jsonData, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	if decoded.Query != result.Query || decoded.PagesToVisit != result.PagesToVisit || decoded.DurationMs != 2000 {
+		t.Errorf("Decoded result does not match: %+v", decoded)
+	}
+
+	if !decoded.StartedAt.Equal(startedAt) {
+		t.Errorf("Expected startedAt %v, got %v", startedAt, decoded.StartedAt)
+	}
+
+	if len(decoded.Crawlers) != 1 || decoded.Crawlers[0].Error != "timeout" {
+		t.Errorf("Expected one failed crawler, got %+v", decoded.Crawlers)
+	}
+}
+
+// Helper function to check that a value marshals to exactly the expected JSON fields
+func testExactJSONFields(t *testing.T, value interface{}, expectedFields []string) {
+	t.Helper()
+
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Failed to marshal %T to JSON: %v", value, err)
+	}
+
+	var unmarshaled map[string]interface{}
+	err = json.Unmarshal(jsonData, &unmarshaled)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	for _, field := range expectedFields {
+		if _, ok := unmarshaled[field]; !ok {
+			t.Errorf("Expected JSON field '%s' not found", field)
+		}
+	}
+
+	if len(unmarshaled) != len(expectedFields) {
+		t.Errorf("Expected exactly %d JSON fields, got %d", len(expectedFields), len(unmarshaled))
+	}
+}
@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"aletheia-server/src/models"
+	"encoding/json"
+	"testing"
+)
+
+func TestCrawlRun_FlattensCrawlResult(t *testing.T) {
+	run := models.CrawlRun{
+		CrawlResult: models.CrawlResult{RunId: 7, Query: "test query", PagesToVisit: 3},
+	}
+
+	expectedFields := []string{
+		"runId", "query", "pagesToVisit", "startedAt", "finishedAt", "durationMs", "crawlers",
+	}
+	testExactJSONFields(t, run, expectedFields)
+}
+
+func TestCrawlRun_IncludesVerdictWhenPresent(t *testing.T) {
+	run := models.CrawlRun{
+		CrawlResult: models.CrawlResult{RunId: 7},
+		Verdict:     &models.Verdict{Verdict: models.VerdictLikelyTrue, Score: 0.8},
+	}
+
+	jsonData, err := json.Marshal(run)
+	if err != nil {
+		t.Fatalf("Failed to marshal CrawlRun to JSON: %v", err)
+	}
+
+	var decoded models.CrawlRun
+	if err := json.Unmarshal(jsonData, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	if decoded.RunId != 7 || decoded.Verdict == nil || decoded.Verdict.Verdict != models.VerdictLikelyTrue {
+		t.Errorf("Decoded crawl run does not match: %+v", decoded)
+	}
+}
+
+func TestCrawlResult_RunIdOmittedWhenNotStored(t *testing.T) {
+	jsonData, err := json.Marshal(models.CrawlResult{})
+	if err != nil {
+		t.Fatalf("Failed to marshal CrawlResult to JSON: %v", err)
+	}
+
+	var unmarshaled map[string]interface{}
+	if err := json.Unmarshal(jsonData, &unmarshaled); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	if _, ok := unmarshaled["runId"]; ok {
+		t.Errorf("Expected 'runId' to be omitted when the result was not stored")
+	}
+}
@@ -157,3 +157,44 @@ func testNewsOutletMarshaling(t *testing.T, outlet models.NewsOutlet, expected m
 		}
 	}
 }
+
+func TestNewsOutletPatch_Apply(t *testing.T) {
+	outlet := models.NewsOutlet{
+		Id:           1,
+		Name:         "Reuters",
+		QueryUrl:     "https://reuters.com/search?q=QUERY_HERE",
+		HtmlSelector: "div.article",
+		Language:     "English",
+		Credibility:  90,
+	}
+
+	var patch models.NewsOutletPatch
+	if err := json.Unmarshal([]byte(`{"credibility": 75, "htmlSelector": "a.result"}`), &patch); err != nil {
+		t.Fatalf("Failed to unmarshal NewsOutletPatch: %v", err)
+	}
+
+	if patch.IsEmpty() {
+		t.Fatalf("Expected patch with fields to not be empty")
+	}
+
+	patched := patch.Apply(outlet)
+
+	if patched.Credibility != 75 || patched.HtmlSelector != "a.result" {
+		t.Errorf("Expected patched fields to be replaced, got %+v", patched)
+	}
+
+	if patched.Id != outlet.Id || patched.Name != outlet.Name || patched.QueryUrl != outlet.QueryUrl || patched.Language != outlet.Language {
+		t.Errorf("Expected absent fields to be kept, got %+v", patched)
+	}
+}
+
+func TestNewsOutletPatch_IsEmpty(t *testing.T) {
+	var patch models.NewsOutletPatch
+	if err := json.Unmarshal([]byte(`{}`), &patch); err != nil {
+		t.Fatalf("Failed to unmarshal NewsOutletPatch: %v", err)
+	}
+
+	if !patch.IsEmpty() {
+		t.Errorf("Expected patch without fields to be empty")
+	}
+}
@@ -11,6 +11,7 @@ func TestResponse_EmptyStruct(t *testing.T) {
 	expected := map[string]interface{}{
 		"message": "",
 		"status":  float64(0),
+		"code":    "",
 	}
 
 	testResponseMarshaling(t, resp, expected)
@@ -20,10 +21,14 @@ func TestResponse_CompleteStruct(t *testing.T) {
 	resp := models.Response{
 		Message: "Operation successful",
 		Status:  200,
+		Code:    "OK",
+		Details: "language was deleted",
 	}
 	expected := map[string]interface{}{
 		"message": "Operation successful",
 		"status":  float64(200),
+		"code":    "OK",
+		"details": "language was deleted",
 	}
 
 	testResponseMarshaling(t, resp, expected)
@@ -36,6 +41,7 @@ func TestResponse_OnlyMessage(t *testing.T) {
 	expected := map[string]interface{}{
 		"message": "Not found",
 		"status":  float64(0),
+		"code":    "",
 	}
 
 	testResponseMarshaling(t, resp, expected)
@@ -48,6 +54,7 @@ func TestResponse_OnlyStatus(t *testing.T) {
 	expected := map[string]interface{}{
 		"message": "",
 		"status":  float64(404),
+		"code":    "",
 	}
 
 	testResponseMarshaling(t, resp, expected)
@@ -74,6 +81,7 @@ func TestResponse_StatusCodes(t *testing.T) {
 			expected := map[string]interface{}{
 				"message": "",
 				"status":  tt.expectedJson,
+				"code":    "",
 			}
 
 			testResponseMarshaling(t, resp, expected)
@@ -85,6 +93,7 @@ func TestResponse_JSONFieldNames(t *testing.T) {
 	resp := models.Response{
 		Message: "Test field names",
 		Status:  201,
+		Code:    "OK",
 	}
 
 	jsonData, err := json.Marshal(resp)
@@ -98,7 +107,8 @@ func TestResponse_JSONFieldNames(t *testing.T) {
 		t.Fatalf("Failed to unmarshal JSON: %v", err)
 	}
 
-	expectedFields := []string{"message", "status"}
+	// Details is left out when empty
+	expectedFields := []string{"message", "status", "code"}
 	for _, field := range expectedFields {
 		if _, ok := unmarshaled[field]; !ok {
 			t.Errorf("Expected JSON field '%s' not found", field)
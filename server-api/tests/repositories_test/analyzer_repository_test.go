@@ -0,0 +1,62 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAnalyzerRepository_Ping(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		healthy bool
+	}{
+		{"NoRoute", http.StatusNotFound, true},
+		{"Ok", http.StatusOK, true},
+		{"ServerError", http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			analyzer := repositories.NewAnalyzerRepository(repositories.AnalyzerConfig{Url: server.URL}, nil)
+			err := analyzer.Ping(context.Background())
+
+			if tt.healthy != (err == nil) {
+				t.Errorf("Ping() = %v, expected healthy: %v", err, tt.healthy)
+			}
+
+			if err != nil && !errors.Is(err, server_errors.ErrAnalyzerUnreachable) {
+				t.Errorf("Expected AnalyzerUnreachable, got %v", err)
+			}
+
+			if requests != 1 {
+				t.Errorf("Expected a single attempt, got %d", requests)
+			}
+		})
+	}
+}
+
+func TestAnalyzerRepository_PingUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	analyzer := repositories.NewAnalyzerRepository(repositories.AnalyzerConfig{Url: url}, nil)
+
+	if err := analyzer.Ping(context.Background()); !errors.Is(err, server_errors.ErrAnalyzerUnreachable) {
+		t.Errorf("Expected AnalyzerUnreachable, got %v", err)
+	}
+}
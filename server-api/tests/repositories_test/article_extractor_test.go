@@ -0,0 +1,128 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/repositories"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+const articlePage = `
+<html>
+<head>
+	<title>Tax cuts approved | Example News</title>
+	<meta property="og:title" content="Tax cuts approved by the parliament">
+	<meta name="author" content="Jane Doe">
+	<meta property="article:published_time" content="2025-01-02T10:30:00Z">
+	<link rel="canonical" href="/2025/01/tax-cuts">
+	<script>trackVisitor();</script>
+</head>
+<body>
+	<header class="site-header"><a href="/">Example News</a></header>
+	<nav><a href="/politics">Politics</a></nav>
+	<div class="layout">
+		<div class="share-buttons"><p>Share this article on every social network you know of.</p></div>
+		<div class="story-body">
+			<p>The parliament approved new tax cuts on Thursday, reducing rates for incomes under 100,000.</p>
+			<p>The bill, which passed by a narrow margin, affects roughly 60% of taxpayers, officials said.</p>
+			<h2>What changes</h2>
+			<p>Cuts average 1,200 a year and take effect in March, according to the finance ministry.</p>
+		</div>
+		<div class="related-stories"><p>Another unrelated story that should never be part of the article.</p></div>
+	</div>
+	<footer><p>Copyright Example News, all rights reserved, since forever.</p></footer>
+</body>
+</html>`
+
+func TestExtractArticle_Metadata(t *testing.T) {
+	article, err := repositories.ExtractArticle(articlePage, "https://news.example.com/2025/01/tax-cuts?utm=feed", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if article.Title != "Tax cuts approved by the parliament" {
+		t.Errorf("Unexpected title: %q", article.Title)
+	}
+
+	if article.Byline != "Jane Doe" {
+		t.Errorf("Unexpected byline: %q", article.Byline)
+	}
+
+	if article.CanonicalUrl != "https://news.example.com/2025/01/tax-cuts" {
+		t.Errorf("Unexpected canonical url: %q", article.CanonicalUrl)
+	}
+
+	if article.Url != "https://news.example.com/2025/01/tax-cuts?utm=feed" {
+		t.Errorf("Unexpected url: %q", article.Url)
+	}
+
+	expectedDate := time.Date(2025, 1, 2, 10, 30, 0, 0, time.UTC)
+	if article.PublishedAt == nil || !article.PublishedAt.Equal(expectedDate) {
+		t.Errorf("Expected publishing date %v, got %v", expectedDate, article.PublishedAt)
+	}
+
+	if article.RawHtml != "" {
+		t.Errorf("Raw HTML should only be kept on request")
+	}
+}
+
+func TestExtractArticle_MainText(t *testing.T) {
+	article, err := repositories.ExtractArticle(articlePage, "https://news.example.com/2025/01/tax-cuts", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"The parliament approved new tax cuts on Thursday, reducing rates for incomes under 100,000.",
+		"The bill, which passed by a narrow margin, affects roughly 60% of taxpayers, officials said.",
+		"What changes",
+		"Cuts average 1,200 a year and take effect in March, according to the finance ministry.",
+	}, "\n")
+
+	if article.Text != expected {
+		t.Errorf("Unexpected text:\n%s\nexpected:\n%s", article.Text, expected)
+	}
+
+	sum := sha256.Sum256([]byte(expected))
+	if article.ContentHash != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected the content hash to be the SHA-256 of the text, got %q", article.ContentHash)
+	}
+}
+
+func TestExtractArticle_KeepRawHtml(t *testing.T) {
+	article, err := repositories.ExtractArticle(articlePage, "https://news.example.com/2025/01/tax-cuts", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if article.RawHtml != articlePage {
+		t.Errorf("Expected the raw HTML to be kept")
+	}
+}
+
+func TestExtractArticle_Fallbacks(t *testing.T) {
+	page := `<html><head><title>Plain page</title></head><body><h1>Heading</h1><span>Short text</span></body></html>`
+
+	article, err := repositories.ExtractArticle(page, "https://news.example.com/plain", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if article.Title != "Heading" {
+		t.Errorf("Expected the main heading to be used as title, got %q", article.Title)
+	}
+
+	if article.CanonicalUrl != "https://news.example.com/plain" {
+		t.Errorf("Expected the page url to be used as canonical url, got %q", article.CanonicalUrl)
+	}
+
+	if article.Text != "Heading Short text" {
+		t.Errorf("Expected the whole body as text, got %q", article.Text)
+	}
+
+	if article.PublishedAt != nil || article.Byline != "" {
+		t.Errorf("Expected no publishing date nor byline, got %v and %q", article.PublishedAt, article.Byline)
+	}
+}
@@ -0,0 +1,500 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// forEachStorage :
+// Runs test against empty in-memory and SQLite repositories, then against empty PostgreSQL ones when TEST_DB is set, so
+// every implementation is held to the same behaviour.
+func forEachStorage(t *testing.T, test func(t *testing.T, s repositories.Storage)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, repositories.NewMemoryStorage(repositories.NewMemoryStore()))
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		test(t, repositories.NewSqliteStorage(openTestSqlite(t)))
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		test(t, repositories.NewPostgresStorage(openTestDatabase(t)))
+	})
+}
+
+// expectError :
+// Fails the test unless err carries the code of expected.
+func expectError(t *testing.T, err error, expected *server_errors.Error) {
+	t.Helper()
+
+	if !errors.Is(err, expected) {
+		t.Fatalf("Expected %s, got %v", expected.Code, err)
+	}
+}
+
+// mustAddLanguage :
+// Adds a language and returns its id, failing the test if it cannot.
+func mustAddLanguage(t *testing.T, s repositories.Storage, name string) int {
+	t.Helper()
+
+	id, err := s.Languages.AddLanguage(context.Background(), models.Language{Name: name})
+	if err != nil {
+		t.Fatalf("Unexpected error adding %q: %v", name, err)
+	}
+
+	return id
+}
+
+// mustAddNewsOutlet :
+// Adds a news outlet written in the provided language and returns its id, failing the test if it cannot.
+func mustAddNewsOutlet(t *testing.T, s repositories.Storage, name string, language string) int {
+	t.Helper()
+
+	id, err := s.NewsOutlets.AddNewsOutlet(context.Background(), models.NewsOutlet{
+		Name:         name,
+		QueryUrl:     "https://" + name + ".example/search?q=",
+		HtmlSelector: "article a",
+		Language:     language,
+		Credibility:  70,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error adding %q: %v", name, err)
+	}
+
+	return id
+}
+
+// Languages -----------------------------------------------------------------------------------------------------------
+
+func TestLanguageRepository_AddAndGet(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s repositories.Storage) {
+		ctx := context.Background()
+
+		languages, err := s.Languages.GetLanguages(ctx)
+		if err != nil || languages == nil || len(languages) != 0 {
+			t.Fatalf("Expected an empty list, got %v, %v", languages, err)
+		}
+
+		english := mustAddLanguage(t, s, "English")
+		french := mustAddLanguage(t, s, "french")
+
+		_, err = s.Languages.AddLanguage(ctx, models.Language{Name: "ENGLISH"})
+		expectError(t, err, server_errors.ErrLanguageAlreadyExists)
+
+		language, err := s.Languages.GetLanguageByName(ctx, "eNgLiSh")
+		if err != nil || language.Id != english || language.Name != "english" {
+			t.Errorf("Expected the lower-cased language %d, got %+v, %v", english, language, err)
+		}
+
+		language, err = s.Languages.GetLanguageById(ctx, french)
+		if err != nil || language.Name != "french" {
+			t.Errorf("Expected french, got %+v, %v", language, err)
+		}
+
+		languages, err = s.Languages.GetLanguages(ctx)
+		if err != nil || len(languages) != 2 || languages[0].Id != english || languages[1].Id != french {
+			t.Errorf("Expected both languages by id, got %+v, %v", languages, err)
+		}
+
+		_, err = s.Languages.GetLanguageById(ctx, french+100)
+		expectError(t, err, server_errors.ErrLanguageNotFound)
+
+		_, err = s.Languages.GetLanguageByName(ctx, "german")
+		expectError(t, err, server_errors.ErrLanguageNotFound)
+	})
+}
+
+func TestLanguageRepository_Rename(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s repositories.Storage) {
+		ctx := context.Background()
+		english := mustAddLanguage(t, s, "english")
+		mustAddLanguage(t, s, "french")
+		mustAddNewsOutlet(t, s, "reuters", "english")
+
+		if err := s.Languages.RenameLanguage(ctx, english, "English"); err != nil {
+			t.Fatalf("Expected a language to be renamed to its own name, got %v", err)
+		}
+
+		if err := s.Languages.RenameLanguage(ctx, english, "British"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		language, err := s.Languages.GetLanguageById(ctx, english)
+		if err != nil || language.Name != "british" {
+			t.Errorf("Expected the lower-cased new name, got %+v, %v", language, err)
+		}
+
+		newsOutlet, err := s.NewsOutlets.GetNewsOutletByName(ctx, "reuters")
+		if err != nil || newsOutlet.Language != "british" {
+			t.Errorf("Expected the news outlets to follow the renamed language, got %+v, %v", newsOutlet, err)
+		}
+
+		expectError(t, s.Languages.RenameLanguage(ctx, english, "FRENCH"), server_errors.ErrLanguageAlreadyExists)
+		expectError(t, s.Languages.RenameLanguage(ctx, english+100, "german"), server_errors.ErrLanguageNotFound)
+	})
+}
+
+func TestLanguageRepository_Delete(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s repositories.Storage) {
+		ctx := context.Background()
+		english := mustAddLanguage(t, s, "english")
+		french := mustAddLanguage(t, s, "french")
+		mustAddNewsOutlet(t, s, "reuters", "english")
+		mustAddNewsOutlet(t, s, "le monde", "french")
+
+		expectError(t, s.Languages.DeleteLanguage(ctx, english, false), server_errors.ErrLanguageInUse)
+
+		if _, err := s.Languages.GetLanguageById(ctx, english); err != nil {
+			t.Fatalf("Expected the language in use to be kept, got %v", err)
+		}
+
+		if err := s.Languages.DeleteLanguage(ctx, english, true); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		_, err := s.Languages.GetLanguageById(ctx, english)
+		expectError(t, err, server_errors.ErrLanguageNotFound)
+
+		newsOutlets, err := s.NewsOutlets.GetNewsOutlets(ctx)
+		if err != nil || len(newsOutlets) != 1 || newsOutlets[0].Name != "le monde" {
+			t.Errorf("Expected only the news outlets of the language to be removed, got %+v, %v", newsOutlets, err)
+		}
+
+		expectError(t, s.Languages.DeleteLanguage(ctx, english, true), server_errors.ErrLanguageNotFound)
+
+		if _, err = s.NewsOutlets.GetNewsOutletByName(ctx, "le monde"); err != nil {
+			t.Fatal(err)
+		}
+		if err = s.NewsOutlets.DeleteNewsOutlet(ctx, newsOutlets[0].Id); err != nil {
+			t.Fatal(err)
+		}
+		if err = s.Languages.DeleteLanguage(ctx, french, false); err != nil {
+			t.Errorf("Expected a language no longer in use to be removed, got %v", err)
+		}
+	})
+}
+
+// News outlets --------------------------------------------------------------------------------------------------------
+
+func TestNewsOutletRepository_AddAndGet(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s repositories.Storage) {
+		ctx := context.Background()
+		mustAddLanguage(t, s, "english")
+
+		_, err := s.NewsOutlets.AddNewsOutlet(ctx, models.NewsOutlet{Name: "reuters", Language: "klingon"})
+		expectError(t, err, server_errors.ErrNewsOutletUnknownLanguage)
+
+		added := models.NewsOutlet{
+			Name:         "Reuters",
+			QueryUrl:     "https://www.reuters.com/site-search/?query=",
+			HtmlSelector: "li a",
+			Language:     "English",
+			Credibility:  90,
+			CrawlDelayMs: 500,
+		}
+		id, err := s.NewsOutlets.AddNewsOutlet(ctx, added)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := added
+		expected.Id, expected.Name, expected.Language = id, "reuters", "english"
+
+		newsOutlet, err := s.NewsOutlets.GetNewsOutletById(ctx, id)
+		if err != nil || *newsOutlet != expected {
+			t.Errorf("Expected %+v, got %+v, %v", expected, newsOutlet, err)
+		}
+
+		newsOutlet, err = s.NewsOutlets.GetNewsOutletByName(ctx, "REUTERS")
+		if err != nil || *newsOutlet != expected {
+			t.Errorf("Expected %+v, got %+v, %v", expected, newsOutlet, err)
+		}
+
+		_, err = s.NewsOutlets.AddNewsOutlet(ctx, models.NewsOutlet{Name: "reuters", Language: "english"})
+		expectError(t, err, server_errors.ErrNewsOutletAlreadyExists)
+
+		bbc := mustAddNewsOutlet(t, s, "bbc", "english")
+
+		newsOutlets, err := s.NewsOutlets.GetNewsOutlets(ctx)
+		if err != nil || len(newsOutlets) != 2 || newsOutlets[0] != expected || newsOutlets[1].Id != bbc {
+			t.Errorf("Expected both news outlets by id, got %+v, %v", newsOutlets, err)
+		}
+
+		_, err = s.NewsOutlets.GetNewsOutletById(ctx, bbc+100)
+		expectError(t, err, server_errors.ErrNewsOutletNotFound)
+
+		_, err = s.NewsOutlets.GetNewsOutletByName(ctx, "cnn")
+		expectError(t, err, server_errors.ErrNewsOutletNotFound)
+	})
+}
+
+func TestNewsOutletRepository_UpdateAndDelete(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s repositories.Storage) {
+		ctx := context.Background()
+		mustAddLanguage(t, s, "english")
+		mustAddLanguage(t, s, "french")
+		reuters := mustAddNewsOutlet(t, s, "reuters", "english")
+		mustAddNewsOutlet(t, s, "bbc", "english")
+
+		updated := models.NewsOutlet{
+			Name:         "Reuters France",
+			QueryUrl:     "https://fr.reuters.com/search?q=",
+			HtmlSelector: "h3 a",
+			Language:     "french",
+			Credibility:  85,
+		}
+		if err := s.NewsOutlets.UpdateNewsOutlet(ctx, reuters, updated); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := updated
+		expected.Id, expected.Name = reuters, "reuters france"
+
+		newsOutlet, err := s.NewsOutlets.GetNewsOutletById(ctx, reuters)
+		if err != nil || *newsOutlet != expected {
+			t.Errorf("Expected %+v, got %+v, %v", expected, newsOutlet, err)
+		}
+
+		updated.Name = "BBC"
+		expectError(t, s.NewsOutlets.UpdateNewsOutlet(ctx, reuters, updated), server_errors.ErrNewsOutletAlreadyExists)
+
+		updated.Name, updated.Language = "reuters", "klingon"
+		expectError(t, s.NewsOutlets.UpdateNewsOutlet(ctx, reuters, updated), server_errors.ErrNewsOutletUnknownLanguage)
+
+		updated.Language = "english"
+		expectError(t, s.NewsOutlets.UpdateNewsOutlet(ctx, reuters+100, updated), server_errors.ErrNewsOutletNotFound)
+
+		if err = s.NewsOutlets.DeleteNewsOutlet(ctx, reuters); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		_, err = s.NewsOutlets.GetNewsOutletById(ctx, reuters)
+		expectError(t, err, server_errors.ErrNewsOutletNotFound)
+		expectError(t, s.NewsOutlets.DeleteNewsOutlet(ctx, reuters), server_errors.ErrNewsOutletNotFound)
+	})
+}
+
+// API keys ------------------------------------------------------------------------------------------------------------
+
+func TestApiKeyRepository(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s repositories.Storage) {
+		ctx := context.Background()
+		hash := "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+
+		apiKey, err := s.ApiKeys.AddApiKey(ctx, "ci", models.RoleCrawl, "aletheia_a1b2", hash)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if apiKey.Name != "ci" || apiKey.Role != models.RoleCrawl || apiKey.Prefix != "aletheia_a1b2" ||
+			apiKey.CreatedAt.IsZero() || apiKey.LastUsedAt != nil || apiKey.RevokedAt != nil {
+			t.Errorf("Unexpected API key stored: %+v", apiKey)
+		}
+
+		_, err = s.ApiKeys.AddApiKey(ctx, "copy", models.RoleRead, "aletheia_a1b2", hash)
+		expectError(t, err, server_errors.ErrApiKeyNotSaved)
+
+		found, err := s.ApiKeys.GetApiKeyByHash(ctx, hash)
+		if err != nil || found.Id != apiKey.Id {
+			t.Fatalf("Expected API key %d, got %+v, %v", apiKey.Id, found, err)
+		}
+
+		apiKeys, err := s.ApiKeys.GetApiKeys(ctx)
+		if err != nil || len(apiKeys) != 1 || apiKeys[0].LastUsedAt == nil {
+			t.Errorf("Expected the use of the key to be recorded, got %+v, %v", apiKeys, err)
+		}
+
+		_, err = s.ApiKeys.GetApiKeyByHash(ctx, "unknown")
+		expectError(t, err, server_errors.ErrApiKeyNotFound)
+
+		if err = s.ApiKeys.RevokeApiKey(ctx, apiKey.Id); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err = s.ApiKeys.RevokeApiKey(ctx, apiKey.Id); err != nil {
+			t.Errorf("Expected revoking a revoked key to change nothing, got %v", err)
+		}
+
+		_, err = s.ApiKeys.GetApiKeyByHash(ctx, hash)
+		expectError(t, err, server_errors.ErrApiKeyNotFound)
+
+		apiKeys, err = s.ApiKeys.GetApiKeys(ctx)
+		if err != nil || len(apiKeys) != 1 || apiKeys[0].RevokedAt == nil {
+			t.Errorf("Expected the revoked key to still be listed, got %+v, %v", apiKeys, err)
+		}
+
+		expectError(t, s.ApiKeys.RevokeApiKey(ctx, apiKey.Id+100), server_errors.ErrApiKeyNotFound)
+	})
+}
+
+// Quotas --------------------------------------------------------------------------------------------------------------
+
+func TestQuotaRepository(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s repositories.Storage) {
+		ctx := context.Background()
+		today := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+
+		for expected := 1; expected <= 2; expected++ {
+			used, ok, err := s.Quotas.ConsumeQuota(ctx, "ip:192.0.2.1", today, 2)
+			if err != nil || !ok || used != expected {
+				t.Fatalf("Expected crawl %d to be counted, got %d, %v, %v", expected, used, ok, err)
+			}
+		}
+
+		used, ok, err := s.Quotas.ConsumeQuota(ctx, "ip:192.0.2.1", today, 2)
+		if err != nil || ok || used != 2 {
+			t.Errorf("Expected the crawl past the limit to be refused, got %d, %v, %v", used, ok, err)
+		}
+
+		if used, ok, err = s.Quotas.ConsumeQuota(ctx, "ip:192.0.2.1", today.AddDate(0, 0, 1), 2); err != nil || !ok || used != 1 {
+			t.Errorf("Expected the quota to start over the next day, got %d, %v, %v", used, ok, err)
+		}
+
+		if used, ok, err = s.Quotas.ConsumeQuota(ctx, "key:1", today, 2); err != nil || !ok || used != 1 {
+			t.Errorf("Expected every client to have its own quota, got %d, %v, %v", used, ok, err)
+		}
+
+		if used, err = s.Quotas.RefundQuota(ctx, "ip:192.0.2.1", today); err != nil || used != 1 {
+			t.Errorf("Expected the refunded crawl to be given back, got %d, %v", used, err)
+		}
+
+		if used, ok, err = s.Quotas.ConsumeQuota(ctx, "ip:192.0.2.1", today, 2); err != nil || !ok || used != 2 {
+			t.Errorf("Expected the refunded crawl to be available again, got %d, %v, %v", used, ok, err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if used, err = s.Quotas.RefundQuota(ctx, "key:1", today); err != nil || used != 0 {
+				t.Errorf("Expected the count never to go below zero, got %d, %v", used, err)
+			}
+		}
+
+		if used, err = s.Quotas.RefundQuota(ctx, "key:2", today); err != nil || used != 0 {
+			t.Errorf("Expected nothing to be given back to a client without crawls, got %d, %v", used, err)
+		}
+	})
+}
+
+// Crawl runs ----------------------------------------------------------------------------------------------------------
+
+// crawlResult :
+// Returns a crawl result started at the provided time, with two crawlers listed out of order.
+func crawlResult(query string, startedAt time.Time) models.CrawlResult {
+	finishedAt := startedAt.Add(time.Second)
+
+	return models.CrawlResult{
+		Query:        query,
+		PagesToVisit: 2,
+		StartedAt:    startedAt,
+		FinishedAt:   finishedAt,
+		DurationMs:   1000,
+		Crawlers: []models.CrawlerResult{
+			{
+				CrawlerId:  2,
+				NewsOutlet: "bbc",
+				Query:      query,
+				Status:     server_errors.CrawlerFailed,
+				Error:      "search page unreachable",
+				StartedAt:  startedAt,
+				FinishedAt: finishedAt,
+			},
+			{
+				CrawlerId:   1,
+				NewsOutlet:  "reuters",
+				Query:       query,
+				Status:      server_errors.CrawlerSucceeded,
+				VisitedUrls: []string{"https://www.reuters.com/a", "https://www.reuters.com/b"},
+				Articles: []models.Article{
+					{Url: "https://www.reuters.com/a", Title: "A", FetchedAt: startedAt, Text: "first", RawHtml: "<p>first</p>"},
+					{Url: "https://www.reuters.com/b", Title: "B", FetchedAt: startedAt, Text: "second"},
+				},
+				Failures:   []models.FetchFailure{{Url: "https://www.reuters.com/c", Error: "timeout"}},
+				StartedAt:  startedAt,
+				FinishedAt: finishedAt,
+			},
+		},
+	}
+}
+
+func TestCrawlRunRepository(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s repositories.Storage) {
+		ctx := context.Background()
+		startedAt := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+
+		older, err := s.CrawlRuns.SaveCrawlRun(ctx, crawlResult("older", startedAt))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		newer, err := s.CrawlRuns.SaveCrawlRun(ctx, crawlResult("newer", startedAt.Add(time.Hour)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		run, err := s.CrawlRuns.GetCrawlRunById(ctx, older)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if run.RunId != older || run.Query != "older" || len(run.Crawlers) != 2 || run.Verdict != nil {
+			t.Fatalf("Unexpected crawl run: %+v", run)
+		}
+
+		failed, succeeded := run.Crawlers[1], run.Crawlers[0]
+		if succeeded.CrawlerId != 1 || failed.CrawlerId != 2 {
+			t.Fatalf("Expected the crawlers by id, got %d then %d", run.Crawlers[0].CrawlerId, run.Crawlers[1].CrawlerId)
+		}
+
+		if failed.Error != "search page unreachable" || failed.Articles == nil || len(failed.Articles) != 0 ||
+			len(failed.VisitedUrls) != 0 || failed.Failures == nil || len(failed.Failures) != 0 {
+			t.Errorf("Unexpected failed crawler: %+v", failed)
+		}
+
+		if len(succeeded.VisitedUrls) != 2 || len(succeeded.Failures) != 1 || len(succeeded.Articles) != 2 {
+			t.Fatalf("Unexpected succeeded crawler: %+v", succeeded)
+		}
+
+		first := succeeded.Articles[0]
+		if first.Title != "A" || first.RawHtml != "" || len(first.ContentHash) != 64 {
+			t.Errorf("Expected the article to be stored hashed and without its page, got %+v", first)
+		}
+
+		verdict := models.FactCheckResult{
+			Post:     models.Post{Url: "https://example.com/post", Title: "Post", Content: "content"},
+			Prompt:   "prompt",
+			Verdict:  models.VerdictLikelyTrue,
+			Score:    0.8,
+			Analyses: []models.ArticleAnalysis{{NewsOutlet: "reuters", Credibility: 90, Stance: models.StanceSupports}},
+		}
+		if err = s.CrawlRuns.SaveVerdict(ctx, older, verdict); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		run, err = s.CrawlRuns.GetCrawlRunById(ctx, older)
+		if err != nil || run.Verdict == nil || run.Verdict.Verdict != models.VerdictLikelyTrue ||
+			run.Verdict.Post != verdict.Post || len(run.Verdict.Analyses) != 1 {
+			t.Errorf("Expected the verdict along with the run, got %+v, %v", run, err)
+		}
+
+		expectError(t, s.CrawlRuns.SaveVerdict(ctx, newer+100, verdict), server_errors.ErrVerdictNotSaved)
+
+		runs, err := s.CrawlRuns.GetCrawlRuns(ctx, 10, 0)
+		if err != nil || len(runs) != 2 || runs[0].Id != newer || runs[1].Id != older {
+			t.Fatalf("Expected the newest run first, got %+v, %v", runs, err)
+		}
+
+		if runs[1].Crawlers != 2 || runs[1].Articles != 2 || runs[1].Verdict != models.VerdictLikelyTrue || runs[0].Verdict != "" {
+			t.Errorf("Unexpected summaries: %+v", runs)
+		}
+
+		runs, err = s.CrawlRuns.GetCrawlRuns(ctx, 1, 1)
+		if err != nil || len(runs) != 1 || runs[0].Id != older {
+			t.Errorf("Expected the second page to hold the older run, got %+v, %v", runs, err)
+		}
+
+		_, err = s.CrawlRuns.GetCrawlRunById(ctx, newer+100)
+		expectError(t, err, server_errors.ErrCrawlRunNotFound)
+	})
+}
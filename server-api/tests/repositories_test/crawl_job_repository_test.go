@@ -0,0 +1,96 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestCrawlJob(id string) models.CrawlJob {
+	return models.CrawlJob{
+		Id:        id,
+		Status:    server_errors.CrawlJobQueued,
+		CreatedAt: time.Now(),
+		Crawlers: []models.CrawlerProgress{
+			{CrawlerId: 1, NewsOutlet: "reuters", Status: server_errors.CrawlerReady},
+			{CrawlerId: 2, NewsOutlet: "bbc", Status: server_errors.CrawlerReady},
+		},
+	}
+}
+
+func TestCrawlJobRepository_GetMissingJob(t *testing.T) {
+	repository := repositories.NewCrawlJobRepository()
+
+	_, err := repository.GetCrawlJob("missing")
+	if err == nil || err.Error() != server_errors.CrawlJobNotFound {
+		t.Errorf("Expected %q, got %v", server_errors.CrawlJobNotFound, err)
+	}
+}
+
+func TestCrawlJobRepository_CrawlerProgress(t *testing.T) {
+	repository := repositories.NewCrawlJobRepository()
+	_ = repository.AddCrawlJob(newTestCrawlJob("job"), func() {}, nil)
+
+	repository.SetCrawlJobStatus("job", server_errors.CrawlJobRunning)
+	repository.SetCrawlerStatus("job", 2, server_errors.CrawlerRunning)
+
+	job, err := repository.GetCrawlJob("job")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if job.Status != server_errors.CrawlJobRunning {
+		t.Errorf("Expected job status %q, got %q", server_errors.CrawlJobRunning, job.Status)
+	}
+
+	if job.Crawlers[0].Status != server_errors.CrawlerReady || job.Crawlers[1].Status != server_errors.CrawlerRunning {
+		t.Errorf("Unexpected crawlers progress: %+v", job.Crawlers)
+	}
+
+	// Snapshots must not be affected by later updates
+	repository.SetCrawlerStatus("job", 1, server_errors.CrawlerSucceeded)
+	if job.Crawlers[0].Status != server_errors.CrawlerReady {
+		t.Errorf("Snapshot was modified by a later update")
+	}
+}
+
+func TestCrawlJobRepository_Cancel(t *testing.T) {
+	repository := repositories.NewCrawlJobRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	_ = repository.AddCrawlJob(newTestCrawlJob("job"), cancel, nil)
+
+	if _, err := repository.CancelCrawlJob("job"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if ctx.Err() == nil {
+		t.Errorf("Expected the job context to be cancelled")
+	}
+
+	if _, err := repository.CancelCrawlJob("missing"); err == nil || err.Error() != server_errors.CrawlJobNotFound {
+		t.Errorf("Expected %q, got %v", server_errors.CrawlJobNotFound, err)
+	}
+}
+
+func TestCrawlJobRepository_CancelFinishedJob(t *testing.T) {
+	repository := repositories.NewCrawlJobRepository()
+	_ = repository.AddCrawlJob(newTestCrawlJob("job"), func() {}, nil)
+	repository.FinishCrawlJob("job", server_errors.CrawlJobSucceeded, models.CrawlResult{Query: "test"})
+
+	job, err := repository.GetCrawlJob("job")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if job.Status != server_errors.CrawlJobSucceeded || job.FinishedAt == nil || job.Result == nil {
+		t.Errorf("Expected a finished job with a result, got %+v", job)
+	}
+
+	_, err = repository.CancelCrawlJob("job")
+	if err == nil || err.Error() != server_errors.CrawlJobAlreadyFinished {
+		t.Errorf("Expected %q, got %v", server_errors.CrawlJobAlreadyFinished, err)
+	}
+}
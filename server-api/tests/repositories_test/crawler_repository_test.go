@@ -0,0 +1,170 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/metrics"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newOutletServer :
+// Serves a search results page linking to the provided number of articles. Every article takes a while to be served
+// and the one at index failing answers with a 404.
+func newOutletServer(t *testing.T, articles int, failing int, running *int32, peak *int32) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		var links strings.Builder
+		for i := 0; i < articles; i++ {
+			_, _ = fmt.Fprintf(&links, `<a class="result" href="/article/%d">Article %d</a>`, i, i)
+		}
+		_, _ = fmt.Fprintf(w, "<html><body>%s</body></html>", links.String())
+	})
+	mux.HandleFunc("/article/", func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(running, 1)
+		defer atomic.AddInt32(running, -1)
+		for {
+			seen := atomic.LoadInt32(peak)
+			if current <= seen || atomic.CompareAndSwapInt32(peak, seen, current) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+
+		if r.URL.Path == fmt.Sprintf("/article/%d", failing) {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprintf(w, "<html><body><article><h1>%s</h1><p>Body of %s.</p></article></body></html>", r.URL.Path, r.URL.Path)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newTestCrawler(server *httptest.Server, pages int, fetcher *repositories.Fetcher) repositories.CrawlerRepository {
+	crawler := repositories.NewCrawlerRepository(models.Crawler{
+		Id:           1,
+		PagesToVisit: pages,
+		Query:        server.URL + "/search",
+		HtmlSelector: "a.result",
+		Status:       server_errors.CrawlerReady,
+		PagesBodies:  make([]string, 0),
+	}, "test outlet")
+	crawler.Fetcher = fetcher
+
+	return crawler
+}
+
+func TestCrawler_FetchesArticlesConcurrently(t *testing.T) {
+	var running, peak int32
+	server := newOutletServer(t, 8, 3, &running, &peak)
+
+	crawler := newTestCrawler(server, 8, repositories.NewFetcher(nil, repositories.FetcherConfig{
+		RequestsPerSecond: 1000,
+		Burst:             100,
+		Workers:           10,
+		OutletConcurrency: 4,
+	}))
+	crawler.Crawl(context.Background())
+
+	if crawler.Result.Status != server_errors.CrawlerSucceeded {
+		t.Fatalf("Expected %q, got %q (%s)", server_errors.CrawlerSucceeded, crawler.Result.Status, crawler.Result.Error)
+	}
+
+	if got := atomic.LoadInt32(&peak); got < 2 || got > 4 {
+		t.Errorf("Expected between 2 and 4 article pages fetched at once, got %d", got)
+	}
+
+	if len(crawler.Result.VisitedUrls) != 8 {
+		t.Errorf("Expected 8 visited urls, got %d", len(crawler.Result.VisitedUrls))
+	}
+
+	if len(crawler.Result.Articles) != 7 || len(crawler.Crawler.PagesBodies) != 7 {
+		t.Fatalf("Expected 7 articles and page bodies, got %d and %d", len(crawler.Result.Articles), len(crawler.Crawler.PagesBodies))
+	}
+
+	// The articles keep the order of the links, whatever the order they were fetched in
+	expected := []int{0, 1, 2, 4, 5, 6, 7}
+	for i, article := range crawler.Result.Articles {
+		if want := fmt.Sprintf("%s/article/%d", server.URL, expected[i]); article.Url != want {
+			t.Errorf("Expected article %d to be %s, got %s", i, want, article.Url)
+		}
+	}
+
+	if len(crawler.Result.Failures) != 1 || crawler.Result.Failures[0].Url != server.URL+"/article/3" {
+		t.Errorf("Expected article 3 to be reported as failed, got %+v", crawler.Result.Failures)
+	}
+}
+
+func TestCrawler_WorkersBoundRequestsAcrossCrawlers(t *testing.T) {
+	var running, peak int32
+	server := newOutletServer(t, 4, -1, &running, &peak)
+
+	fetcher := repositories.NewFetcher(nil, repositories.FetcherConfig{
+		RequestsPerSecond: 1000,
+		Burst:             100,
+		Workers:           2,
+		OutletConcurrency: 4,
+	})
+
+	crawlers := []repositories.CrawlerRepository{
+		newTestCrawler(server, 4, fetcher),
+		newTestCrawler(server, 4, fetcher),
+	}
+
+	done := make(chan struct{})
+	for i := range crawlers {
+		go func(cr *repositories.CrawlerRepository) {
+			cr.Crawl(context.Background())
+			done <- struct{}{}
+		}(&crawlers[i])
+	}
+	<-done
+	<-done
+
+	if got := atomic.LoadInt32(&peak); got > 2 {
+		t.Errorf("Expected 2 requests in flight at most, got %d", got)
+	}
+
+	for _, cr := range crawlers {
+		if len(cr.Result.Articles) != 4 {
+			t.Errorf("Expected 4 articles, got %d (%s)", len(cr.Result.Articles), cr.Result.Error)
+		}
+	}
+}
+
+func TestCrawler_RecordsMetrics(t *testing.T) {
+	var running, peak int32
+	server := newOutletServer(t, 3, 1, &running, &peak)
+
+	crawler := newTestCrawler(server, 3, repositories.NewFetcher(nil, repositories.FetcherConfig{
+		RequestsPerSecond: 1000,
+		Burst:             100,
+		Workers:           10,
+		OutletConcurrency: 3,
+	}))
+	crawler.NewsOutlet = "metrics outlet"
+	crawler.Crawl(context.Background())
+
+	if got := testutil.ToFloat64(metrics.Crawls.WithLabelValues("metrics outlet", server_errors.CrawlerSucceeded)); got != 1 {
+		t.Errorf("Expected 1 succeeded crawl, got %v", got)
+	}
+
+	if got := testutil.ToFloat64(metrics.Crawls.WithLabelValues("metrics outlet", server_errors.CrawlerFailed)); got != 0 {
+		t.Errorf("Expected no failed crawl, got %v", got)
+	}
+}
@@ -0,0 +1,88 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/config"
+	"aletheia-server/src/db"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// testSchema : schema holding the tables of the tests, dropped once they are done
+const testSchema = "aletheia_test"
+
+// openTestDatabase :
+// Connects to the database configured through the DB_* environment variables, inside a schema of its own migrated to
+// the latest version. The tests are skipped unless TEST_DB is true, as they need a running PostgreSQL.
+func openTestDatabase(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	if enabled, _ := strconv.ParseBool(os.Getenv("TEST_DB")); !enabled {
+		tb.Skip("TEST_DB is not set, skipping the tests needing a database")
+	}
+
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	settings := cfg.DBConfig()
+
+	connection, err := sql.Open("postgres", fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable search_path=%s",
+		settings.Host, settings.Port, settings.User, settings.Password, settings.DBName, testSchema))
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err = connection.ExecContext(ctx, "DROP SCHEMA IF EXISTS "+testSchema+" CASCADE"); err != nil {
+		tb.Fatal(err)
+	}
+	if _, err = connection.ExecContext(ctx, "CREATE SCHEMA "+testSchema); err != nil {
+		tb.Fatal(err)
+	}
+
+	tb.Cleanup(func() {
+		_, _ = connection.ExecContext(context.Background(), "DROP SCHEMA IF EXISTS "+testSchema+" CASCADE")
+		_ = connection.Close()
+	})
+
+	migrate(tb, connection, db.PostgresDriver)
+	return connection
+}
+
+// openTestSqlite :
+// Opens a SQLite file of its own, removed once the test is done, migrated to the latest version.
+func openTestSqlite(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	connection, err := db.Connect(context.Background(), db.Config{
+		Driver: db.SqliteDriver,
+		Path:   filepath.Join(tb.TempDir(), "aletheia.db"),
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { _ = connection.Close() })
+
+	migrate(tb, connection, db.SqliteDriver)
+	return connection
+}
+
+// migrate :
+// Applies every migration of the driver, failing the test if any cannot be applied.
+func migrate(tb testing.TB, connection *sql.DB, driver string) {
+	tb.Helper()
+
+	migrations, err := db.Migrations(driver)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if _, err = db.NewMigrator(connection, driver, migrations).Up(context.Background()); err != nil {
+		tb.Fatal(err)
+	}
+}
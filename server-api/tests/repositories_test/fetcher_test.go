@@ -0,0 +1,288 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRobotsServer(t *testing.T, robots string, robotsRequests *int32, userAgents chan<- string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			atomic.AddInt32(robotsRequests, 1)
+			_, _ = w.Write([]byte(robots))
+			return
+		}
+
+		if userAgents != nil {
+			userAgents <- r.UserAgent()
+		}
+		_, _ = w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFetcher_RespectsRobots(t *testing.T) {
+	var robotsRequests int32
+	robots := "User-agent: TestBot\nDisallow: /private\n\nUser-agent: *\nDisallow: /"
+	server := newRobotsServer(t, robots, &robotsRequests, nil)
+
+	fetcher := repositories.NewFetcher(nil, repositories.FetcherConfig{
+		UserAgent:         "TestBot/2.0",
+		RequestsPerSecond: 1000,
+		Burst:             10,
+	})
+
+	if _, err := fetcher.Get(context.Background(), server.URL+"/news/article", 0); err != nil {
+		t.Fatalf("Expected allowed page to be fetched, got %v", err)
+	}
+
+	_, err := fetcher.Get(context.Background(), server.URL+"/private/page", 0)
+	if err == nil || !strings.HasPrefix(err.Error(), server_errors.CrawlerRobotsDisallowed) {
+		t.Errorf("Expected %q error, got %v", server_errors.CrawlerRobotsDisallowed, err)
+	}
+
+	if got := atomic.LoadInt32(&robotsRequests); got != 1 {
+		t.Errorf("Expected robots.txt to be fetched once and cached, got %d requests", got)
+	}
+}
+
+func TestFetcher_SendsUserAgent(t *testing.T) {
+	var robotsRequests int32
+	userAgents := make(chan string, 1)
+	server := newRobotsServer(t, "", &robotsRequests, userAgents)
+
+	fetcher := repositories.NewFetcher(nil, repositories.FetcherConfig{UserAgent: "TestBot/2.0"})
+
+	if _, err := fetcher.Get(context.Background(), server.URL+"/article", 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := <-userAgents; got != "TestBot/2.0" {
+		t.Errorf("Expected User-Agent %q, got %q", "TestBot/2.0", got)
+	}
+}
+
+func TestFetcher_ThrottlesPerHost(t *testing.T) {
+	var robotsRequests int32
+	server := newRobotsServer(t, "", &robotsRequests, nil)
+
+	fetcher := repositories.NewFetcher(nil, repositories.FetcherConfig{RequestsPerSecond: 1000, Burst: 10})
+	crawlDelay := 100 * time.Millisecond
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := fetcher.Get(context.Background(), server.URL+"/article", crawlDelay); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// The first request goes right away, the next two wait for the crawl delay each
+	if elapsed := time.Since(start); elapsed < 2*crawlDelay {
+		t.Errorf("Expected the crawl delay to space the requests, took %v", elapsed)
+	}
+}
+
+func TestFetcher_WaitAbortsOnCancel(t *testing.T) {
+	var robotsRequests int32
+	server := newRobotsServer(t, "", &robotsRequests, nil)
+
+	fetcher := repositories.NewFetcher(nil, repositories.FetcherConfig{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The robots.txt goes right away, the page then waits for the crawl delay
+	_, err := fetcher.Get(ctx, server.URL+"/article", time.Hour)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the wait to be aborted by the context, got %v", err)
+	}
+
+	if got := atomic.LoadInt32(&robotsRequests); got != 1 {
+		t.Errorf("Expected the robots.txt to be fetched before waiting, got %d requests", got)
+	}
+}
+
+func TestFetcher_CancelledWaitGivesItsTurnBack(t *testing.T) {
+	var robotsRequests int32
+	server := newRobotsServer(t, "", &robotsRequests, nil)
+
+	fetcher := repositories.NewFetcher(nil, repositories.FetcherConfig{})
+	crawlDelay := 300 * time.Millisecond
+
+	if _, err := fetcher.Get(context.Background(), server.URL+"/article", crawlDelay); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := fetcher.Get(ctx, server.URL+"/article", crawlDelay); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the wait to be aborted by the context, got %v", err)
+	}
+
+	// Had the cancelled request kept its turn, this one would wait for two crawl delays
+	start := time.Now()
+	if _, err := fetcher.Get(context.Background(), server.URL+"/article", crawlDelay); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > crawlDelay+crawlDelay/2 {
+		t.Errorf("Expected the cancelled request to give its turn back, waited %v", elapsed)
+	}
+}
+
+func TestFetcher_FetchesRobotsOncePerHost(t *testing.T) {
+	var robotsRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			atomic.AddInt32(&robotsRequests, 1)
+			time.Sleep(100 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	fetcher := repositories.NewFetcher(nil, repositories.FetcherConfig{RequestsPerSecond: 1000, Burst: 10})
+
+	errs := make(chan error, 5)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			_, err := fetcher.Get(context.Background(), server.URL+"/article", 0)
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&robotsRequests); got != 1 {
+		t.Errorf("Expected the crawlers to share a single robots.txt request, got %d", got)
+	}
+}
+
+func TestFetcher_CancelledRobotsFetchIsTakenOver(t *testing.T) {
+	var robotsRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" && atomic.AddInt32(&robotsRequests, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	fetcher := repositories.NewFetcher(nil, repositories.FetcherConfig{RequestsPerSecond: 1000, Burst: 10})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := fetcher.Get(ctx, server.URL+"/article", 0)
+		cancelled <- err
+	}()
+
+	// Waits for the robots.txt fetched by the crawl about to be cancelled
+	for atomic.LoadInt32(&robotsRequests) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := fetcher.Get(context.Background(), server.URL+"/article", 0); err != nil {
+		t.Errorf("Expected the robots.txt to be fetched again, got %v", err)
+	}
+
+	if err := <-cancelled; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the first crawl to be cancelled, got %v", err)
+	}
+
+	if got := atomic.LoadInt32(&robotsRequests); got != 2 {
+		t.Errorf("Expected the abandoned robots.txt to be fetched again, got %d requests", got)
+	}
+}
+
+// newRateLimitedServer :
+// Server answering the first request of every page with status and the provided Retry-After, then with 200, and
+// recording when each page request was received.
+func newRateLimitedServer(t *testing.T, status int, retryAfter string, received chan<- time.Time) *httptest.Server {
+	t.Helper()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		received <- time.Now()
+		if atomic.AddInt32(&requests, 1) == 1 {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFetcher_RetryHonoursRetryAfter(t *testing.T) {
+	received := make(chan time.Time, 2)
+	server := newRateLimitedServer(t, http.StatusTooManyRequests, "2", received)
+
+	client := repositories.NewHttpClient(repositories.HttpClientConfig{
+		MaxRetries:  2,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+	})
+	fetcher := repositories.NewFetcher(client, repositories.FetcherConfig{RequestsPerSecond: 1000, Burst: 10})
+
+	resp, err := fetcher.Get(context.Background(), server.URL+"/article", 0)
+	if err != nil || resp.StatusCode != http.StatusOK || resp.Attempts != 2 {
+		t.Fatalf("Expected a successful second attempt, got %+v: %v", resp, err)
+	}
+
+	first, retry := <-received, <-received
+	if gap := retry.Sub(first); gap < 2*time.Second {
+		t.Errorf("Expected the retry to wait for the Retry-After of 2s, came %v later", gap)
+	}
+}
+
+func TestFetcher_RetryWaitsForTheHost(t *testing.T) {
+	received := make(chan time.Time, 2)
+	server := newRateLimitedServer(t, http.StatusServiceUnavailable, "", received)
+
+	client := repositories.NewHttpClient(repositories.HttpClientConfig{
+		MaxRetries:  2,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	})
+	fetcher := repositories.NewFetcher(client, repositories.FetcherConfig{RequestsPerSecond: 1000, Burst: 10})
+	crawlDelay := 300 * time.Millisecond
+
+	if _, err := fetcher.Get(context.Background(), server.URL+"/article", crawlDelay); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The backoff alone would retry right away
+	first, retry := <-received, <-received
+	if gap := retry.Sub(first); gap < crawlDelay-10*time.Millisecond {
+		t.Errorf("Expected the retry to wait for the crawl delay, came %v later", gap)
+	}
+}
@@ -0,0 +1,161 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestHttpClient(maxRetries int) *repositories.HttpClient {
+	return repositories.NewHttpClient(repositories.HttpClientConfig{
+		Timeout:     100 * time.Millisecond,
+		MaxRetries:  maxRetries,
+		MaxBodySize: 64,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+	})
+}
+
+func TestHttpClient_RetriesServerErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	resp, err := newTestHttpClient(2).Get(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || string(resp.Body) != "ok" || resp.Attempts != 3 {
+		t.Errorf("Expected a successful third attempt, got status %d, body %q, %d attempts", resp.StatusCode, resp.Body, resp.Attempts)
+	}
+}
+
+func TestHttpClient_ReturnsLastServerErrorWhenOutOfAttempts(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	resp, err := newTestHttpClient(1).Get(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusServiceUnavailable || atomic.LoadInt32(&requests) != 2 {
+		t.Errorf("Expected 2 attempts ending with 503, got status %d after %d requests", resp.StatusCode, requests)
+	}
+}
+
+func TestHttpClient_DoesNotRetryClientErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	resp, err := newTestHttpClient(3).Get(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusNotFound || atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("Expected a single attempt ending with 404, got status %d after %d requests", resp.StatusCode, requests)
+	}
+}
+
+func TestHttpClient_RetriesTimeouts(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	resp, err := newTestHttpClient(1).Get(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("Expected the timed out attempt to be retried, got %v", err)
+	}
+
+	if resp.Attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", resp.Attempts)
+	}
+}
+
+func TestHttpClient_CapsBodySize(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(strings.Repeat("a", 65)))
+	}))
+	defer server.Close()
+
+	_, err := newTestHttpClient(2).Get(context.Background(), server.URL, nil)
+	if err == nil || !strings.Contains(err.Error(), server_errors.HttpBodyTooLarge) {
+		t.Fatalf("Expected %q error, got %v", server_errors.HttpBodyTooLarge, err)
+	}
+
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("Expected oversized bodies to never be retried, got %d requests", requests)
+	}
+}
+
+func TestHttpClient_StopsOnCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newTestHttpClient(5).Get(ctx, server.URL, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the request to be cancelled, got %v", err)
+	}
+}
+
+func TestHttpClient_CapsRetryAfter(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := newTestHttpClient(1).Get(context.Background(), server.URL, nil)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected a successful retry, got %+v: %v", resp, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the Retry-After to be capped at MaxBackoff, took %v", elapsed)
+	}
+}
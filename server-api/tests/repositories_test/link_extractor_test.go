@@ -0,0 +1,82 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/repositories"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const searchResultsPage = `
+<html>
+<body>
+	<nav><a href="/about">About</a></nav>
+	<div class="result"><a href="/2025/01/tax-cuts">Tax cuts</a></div>
+	<div class="result"><h2><a href="https://news.example.com/2025/01/budget#comments">Budget</a></h2></div>
+	<div class="result"><a href="/2025/01/tax-cuts">Tax cuts (duplicate)</a></div>
+	<div class="result"><a href="mailto:editor@example.com">Contact</a></div>
+	<div class="result"><a href="../archive/old-story">Old story</a></div>
+</body>
+</html>`
+
+func TestExtractLinks_ContainerSelector(t *testing.T) {
+	links, err := repositories.ExtractLinks(searchResultsPage, "div.result", "https://news.example.com/search/?q=tax")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"https://news.example.com/2025/01/tax-cuts",
+		"https://news.example.com/2025/01/budget",
+		"https://news.example.com/archive/old-story",
+	}
+
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %v, got %v", expected, links)
+	}
+}
+
+func TestExtractLinks_AnchorSelector(t *testing.T) {
+	links, err := repositories.ExtractLinks(searchResultsPage, "nav a", "https://news.example.com/search?q=tax")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"https://news.example.com/about"}
+
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %v, got %v", expected, links)
+	}
+}
+
+func TestExtractLinks_NoMatches(t *testing.T) {
+	links, err := repositories.ExtractLinks(searchResultsPage, "article.story", "https://news.example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(links) != 0 {
+		t.Errorf("Expected no links, got %v", links)
+	}
+}
+
+func TestExtractLinks_InvalidSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     string
+	}{
+		{"EmptySelector", "  ", server_errors.CrawlerEmptyHtmlSelector},
+		{"InvalidSelector", "div[", server_errors.CrawlerInvalidHtmlSelector},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repositories.ExtractLinks(searchResultsPage, tt.selector, "https://news.example.com")
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Expected %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
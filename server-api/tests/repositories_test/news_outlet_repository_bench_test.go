@@ -0,0 +1,126 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+// seedNewsOutlets :
+// Adds the news outlets numbered from from up to to, spread over a handful of languages.
+func seedNewsOutlets(b *testing.B, connection *sql.DB, from int, to int) {
+	b.Helper()
+	ctx := context.Background()
+
+	languageRepository := repositories.NewPostgresLanguageRepository(connection)
+	languages := []string{"english", "french", "german", "spanish", "italian"}
+	for _, name := range languages {
+		if _, err := languageRepository.GetLanguageByName(ctx, name); err == nil {
+			continue
+		}
+		if _, err := languageRepository.AddLanguage(ctx, models.Language{Name: name}); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	newsOutletRepository := repositories.NewPostgresNewsOutletRepository(connection, languageRepository)
+	for i := from; i < to; i++ {
+		_, err := newsOutletRepository.AddNewsOutlet(ctx, models.NewsOutlet{
+			Name:         fmt.Sprintf("outlet-%d", i),
+			QueryUrl:     fmt.Sprintf("https://outlet-%d.example/search?q=", i),
+			HtmlSelector: "article a",
+			Language:     languages[i%len(languages)],
+			Credibility:  50,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// getNewsOutletsPerRow :
+// Reads the news outlets the way the repository used to: a query for the outlets, then one for the language of each.
+func getNewsOutletsPerRow(ctx context.Context, connection *sql.DB, languageRepository repositories.LanguageRepository) ([]models.NewsOutlet, error) {
+	rows, err := connection.QueryContext(ctx,
+		"SELECT id, name, queryurl, htmlselector, languageid, credibility, crawldelayms FROM news_outlet ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+
+	type row struct {
+		outlet     models.NewsOutlet
+		languageId int
+	}
+	var read []row
+
+	for rows.Next() {
+		var r row
+		err = rows.Scan(&r.outlet.Id, &r.outlet.Name, &r.outlet.QueryUrl, &r.outlet.HtmlSelector, &r.languageId,
+			&r.outlet.Credibility, &r.outlet.CrawlDelayMs)
+		if err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		read = append(read, r)
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	newsOutlets := make([]models.NewsOutlet, 0, len(read))
+	for _, r := range read {
+		language, err := languageRepository.GetLanguageById(ctx, r.languageId)
+		if err != nil {
+			return nil, err
+		}
+		r.outlet.Language = language.Name
+		newsOutlets = append(newsOutlets, r.outlet)
+	}
+
+	return newsOutlets, nil
+}
+
+// BenchmarkGetNewsOutlets :
+// Compares reading every news outlet with a single JOIN against looking up the language of each outlet separately.
+//
+//	TEST_DB=true go test ./tests/repositories_test -run '^$' -bench GetNewsOutlets -benchmem
+func BenchmarkGetNewsOutlets(b *testing.B) {
+	connection := openTestDatabase(b)
+	ctx := context.Background()
+
+	languageRepository := repositories.NewPostgresLanguageRepository(connection)
+	newsOutletRepository := repositories.NewPostgresNewsOutletRepository(connection, languageRepository)
+
+	seeded := 0
+	for _, count := range []int{1000, 5000} {
+		seedNewsOutlets(b, connection, seeded, count)
+		seeded = count
+
+		b.Run(fmt.Sprintf("join/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				newsOutlets, err := newsOutletRepository.GetNewsOutlets(ctx)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(newsOutlets) != count {
+					b.Fatalf("Expected %d news outlets, got %d", count, len(newsOutlets))
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("per_row_lookup/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				newsOutlets, err := getNewsOutletsPerRow(ctx, connection, languageRepository)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(newsOutlets) != count {
+					b.Fatalf("Expected %d news outlets, got %d", count, len(newsOutlets))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPostRepository_RefusesNonPublicUrls(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte("<html><body><p>Internal page.</p></body></html>"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		url      string
+		expected error
+	}{
+		{"File", "file:///etc/passwd", server_errors.ErrHttpForbiddenScheme},
+		{"Gopher", "gopher://127.0.0.1:70/", server_errors.ErrHttpForbiddenScheme},
+		{"NoHost", "http:///posts", server_errors.ErrHttpForbiddenScheme},
+		{"Loopback", server.URL, server_errors.ErrHttpForbiddenAddress},
+		{"Localhost", strings.Replace(server.URL, "127.0.0.1", "localhost", 1), server_errors.ErrHttpForbiddenAddress},
+		{"Private", "http://10.0.0.1/posts", server_errors.ErrHttpForbiddenAddress},
+		{"CloudMetadata", "http://169.254.169.254/latest/meta-data/", server_errors.ErrHttpForbiddenAddress},
+	}
+
+	postRepository := repositories.NewPostRepository(newTestHttpClient(0))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := postRepository.GetPost(context.Background(), tt.url)
+
+			if !errors.Is(err, server_errors.ErrFactCheckPostFetchError) || !errors.Is(err, tt.expected) {
+				t.Errorf("Expected FactCheckPostFetchError caused by %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if requests != 0 {
+		t.Errorf("Expected the internal server never to be reached, got %d requests", requests)
+	}
+}
+
+func TestPostRepository_HidesTheUpstreamStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	postRepository := repositories.NewPostRepository(newTestHttpClient(0))
+	postRepository.AllowPrivateNetworks = true
+
+	_, err := postRepository.GetPost(context.Background(), server.URL)
+
+	var typed *server_errors.Error
+	if !errors.As(err, &typed) || !errors.Is(err, server_errors.ErrFactCheckPostFetchError) {
+		t.Fatalf("Expected FactCheckPostFetchError, got %v", err)
+	}
+
+	if typed.Details != "" || strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected the upstream status to stay out of the error, got %q", err.Error())
+	}
+}
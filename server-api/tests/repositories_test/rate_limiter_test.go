@@ -0,0 +1,65 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/repositories"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Burst(t *testing.T) {
+	limiter := repositories.NewRateLimiter(60, 2)
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.Allow("client"); !ok {
+			t.Fatalf("Expected request %d of the burst to be allowed", i+1)
+		}
+	}
+
+	ok, retryAfter := limiter.Allow("client")
+	if ok {
+		t.Fatal("Expected the request past the burst to be refused")
+	}
+
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("Expected to retry within the second a token takes to refill, got %v", retryAfter)
+	}
+
+	if ok, _ = limiter.Allow("other"); !ok {
+		t.Error("Expected every client to have its own bucket")
+	}
+}
+
+func TestRateLimiter_Refill(t *testing.T) {
+	// A token every 10ms
+	limiter := repositories.NewRateLimiter(6000, 1)
+
+	if ok, _ := limiter.Allow("client"); !ok {
+		t.Fatal("Expected the first request to be allowed")
+	}
+
+	if ok, _ := limiter.Allow("client"); ok {
+		t.Fatal("Expected the second request to be refused")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if ok, _ := limiter.Allow("client"); !ok {
+		t.Error("Expected the bucket to refill over time")
+	}
+}
+
+func TestRateLimiter_Peek(t *testing.T) {
+	limiter := repositories.NewRateLimiter(60, 1)
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := limiter.Peek("client"); !ok {
+			t.Fatal("Expected peeking to leave the token in the bucket")
+		}
+	}
+
+	limiter.Allow("client")
+
+	if ok, retryAfter := limiter.Peek("client"); ok || retryAfter <= 0 {
+		t.Errorf("Expected the empty bucket to be reported with a delay, got %v %v", ok, retryAfter)
+	}
+}
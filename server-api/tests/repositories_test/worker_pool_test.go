@@ -0,0 +1,81 @@
+package repositories_test
+
+import (
+	"aletheia-server/src/repositories"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool_BoundsConcurrency(t *testing.T) {
+	pool := repositories.NewWorkerPool(3)
+
+	var running, peak int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := pool.Do(context.Background(), func() {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					seen := atomic.LoadInt32(&peak)
+					if current <= seen || atomic.CompareAndSwapInt32(&peak, seen, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			})
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&peak); got != 3 {
+		t.Errorf("Expected 3 units of work at most at once, got %d", got)
+	}
+
+	if pool.InUse() != 0 {
+		t.Errorf("Expected every slot to be released, got %d in use", pool.InUse())
+	}
+}
+
+func TestWorkerPool_AcquireCancelled(t *testing.T) {
+	pool := repositories.NewWorkerPool(1)
+
+	if err := pool.Acquire(context.Background()); err != nil {
+		t.Fatalf("Expected the free slot to be taken, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	ran := false
+	err := pool.Do(ctx, func() { ran = true })
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	if ran {
+		t.Error("Expected the work not to run without a slot")
+	}
+
+	pool.Release()
+	if pool.InUse() != 0 {
+		t.Errorf("Expected the cancelled wait not to take a slot, got %d in use", pool.InUse())
+	}
+}
+
+func TestNewWorkerPool_DefaultsToOneSlot(t *testing.T) {
+	if got := repositories.NewWorkerPool(0).Size(); got != 1 {
+		t.Errorf("Expected 1 slot, got %d", got)
+	}
+}
@@ -0,0 +1,78 @@
+package usecases_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/usecases"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestNewApiKey(t *testing.T) {
+	shape := regexp.MustCompile(`^aletheia_[0-9a-f]{48}$`)
+
+	first, err := usecases.NewApiKey()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	second, _ := usecases.NewApiKey()
+
+	if !shape.MatchString(first) || !shape.MatchString(second) {
+		t.Errorf("Expected keys shaped like %s, got %q and %q", shape, first, second)
+	}
+
+	if first == second {
+		t.Error("Expected every key to be different")
+	}
+}
+
+func TestHashApiKey(t *testing.T) {
+	hash := usecases.HashApiKey("aletheia_key")
+
+	if len(hash) != 64 || hash != usecases.HashApiKey("aletheia_key") {
+		t.Errorf("Expected a stable hexadecimal SHA-256 hash, got %q", hash)
+	}
+
+	if hash == usecases.HashApiKey("aletheia_other") {
+		t.Error("Expected different keys to have different hashes")
+	}
+}
+
+// The checks below are all done before the database is reached, so no repository is needed
+
+func TestApiKeyUsecase_AuthenticateRejectsMalformedKeys(t *testing.T) {
+	usecase := usecases.NewApiKeyUsecase(nil)
+
+	tests := []struct {
+		name     string
+		key      string
+		expected error
+	}{
+		{"Missing", "", server_errors.ErrMissingApiKey},
+		{"WrongPrefix", "other_0123456789abcdef0123456789abcdef0123456789abcdef", server_errors.ErrInvalidApiKey},
+		{"TooShort", "aletheia_0123", server_errors.ErrInvalidApiKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := usecase.Authenticate(context.Background(), tt.key); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestApiKeyUsecase_IssueRejectsInvalidKeys(t *testing.T) {
+	usecase := usecases.NewApiKeyUsecase(nil)
+
+	if _, err := usecase.IssueApiKey(context.Background(), "  ", models.RoleRead); !errors.Is(err, server_errors.ErrEmptyNameError) {
+		t.Errorf("Expected EmptyNameError, got %v", err)
+	}
+
+	if _, err := usecase.IssueApiKey(context.Background(), "ci", "owner"); !errors.Is(err, server_errors.ErrInvalidApiKeyRole) {
+		t.Errorf("Expected InvalidApiKeyRole, got %v", err)
+	}
+}
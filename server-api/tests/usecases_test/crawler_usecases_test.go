@@ -0,0 +1,112 @@
+package usecases_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"aletheia-server/src/usecases"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newSlowOutlet :
+// Serves a search results page linking to a single article, which is only served once release is closed or the
+// request is aborted.
+func newSlowOutlet(t *testing.T, release chan struct{}) models.NewsOutlet {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><a class="result" href="/article">Article</a></body></html>`)
+	})
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+			fmt.Fprint(w, "<html><body><article><p>Body of the article.</p></article></body></html>")
+		case <-r.Context().Done():
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return models.NewsOutlet{Name: "slow", QueryUrl: server.URL + "/search?q=QUERY_HERE", HtmlSelector: "a.result"}
+}
+
+func startSlowCrawlJob(t *testing.T, release chan struct{}) (usecases.CrawlerUsecase, models.CrawlJob) {
+	t.Helper()
+
+	crawlerUsecase := usecases.NewCrawlerUsecase(
+		repositories.NewCrawlJobRepository(),
+		nil,
+		nil,
+		repositories.NewFetcher(nil, repositories.FetcherConfig{RequestsPerSecond: 1000, Burst: 100}),
+		nil,
+		20,
+	)
+
+	job, err := crawlerUsecase.StartCrawlJob(context.Background(), []models.NewsOutlet{newSlowOutlet(t, release)},
+		models.CrawlerInitializer{Query: "tax cuts", PagesToVisit: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return crawlerUsecase, job
+}
+
+func TestCrawlerUsecase_DrainWaitsForCrawlJobs(t *testing.T) {
+	release := make(chan struct{})
+	crawlerUsecase, job := startSlowCrawlJob(t, release)
+
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := crawlerUsecase.Drain(ctx); err != nil {
+		t.Fatalf("Expected the crawl job to be drained, got %v", err)
+	}
+
+	drained, _ := crawlerUsecase.GetCrawlJob(job.Id)
+	if drained.Status != server_errors.CrawlJobSucceeded || drained.Result == nil || len(drained.Result.Crawlers[0].Articles) != 1 {
+		t.Errorf("Expected the crawl job to finish with its article, got %+v", drained)
+	}
+}
+
+func TestCrawlerUsecase_DrainCancelsCrawlJobsPastDeadline(t *testing.T) {
+	crawlerUsecase, job := startSlowCrawlJob(t, make(chan struct{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := crawlerUsecase.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to be exceeded, got %v", err)
+	}
+
+	// Drain only returns once the cancelled crawl job halted
+	cancelled, _ := crawlerUsecase.GetCrawlJob(job.Id)
+	if cancelled.Status != server_errors.CrawlJobCancelled || cancelled.Result == nil {
+		t.Errorf("Expected the crawl job to halt as cancelled, got %+v", cancelled)
+	}
+}
+
+func TestCrawlerUsecase_RejectsCrawlJobsOnceDraining(t *testing.T) {
+	release := make(chan struct{})
+	crawlerUsecase, _ := startSlowCrawlJob(t, release)
+	close(release)
+
+	if err := crawlerUsecase.Drain(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err := crawlerUsecase.StartCrawlJob(context.Background(), []models.NewsOutlet{newSlowOutlet(t, release)},
+		models.CrawlerInitializer{Query: "tax cuts", PagesToVisit: 1})
+	if !errors.Is(err, server_errors.ErrCrawlJobsDraining) {
+		t.Errorf("Expected CrawlJobsDraining, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package usecases_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"aletheia-server/src/usecases"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newFactCheckServer emulates the post, two news outlets and the AI analyzer. Articles from the "trusted" outlet
+// confirm the post, while articles from the "tabloid" outlet contradict it.
+func newFactCheckServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/post", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><head><title>Tax cuts approved</title></head><body><p>The parliament approved new tax cuts.</p></body></html>")
+	})
+	mux.HandleFunc("/search/", func(w http.ResponseWriter, r *http.Request) {
+		outlet := strings.TrimPrefix(r.URL.Path, "/search/")
+		fmt.Fprintf(w, "<html><body><a href=\"/article/%s\">%s</a></body></html>", outlet, outlet)
+	})
+	mux.HandleFunc("/article/trusted", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body><script>ignored()</script><p>Tax cuts confirmed by the parliament.</p></body></html>")
+	})
+	mux.HandleFunc("/article/tabloid", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body><p>No tax cuts were voted.</p></body></html>")
+	})
+	mux.HandleFunc("/getLinks", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			HtmlContent string `json:"html_content"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+
+		outlet := "tabloid"
+		if strings.Contains(request.HtmlContent, "trusted") {
+			outlet = "trusted"
+		}
+
+		_ = json.NewEncoder(w).Encode([]map[string]string{
+			{"title": outlet, "url": fmt.Sprintf("%s/article/%s", server.URL, outlet)},
+		})
+	})
+	mux.HandleFunc("/analyze", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			PostContent string `json:"post_content"`
+			NewsContent string `json:"news_content"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+
+		if strings.Contains(request.NewsContent, "ignored()") {
+			t.Errorf("Scripts should not be sent to the analyzer: %q", request.NewsContent)
+		}
+
+		analysis := "The news contradicts the post."
+		if strings.Contains(request.NewsContent, "confirmed") {
+			analysis = "The post aligns with the news."
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "analysis": analysis})
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// newFactCheckUsecase builds a fact check whose crawlers get their links from the analyzer at linksUrl, while the
+// articles are analyzed by the one at analyzerUrl.
+func newFactCheckUsecase(linksUrl string, analyzerUrl string) usecases.FactCheckUsecase {
+	linksRepository := repositories.NewAnalyzerRepository(repositories.AnalyzerConfig{Url: linksUrl}, nil)
+	crawlerUsecase := usecases.NewCrawlerUsecase(
+		repositories.NewCrawlJobRepository(),
+		nil,
+		nil,
+		repositories.NewFetcher(nil, repositories.FetcherConfig{RequestsPerSecond: 1000, Burst: 100}),
+		&linksRepository,
+		20,
+	)
+	// The posts are served by httptest, on the loopback address
+	postRepository := repositories.NewPostRepository(nil)
+	postRepository.AllowPrivateNetworks = true
+	return usecases.NewFactCheckUsecase(
+		crawlerUsecase,
+		postRepository,
+		repositories.NewAnalyzerRepository(repositories.AnalyzerConfig{Url: analyzerUrl}, nil),
+		nil,
+	)
+}
+
+func TestFactCheck_WeightsVerdictByCredibility(t *testing.T) {
+	server := newFactCheckServer(t)
+
+	newsOutlets := []models.NewsOutlet{
+		{Name: "trusted", QueryUrl: server.URL + "/search/trusted?q=QUERY_HERE", Credibility: 90},
+		{Name: "tabloid", QueryUrl: server.URL + "/search/tabloid?q=QUERY_HERE", Credibility: 10},
+	}
+
+	factCheckUsecase := newFactCheckUsecase(server.URL, server.URL)
+	result, err := factCheckUsecase.FactCheck(context.Background(), models.PackageReceived{Url: server.URL + "/post"}, newsOutlets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Query != "Tax cuts approved" {
+		t.Errorf("Expected the title of the post to be used as query, got %q", result.Query)
+	}
+
+	if len(result.Analyses) != 2 {
+		t.Fatalf("Expected 2 analyses, got %d: %+v", len(result.Analyses), result.Analyses)
+	}
+
+	for _, analysis := range result.Analyses {
+		expected := models.StanceContradicts
+		if analysis.NewsOutlet == "trusted" {
+			expected = models.StanceSupports
+		}
+
+		if analysis.Stance != expected {
+			t.Errorf("Expected %s to have stance %q, got %q", analysis.NewsOutlet, expected, analysis.Stance)
+		}
+	}
+
+	if result.Verdict != models.VerdictLikelyTrue {
+		t.Errorf("Expected verdict %q, got %q (score %f)", models.VerdictLikelyTrue, result.Verdict, result.Score)
+	}
+
+	if result.Score < 0.79 || result.Score > 0.81 {
+		t.Errorf("Expected score 0.8, got %f", result.Score)
+	}
+}
+
+func TestFactCheck_AnalyzerFailureIsUnverified(t *testing.T) {
+	server := newFactCheckServer(t)
+
+	newsOutlets := []models.NewsOutlet{
+		{Name: "trusted", QueryUrl: server.URL + "/search/trusted?q=QUERY_HERE", Credibility: 90},
+	}
+
+	// The analyzer used for "/analyze" is unreachable, so every analysis fails
+	factCheckUsecase := newFactCheckUsecase(server.URL, "http://127.0.0.1:1")
+	result, err := factCheckUsecase.FactCheck(context.Background(), models.PackageReceived{Url: server.URL + "/post"}, newsOutlets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Verdict != models.VerdictUnverified {
+		t.Errorf("Expected verdict %q, got %q", models.VerdictUnverified, result.Verdict)
+	}
+
+	if len(result.Analyses) != 1 || result.Analyses[0].Error == "" {
+		t.Errorf("Expected the analyzer failure to be reported, got %+v", result.Analyses)
+	}
+}
+
+func TestFactCheck_EmptyUrl(t *testing.T) {
+	factCheckUsecase := newFactCheckUsecase("http://127.0.0.1:1", "http://127.0.0.1:1")
+
+	_, err := factCheckUsecase.FactCheck(context.Background(), models.PackageReceived{Url: "  "}, nil)
+	if err == nil || err.Error() != server_errors.FactCheckEmptyUrl {
+		t.Errorf("Expected %q, got %v", server_errors.FactCheckEmptyUrl, err)
+	}
+}
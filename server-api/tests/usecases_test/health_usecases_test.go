@@ -0,0 +1,71 @@
+package usecases_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/usecases"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthUsecase_Liveness(t *testing.T) {
+	usecase := usecases.NewHealthUsecase(0, usecases.HealthCheck{Name: "database", Check: func(ctx context.Context) error {
+		t.Error("Expected the liveness check to skip the dependencies")
+		return nil
+	}})
+
+	health := usecase.Liveness()
+
+	if health.Status != server_errors.HealthUp || len(health.Dependencies) != 0 {
+		t.Errorf("Unexpected liveness %+v", health)
+	}
+}
+
+func TestHealthUsecase_Readiness(t *testing.T) {
+	up := usecases.HealthCheck{Name: "database", Check: func(ctx context.Context) error { return nil }}
+	down := usecases.HealthCheck{Name: "analyzer", Check: func(ctx context.Context) error {
+		return server_errors.ErrAnalyzerUnreachable.Wrap(errors.New("connection refused"))
+	}}
+	hanging := usecases.HealthCheck{Name: "analyzer", Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	tests := []struct {
+		name     string
+		checks   []usecases.HealthCheck
+		expected []string
+	}{
+		{"AllUp", []usecases.HealthCheck{up}, []string{server_errors.HealthUp, server_errors.HealthUp}},
+		{"OneDown", []usecases.HealthCheck{up, down}, []string{server_errors.HealthDown, server_errors.HealthUp, server_errors.HealthDown}},
+		{"TimedOut", []usecases.HealthCheck{hanging}, []string{server_errors.HealthDown, server_errors.HealthDown}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := usecases.NewHealthUsecase(50*time.Millisecond, tt.checks...)
+
+			start := time.Now()
+			health := usecase.Readiness(context.Background())
+
+			if time.Since(start) > time.Second {
+				t.Errorf("Expected the checks to be bounded by their timeout, took %s", time.Since(start))
+			}
+
+			if health.Status != tt.expected[0] || len(health.Dependencies) != len(tt.checks) {
+				t.Fatalf("Unexpected readiness %+v", health)
+			}
+
+			for i, dependency := range health.Dependencies {
+				if dependency.Name != tt.checks[i].Name || dependency.Status != tt.expected[i+1] {
+					t.Errorf("Unexpected dependency %+v", dependency)
+				}
+
+				if (dependency.Status == server_errors.HealthDown) != (dependency.Error != "") {
+					t.Errorf("Expected only the dependencies that are down to carry an error, got %+v", dependency)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package usecases_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"aletheia-server/src/usecases"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLanguageUsecase_Rename(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	usecase := usecases.NewLanguageUsecase(repositories.NewMemoryLanguageRepository(store))
+
+	if _, err := usecase.AddLanguage(ctx, models.Language{Name: "english"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	english, err := usecase.GetLanguageByName(ctx, "english")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err = usecase.RenameLanguage(ctx, english.Id, models.Language{Name: "   "}); !errors.Is(err, server_errors.ErrLanguageEmptyName) {
+		t.Errorf("Expected LanguageEmptyName, got %v", err)
+	}
+
+	renamed, err := usecase.RenameLanguage(ctx, english.Id, models.Language{Name: "  British "})
+	if err != nil || renamed.Id != english.Id || renamed.Name != "british" {
+		t.Errorf("Expected the trimmed and lower-cased name to be returned, got %+v, %v", renamed, err)
+	}
+
+	if _, err = usecase.RenameLanguage(ctx, english.Id+1, models.Language{Name: "french"}); !errors.Is(err, server_errors.ErrLanguageNotFound) {
+		t.Errorf("Expected LanguageNotFound, got %v", err)
+	}
+}
+
+func TestLanguageUsecase_Delete(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	languageRepository := repositories.NewMemoryLanguageRepository(store)
+	usecase := usecases.NewLanguageUsecase(languageRepository)
+	newsOutletUsecase := usecases.NewNewsOutletUsecase(repositories.NewMemoryNewsOutletRepository(store))
+
+	id, err := languageRepository.AddLanguage(ctx, models.Language{Name: "english"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err = newsOutletUsecase.AddNewsOutlet(ctx, models.NewsOutlet{
+		Name:     "reuters",
+		QueryUrl: "https://www.reuters.com/site-search/?query=" + models.QueryPlaceholder,
+		Language: "english",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err = usecase.DeleteLanguage(ctx, id, false); !errors.Is(err, server_errors.ErrLanguageInUse) {
+		t.Errorf("Expected LanguageInUse, got %v", err)
+	}
+
+	if err = usecase.DeleteLanguage(ctx, id, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	newsOutlets, err := newsOutletUsecase.GetNewsOutlets(ctx)
+	if err != nil || len(newsOutlets) != 0 {
+		t.Errorf("Expected the news outlets to be removed along with their language, got %+v, %v", newsOutlets, err)
+	}
+}
@@ -0,0 +1,93 @@
+package usecases_test
+
+import (
+	"aletheia-server/src/errors"
+	"aletheia-server/src/models"
+	"aletheia-server/src/repositories"
+	"aletheia-server/src/usecases"
+	"context"
+	"errors"
+	"testing"
+)
+
+// newNewsOutletUsecase :
+// Returns a NewsOutletUseCase backed by memory, in which the english and french languages exist.
+func newNewsOutletUsecase(t *testing.T) usecases.NewsOutletUseCase {
+	t.Helper()
+
+	store := repositories.NewMemoryStore()
+	languageRepository := repositories.NewMemoryLanguageRepository(store)
+
+	for _, name := range []string{"english", "french"} {
+		if _, err := languageRepository.AddLanguage(context.Background(), models.Language{Name: name}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	return usecases.NewNewsOutletUsecase(repositories.NewMemoryNewsOutletRepository(store))
+}
+
+func TestNewsOutletUsecase_Add(t *testing.T) {
+	ctx := context.Background()
+	usecase := newNewsOutletUsecase(t)
+	queryUrl := "https://www.reuters.com/site-search/?query=" + models.QueryPlaceholder
+
+	tests := []struct {
+		name       string
+		newsOutlet models.NewsOutlet
+		expected   error
+	}{
+		{"empty name", models.NewsOutlet{Name: " ", QueryUrl: queryUrl, Language: "english"}, server_errors.ErrNewsOutletEmptyName},
+		{"missing placeholder", models.NewsOutlet{Name: "reuters", QueryUrl: "https://www.reuters.com", Language: "english"}, server_errors.ErrNewsOutletMissingQueryHere},
+		{"negative crawl delay", models.NewsOutlet{Name: "reuters", QueryUrl: queryUrl, Language: "english", CrawlDelayMs: -1}, server_errors.ErrNewsOutletNegativeCrawlDelay},
+		{"unknown language", models.NewsOutlet{Name: "reuters", QueryUrl: queryUrl, Language: "klingon"}, server_errors.ErrNewsOutletUnknownLanguage},
+		{"valid", models.NewsOutlet{Name: "Reuters", QueryUrl: queryUrl, Language: "english"}, nil},
+		{"duplicate", models.NewsOutlet{Name: "REUTERS", QueryUrl: queryUrl, Language: "french"}, server_errors.ErrNewsOutletAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := usecase.AddNewsOutlet(ctx, tt.newsOutlet); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestNewsOutletUsecase_Patch(t *testing.T) {
+	ctx := context.Background()
+	usecase := newNewsOutletUsecase(t)
+
+	_, err := usecase.AddNewsOutlet(ctx, models.NewsOutlet{
+		Name:        "reuters",
+		QueryUrl:    "https://www.reuters.com/site-search/?query=" + models.QueryPlaceholder,
+		Language:    "english",
+		Credibility: 90,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reuters, err := usecase.GetNewsOutletByName(ctx, "Reuters")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err = usecase.PatchNewsOutlet(ctx, reuters.Id, models.NewsOutletPatch{}); !errors.Is(err, server_errors.ErrNewsOutletEmptyPatch) {
+		t.Errorf("Expected NewsOutletEmptyPatch, got %v", err)
+	}
+
+	language := "French"
+	patched, err := usecase.PatchNewsOutlet(ctx, reuters.Id, models.NewsOutletPatch{Language: &language})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if patched.Language != "french" || patched.Credibility != 90 || patched.QueryUrl != reuters.QueryUrl {
+		t.Errorf("Expected only the language to change, got %+v", patched)
+	}
+
+	if _, err = usecase.PatchNewsOutlet(ctx, reuters.Id+1, models.NewsOutletPatch{Language: &language}); !errors.Is(err, server_errors.ErrNewsOutletNotFound) {
+		t.Errorf("Expected NewsOutletNotFound, got %v", err)
+	}
+}
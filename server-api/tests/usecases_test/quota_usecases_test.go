@@ -0,0 +1,27 @@
+package usecases_test
+
+import (
+	"aletheia-server/src/usecases"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQuotaUsecase_Unlimited(t *testing.T) {
+	// Without a daily limit, the database is never reached
+	usecase := usecases.NewQuotaUsecase(nil, 0)
+
+	quota, err := usecase.ConsumeCrawlQuota(context.Background(), "ip:192.0.2.1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if quota.Limit != 0 {
+		t.Errorf("Expected no limit, got %+v", quota)
+	}
+
+	resetsIn := time.Until(quota.ResetsAt)
+	if resetsIn <= 0 || resetsIn > 24*time.Hour || quota.ResetsAt.Location() != time.UTC || quota.ResetsAt.Hour() != 0 {
+		t.Errorf("Expected the quota to reset at the next UTC midnight, got %v", quota.ResetsAt)
+	}
+}